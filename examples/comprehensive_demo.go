@@ -224,7 +224,7 @@ func demoEnvGroup(ctx context.Context, client types.Client) {
 
 	// Test math task
 	mathPrompt := mathEnv.FormatPrompt("What is 25 * 4?")
-	rollout, err := group.Rollout(ctx, client, "gpt-4", mathPrompt, "math:100", types.SamplingArgs{})
+	rollout, err := group.RolloutForTask(ctx, client, "gpt-4", "math", mathPrompt, "100", types.SamplingArgs{})
 	if err != nil {
 		log.Printf("EnvGroup math failed: %v", err)
 		return
@@ -233,7 +233,7 @@ func demoEnvGroup(ctx context.Context, client types.Client) {
 
 	// Test trivia task
 	triviaPrompt := triviaEnv.FormatPrompt("Who wrote Romeo and Juliet?")
-	rollout, err = group.Rollout(ctx, client, "gpt-4", triviaPrompt, "trivia:Shakespeare", types.SamplingArgs{})
+	rollout, err = group.RolloutForTask(ctx, client, "gpt-4", "trivia", triviaPrompt, "Shakespeare", types.SamplingArgs{})
 	if err != nil {
 		log.Printf("EnvGroup trivia failed: %v", err)
 		return
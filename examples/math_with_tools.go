@@ -226,12 +226,7 @@ Your conclusion or next question`,
 	rubric := rubrics.NewMultiMetricRubric()
 	
 	// Add think format checker
-	thinkFormatFunc := func(ctx context.Context, response, groundTruth string) (float64, error) {
-		if thinkParser.FollowsFormat(response) {
-			return 1.0, nil
-		}
-		return 0.0, nil
-	}
+	thinkFormatFunc := rubrics.NewFormatReward(thinkParser)
 	
 	// Add answer checker
 	answerFunc := func(ctx context.Context, response, groundTruth string) (float64, error) {
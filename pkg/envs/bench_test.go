@@ -0,0 +1,39 @@
+package envs
+
+// Dataset sampling benchmarks. Run with:
+//
+//	go test -run xxx -bench Sample -benchmem ./pkg/envs
+//
+// Sampling 100 of 100000 items (Intel Xeon, go1.23), before and after GetDataset
+// selected from a shuffled index permutation instead of a shuffled copy:
+//
+//	BenchmarkSampleDataset/shuffle_copy       1733859 ns/op   1646496 B/op   207 allocs/op
+//	BenchmarkSampleDataset/shuffle_indices     973915 ns/op    842736 B/op   204 allocs/op
+
+import (
+	"testing"
+
+	"github.com/rizome-dev/go-verifiers/pkg/types"
+)
+
+func BenchmarkSampleDataset(b *testing.B) {
+	data := make([]map[string]interface{}, 100000)
+	for i := range data {
+		data[i] = map[string]interface{}{"question": "q", "answer": i}
+	}
+	dataset := types.NewSimpleDataset(data)
+
+	b.Run("shuffle_copy", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			dataset.Shuffle(int64(i)).Select(makeRange(100))
+		}
+	})
+
+	b.Run("shuffle_indices", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			sampleDataset(dataset, 100, int64(i))
+		}
+	})
+}
@@ -8,7 +8,9 @@ import (
 	"strings"
 
 	"github.com/Knetic/govaluate"
+	"github.com/rizome-dev/go-verifiers/pkg/mathexpr"
 	"github.com/rizome-dev/go-verifiers/pkg/parsers"
+	"github.com/rizome-dev/go-verifiers/pkg/prompts"
 	"github.com/rizome-dev/go-verifiers/pkg/rubrics"
 	"github.com/rizome-dev/go-verifiers/pkg/types"
 )
@@ -53,6 +55,13 @@ The system will evaluate your mathematical expressions.`
 		return nil, err
 	}
 
+	config.SystemPrompt, err = prompts.Render(config.SystemPrompt, map[string]string{
+		"format": parser.GetFormatStr(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("system prompt: %w", err)
+	}
+
 	env := &CodeMathEnv{
 		MultiTurnEnv: NewMultiTurnEnv(config, maxTurns),
 		Parser:       parser,
@@ -72,7 +81,7 @@ The system will evaluate your mathematical expressions.`
 }
 
 // IsCompleted checks if the problem is solved
-func (e *CodeMathEnv) IsCompleted(ctx context.Context, messages []types.Message, state map[string]interface{}) bool {
+func (e *CodeMathEnv) IsCompleted(ctx context.Context, messages []types.Message, state *types.State) bool {
 	if len(messages) == 0 {
 		return false
 	}
@@ -91,7 +100,7 @@ func (e *CodeMathEnv) IsCompleted(ctx context.Context, messages []types.Message,
 }
 
 // EnvResponse evaluates mathematical expressions and provides feedback
-func (e *CodeMathEnv) EnvResponse(ctx context.Context, messages []types.Message, state map[string]interface{}) (types.Message, map[string]interface{}, error) {
+func (e *CodeMathEnv) EnvResponse(ctx context.Context, messages []types.Message, state *types.State) (types.Message, *types.State, error) {
 	if len(messages) == 0 {
 		return types.Message{}, state, fmt.Errorf("no messages to process")
 	}
@@ -132,17 +141,7 @@ func (e *CodeMathEnv) EnvResponse(ctx context.Context, messages []types.Message,
 	}
 
 	// Track evaluations in state
-	if state["code_executions"] == nil {
-		state["code_executions"] = []map[string]interface{}{}
-	}
-	
-	executions := state["code_executions"].([]map[string]interface{})
-	executions = append(executions, map[string]interface{}{
-		"code":    code,
-		"output":  output,
-		"success": success,
-	})
-	state["code_executions"] = executions
+	recordCodeExecution(state, code, output, success)
 
 	return types.Message{
 		Role:    "user",
@@ -150,37 +149,23 @@ func (e *CodeMathEnv) EnvResponse(ctx context.Context, messages []types.Message,
 	}, state, nil
 }
 
+// recordCodeExecution appends an evaluation to state["code_executions"]
+func recordCodeExecution(state *types.State, code, output string, success bool) {
+	state.Append("code_executions", map[string]interface{}{
+		"code":    code,
+		"output":  output,
+		"success": success,
+	})
+}
+
 // evaluateExpressions evaluates mathematical expressions line by line
 func (e *CodeMathEnv) evaluateExpressions(ctx context.Context, code string) (string, bool) {
 	lines := strings.Split(code, "\n")
 	var results []string
 	success := true
 
-	// Mathematical functions and constants
-	parameters := map[string]interface{}{
-		"pi":    math.Pi,
-		"e":     math.E,
-		"sqrt":  sqrt,
-		"sin":   sin,
-		"cos":   cos,
-		"tan":   tan,
-		"log":   log,
-		"ln":    ln,
-		"exp":   exp,
-		"pow":   pow,
-		"abs":   abs,
-		"ceil":  ceil,
-		"floor": floor,
-		"round": round,
-		"max":   max,
-		"min":   min,
-	}
-
-	// Variables to store results
-	variables := make(map[string]interface{})
-	for k, v := range parameters {
-		variables[k] = v
-	}
+	// Variables to store results, starting with the mathematical constants
+	variables := mathexpr.Constants()
 
 	for _, line := range lines {
 		line = strings.TrimSpace(line)
@@ -189,25 +174,20 @@ func (e *CodeMathEnv) evaluateExpressions(ctx context.Context, code string) (str
 		}
 
 		// Handle variable assignments (e.g., "x = 5")
-		if strings.Contains(line, "=") && !strings.Contains(line, "==") {
-			parts := strings.SplitN(line, "=", 2)
-			if len(parts) == 2 {
-				varName := strings.TrimSpace(parts[0])
-				expr := strings.TrimSpace(parts[1])
-
-				// Evaluate the expression
-				result, err := evaluateExpression(expr, variables)
-				if err != nil {
-					results = append(results, fmt.Sprintf("Error in '%s': %v", line, err))
-					success = false
-					continue
-				}
-
-				// Store the variable
-				variables[varName] = result
-				results = append(results, fmt.Sprintf("%s = %v", varName, formatResult(result)))
+		if varName, expr, ok := splitAssignment(line); ok {
+			// Evaluate the expression
+			result, err := evaluateExpression(expr, variables)
+			if err != nil {
+				results = append(results, fmt.Sprintf("Error in '%s': %v", line, err))
+				success = false
 				continue
 			}
+
+			// Store the variable as evaluated, so later arithmetic accepts it
+			exact := mathexpr.ExactResult(mathexpr.Preprocess(expr), variables, result)
+			variables[varName] = result
+			results = append(results, fmt.Sprintf("%s = %v", varName, formatResult(exact)))
+			continue
 		}
 
 		// Evaluate standalone expressions
@@ -218,19 +198,52 @@ func (e *CodeMathEnv) evaluateExpressions(ctx context.Context, code string) (str
 			continue
 		}
 
-		results = append(results, fmt.Sprintf("%s = %v", line, formatResult(result)))
+		results = append(results, fmt.Sprintf("%s = %v", line, formatResult(mathexpr.ExactResult(mathexpr.Preprocess(line), variables, result))))
 	}
 
 	return strings.Join(results, "\n"), success
 }
 
+// splitAssignment splits "name = expr" into its parts. A line is an assignment only
+// when it contains exactly one "=" that is not part of ==, >=, <= or !=.
+func splitAssignment(line string) (string, string, bool) {
+	index := -1
+	for i := 0; i < len(line); i++ {
+		if line[i] != '=' {
+			continue
+		}
+		if i+1 < len(line) && line[i+1] == '=' {
+			i++ // Skip the second character of ==
+			continue
+		}
+		if i > 0 && strings.ContainsRune("=<>!", rune(line[i-1])) {
+			continue
+		}
+		if index >= 0 {
+			return "", "", false
+		}
+		index = i
+	}
+
+	if index < 0 {
+		return "", "", false
+	}
+
+	varName := strings.TrimSpace(line[:index])
+	expr := strings.TrimSpace(line[index+1:])
+	if varName == "" || expr == "" {
+		return "", "", false
+	}
+	return varName, expr, true
+}
+
 // evaluateExpression evaluates a single mathematical expression
 func evaluateExpression(expr string, variables map[string]interface{}) (interface{}, error) {
 	// Preprocess the expression
-	expr = preprocessExpression(expr)
+	expr = mathexpr.Preprocess(expr)
 
 	// Create and evaluate expression
-	expression, err := govaluate.NewEvaluableExpression(expr)
+	expression, err := govaluate.NewEvaluableExpressionWithFunctions(expr, mathFunctions)
 	if err != nil {
 		return nil, err
 	}
@@ -239,33 +252,22 @@ func evaluateExpression(expr string, variables map[string]interface{}) (interfac
 	if err != nil {
 		return nil, err
 	}
+	if err := mathexpr.CheckFinite(result); err != nil {
+		return nil, err
+	}
 
 	return result, nil
 }
 
-// preprocessExpression handles common mathematical notation
-func preprocessExpression(expr string) string {
-	// Replace common mathematical notation
-	expr = strings.ReplaceAll(expr, "π", "pi")
-	expr = strings.ReplaceAll(expr, "×", "*")
-	expr = strings.ReplaceAll(expr, "÷", "/")
-	expr = strings.ReplaceAll(expr, "²", "^2")
-	expr = strings.ReplaceAll(expr, "³", "^3")
-	
-	// Handle implicit multiplication (e.g., 2pi -> 2*pi)
-	// Simple cases only
-	expr = strings.ReplaceAll(expr, "2pi", "2*pi")
-	expr = strings.ReplaceAll(expr, "2e", "2*e")
-	
-	return expr
-}
+// mathFunctions are the functions available in evaluated expressions
+var mathFunctions = mathexpr.DefaultFunctions()
 
 // formatResult formats a result for display
 func formatResult(result interface{}) string {
 	switch v := result.(type) {
 	case float64:
 		// Format nicely, removing unnecessary decimals
-		if v == float64(int64(v)) {
+		if v == math.Trunc(v) && v >= math.MinInt64 && v < math.MaxInt64 {
 			return strconv.FormatInt(int64(v), 10)
 		}
 		return strconv.FormatFloat(v, 'f', -1, 64)
@@ -278,204 +280,56 @@ func formatResult(result interface{}) string {
 	}
 }
 
-// Mathematical function wrappers (reuse from calculator.go)
-func sqrt(args ...interface{}) (interface{}, error) {
-	if len(args) != 1 {
-		return nil, fmt.Errorf("sqrt requires exactly 1 argument")
-	}
-	val, err := toFloat64(args[0])
-	if err != nil {
-		return nil, err
-	}
-	return math.Sqrt(val), nil
-}
-
-func sin(args ...interface{}) (interface{}, error) {
-	if len(args) != 1 {
-		return nil, fmt.Errorf("sin requires exactly 1 argument")
-	}
-	val, err := toFloat64(args[0])
-	if err != nil {
-		return nil, err
-	}
-	return math.Sin(val), nil
-}
-
-func cos(args ...interface{}) (interface{}, error) {
-	if len(args) != 1 {
-		return nil, fmt.Errorf("cos requires exactly 1 argument")
-	}
-	val, err := toFloat64(args[0])
-	if err != nil {
-		return nil, err
-	}
-	return math.Cos(val), nil
-}
-
-func tan(args ...interface{}) (interface{}, error) {
-	if len(args) != 1 {
-		return nil, fmt.Errorf("tan requires exactly 1 argument")
-	}
-	val, err := toFloat64(args[0])
-	if err != nil {
-		return nil, err
-	}
-	return math.Tan(val), nil
-}
-
-func log(args ...interface{}) (interface{}, error) {
-	if len(args) != 1 {
-		return nil, fmt.Errorf("log requires exactly 1 argument")
-	}
-	val, err := toFloat64(args[0])
-	if err != nil {
-		return nil, err
-	}
-	return math.Log10(val), nil
-}
-
-func ln(args ...interface{}) (interface{}, error) {
-	if len(args) != 1 {
-		return nil, fmt.Errorf("ln requires exactly 1 argument")
-	}
-	val, err := toFloat64(args[0])
-	if err != nil {
-		return nil, err
-	}
-	return math.Log(val), nil
-}
-
-func exp(args ...interface{}) (interface{}, error) {
-	if len(args) != 1 {
-		return nil, fmt.Errorf("exp requires exactly 1 argument")
-	}
-	val, err := toFloat64(args[0])
-	if err != nil {
-		return nil, err
-	}
-	return math.Exp(val), nil
-}
-
-func pow(args ...interface{}) (interface{}, error) {
-	if len(args) != 2 {
-		return nil, fmt.Errorf("pow requires exactly 2 arguments")
-	}
-	base, err := toFloat64(args[0])
-	if err != nil {
-		return nil, err
-	}
-	exponent, err := toFloat64(args[1])
+// Rollout performs the code-math environment rollout
+func (e *CodeMathEnv) Rollout(ctx context.Context, client types.Client, model string, prompt interface{}, answer string, samplingArgs types.SamplingArgs) (*types.Rollout, error) {
+	rollout, err := BaseMultiTurnRollout(ctx, e, client, model, prompt, answer, samplingArgs, e.MaxTurns)
 	if err != nil {
 		return nil, err
 	}
-	return math.Pow(base, exponent), nil
-}
 
-func abs(args ...interface{}) (interface{}, error) {
-	if len(args) != 1 {
-		return nil, fmt.Errorf("abs requires exactly 1 argument")
-	}
-	val, err := toFloat64(args[0])
-	if err != nil {
-		return nil, err
+	parser, rubric := e.parserAndRubric()
+	if rubric == nil || rollout.Error != nil {
+		e.logRollout(ctx, rollout)
+		return rollout, nil
 	}
-	return math.Abs(val), nil
-}
 
-func ceil(args ...interface{}) (interface{}, error) {
-	if len(args) != 1 {
-		return nil, fmt.Errorf("ceil requires exactly 1 argument")
-	}
-	val, err := toFloat64(args[0])
-	if err != nil {
-		return nil, err
+	// The rollout stops as soon as an answer appears, so code in the final
+	// message has not been evaluated yet
+	final, err := e.Parser.ParseXML(rollout.Response, true)
+	if err == nil && final.Fields["code"] != "" {
+		output, success := e.evaluateExpressions(ctx, final.Fields["code"])
+		state := types.NewState(rollout.State)
+		recordCodeExecution(state, final.Fields["code"], output, success)
+		rollout.State = state.Map()
 	}
-	return math.Ceil(val), nil
-}
 
-func floor(args ...interface{}) (interface{}, error) {
-	if len(args) != 1 {
-		return nil, fmt.Errorf("floor requires exactly 1 argument")
-	}
-	val, err := toFloat64(args[0])
-	if err != nil {
-		return nil, err
+	// Extract the final answer
+	parsed := rollout.Response
+	if parser != nil {
+		answerText, err := parser.Parse(ctx, rollout.Response)
+		if err == nil && answerText != "" {
+			parsed = answerText
+		}
 	}
-	return math.Floor(val), nil
-}
 
-func round(args ...interface{}) (interface{}, error) {
-	if len(args) != 1 {
-		return nil, fmt.Errorf("round requires exactly 1 argument")
-	}
-	val, err := toFloat64(args[0])
-	if err != nil {
-		return nil, err
-	}
-	return math.Round(val), nil
-}
+	ctx = rubrics.WithRawResponse(ctx, rollout.Response)
 
-func max(args ...interface{}) (interface{}, error) {
-	if len(args) < 1 {
-		return nil, fmt.Errorf("max requires at least 1 argument")
-	}
-	maxVal, err := toFloat64(args[0])
-	if err != nil {
-		return nil, err
-	}
-	for i := 1; i < len(args); i++ {
-		val, err := toFloat64(args[i])
-		if err != nil {
-			return nil, err
-		}
-		if val > maxVal {
-			maxVal = val
+	var score float64
+	var metrics map[string]float64
+	if codeMathRubric, ok := rubric.(*rubrics.CodeMathRubric); ok {
+		score, metrics, err = codeMathRubric.ComputeRewardWithStateBreakdown(ctx, parsed, answer, rollout.State)
+	} else {
+		score, metrics, err = computeReward(ctx, rubric, parsed, answer)
+		if executionScore, ok := rubrics.CodeExecutionScore(rollout.State); ok && metrics != nil {
+			metrics["code_execution"] = executionScore
 		}
 	}
-	return maxVal, nil
-}
-
-func min(args ...interface{}) (interface{}, error) {
-	if len(args) < 1 {
-		return nil, fmt.Errorf("min requires at least 1 argument")
-	}
-	minVal, err := toFloat64(args[0])
 	if err != nil {
-		return nil, err
-	}
-	for i := 1; i < len(args); i++ {
-		val, err := toFloat64(args[i])
-		if err != nil {
-			return nil, err
-		}
-		if val < minVal {
-			minVal = val
-		}
-	}
-	return minVal, nil
-}
-
-// toFloat64 converts an interface to float64
-func toFloat64(val interface{}) (float64, error) {
-	switch v := val.(type) {
-	case float64:
-		return v, nil
-	case float32:
-		return float64(v), nil
-	case int:
-		return float64(v), nil
-	case int64:
-		return float64(v), nil
-	case int32:
-		return float64(v), nil
-	case string:
-		return strconv.ParseFloat(v, 64)
-	default:
-		return 0, fmt.Errorf("cannot convert %T to float64", val)
+		return nil, fmt.Errorf("failed to compute reward: %w", err)
 	}
-}
+	rollout.Score = score
+	rollout.Metrics = metrics
 
-// Rollout performs the code-math environment rollout
-func (e *CodeMathEnv) Rollout(ctx context.Context, client types.Client, model string, prompt interface{}, answer string, samplingArgs types.SamplingArgs) (*types.Rollout, error) {
-	return BaseMultiTurnRollout(ctx, e, client, model, prompt, answer, samplingArgs, e.MaxTurns)
+	e.logRollout(ctx, rollout)
+	return rollout, nil
 }
\ No newline at end of file
@@ -0,0 +1,163 @@
+package envs
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/rizome-dev/go-verifiers/pkg/tools"
+	"github.com/rizome-dev/go-verifiers/pkg/types"
+)
+
+func TestCodeMathEnv_RolloutScoresAnswer(t *testing.T) {
+	config := types.Config{
+		Model:       "test-model",
+		MessageType: "chat",
+	}
+
+	env, err := NewCodeMathEnv(config, 3)
+	if err != nil {
+		t.Fatalf("NewCodeMathEnv failed: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		response string
+		answer   string
+		expected float64
+	}{
+		{
+			name:     "correct arithmetic",
+			response: "<reasoning>\nAdd the numbers\n</reasoning>\n<code>\nx = 17 + 25\n</code>\n<answer>\n42\n</answer>",
+			answer:   "42",
+			expected: 1.0,
+		},
+		{
+			name:     "wrong answer",
+			response: "<reasoning>\nAdd the numbers\n</reasoning>\n<code>\nx = 17 + 25\n</code>\n<answer>\n41\n</answer>",
+			answer:   "42",
+			expected: 0.3,
+		},
+	}
+
+	ctx := context.Background()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := &MockClient{Response: tt.response}
+			prompt := env.FormatPrompt("What is 17 + 25?")
+
+			rollout, err := env.Rollout(ctx, mockClient, config.Model, prompt, tt.answer, config.SamplingArgs)
+			if err != nil {
+				t.Fatalf("Rollout failed: %v", err)
+			}
+
+			if rollout.Score < tt.expected-1e-9 || rollout.Score > tt.expected+1e-9 {
+				t.Errorf("Score = %v, want %v", rollout.Score, tt.expected)
+			}
+			if executions, _ := rollout.State["code_executions"].([]map[string]interface{}); len(executions) != 1 {
+				t.Errorf("Expected one code execution in state, got %v", rollout.State["code_executions"])
+			}
+			if rollout.Metrics["code_execution"] != 1.0 {
+				t.Errorf("code_execution = %v, want 1.0", rollout.Metrics["code_execution"])
+			}
+		})
+	}
+}
+
+func TestCodeMathEnv_EvaluateIntegerFunctions(t *testing.T) {
+	env, err := NewCodeMathEnv(types.Config{}, 3)
+	if err != nil {
+		t.Fatalf("NewCodeMathEnv failed: %v", err)
+	}
+
+	code := "a = factorial(5)\nb = gcd(12, 18)\nc = 17 mod 5"
+	output, success := env.evaluateExpressions(context.Background(), code)
+	if !success {
+		t.Fatalf("Evaluation failed: %s", output)
+	}
+
+	expected := "a = 120\nb = 6\nc = 2"
+	if output != expected {
+		t.Errorf("evaluateExpressions() = %q, want %q", output, expected)
+	}
+}
+
+func TestCodeMathEnv_EvaluateMathErrors(t *testing.T) {
+	env, err := NewCodeMathEnv(types.Config{}, 3)
+	if err != nil {
+		t.Fatalf("NewCodeMathEnv failed: %v", err)
+	}
+
+	output, success := env.evaluateExpressions(context.Background(), "1/0\nsqrt(-4)\nlog(0)")
+	if success {
+		t.Fatalf("Expected evaluation to fail, got %q", output)
+	}
+
+	expected := "Error in '1/0': division by zero or overflow\n" +
+		"Error in 'sqrt(-4)': math domain error (sqrt of negative)\n" +
+		"Error in 'log(0)': math domain error (log of zero)"
+	if output != expected {
+		t.Errorf("evaluateExpressions() = %q, want %q", output, expected)
+	}
+}
+
+func TestCodeMathEnv_EvaluateComparisons(t *testing.T) {
+	env, err := NewCodeMathEnv(types.Config{}, 3)
+	if err != nil {
+		t.Fatalf("NewCodeMathEnv failed: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		code     string
+		expected string
+	}{
+		{name: "greater or equal", code: "x = 7\nx >= 5", expected: "x = 7\nx >= 5 = true"},
+		{name: "assignment", code: "y = 3", expected: "y = 3"},
+		{name: "equality", code: "z = 3\nz == 3", expected: "z = 3\nz == 3 = true"},
+		{name: "not equal", code: "z = 3\nz != 3", expected: "z = 3\nz != 3 = false"},
+		{name: "assigned comparison", code: "b = 2 <= 1", expected: "b = false"},
+		{name: "large integer in arithmetic", code: "p = pow(3, 39)\np + 1\nfactorial(20) + 1", expected: "p = 4052555153018976267\np + 1 = 4052555153018976256\nfactorial(20) + 1 = 2432902008176640000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, success := env.evaluateExpressions(context.Background(), tt.code)
+			if !success {
+				t.Fatalf("Evaluation failed: %s", output)
+			}
+			if output != tt.expected {
+				t.Errorf("evaluateExpressions() = %q, want %q", output, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCodeMathEnv_MatchesCalculator(t *testing.T) {
+	env, err := NewCodeMathEnv(types.Config{}, 3)
+	if err != nil {
+		t.Fatalf("NewCodeMathEnv failed: %v", err)
+	}
+	calc := tools.NewCalculator()
+
+	expressions := []string{"2(3+4) + sqrt(16)", "17 mod 5", "pow(2, 10) / 8", "3π", "max(2, 9) × 2", "1/0", "log(-1)"}
+	for _, expr := range expressions {
+		t.Run(expr, func(t *testing.T) {
+			output, _ := env.evaluateExpressions(context.Background(), expr)
+
+			var want string
+			result, err := calc.Execute(context.Background(), map[string]interface{}{"expression": expr})
+			if err != nil {
+				// The calculator prefixes errors raised inside govaluate
+				want = fmt.Sprintf("Error in '%s': %s", expr, strings.TrimPrefix(err.Error(), "evaluation error: "))
+			} else {
+				want = fmt.Sprintf("%s = %v", expr, formatResult(result))
+			}
+
+			if output != want {
+				t.Errorf("CodeMathEnv gave %q, calculator gave %q", output, want)
+			}
+		})
+	}
+}
@@ -3,20 +3,38 @@ package envs
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	"github.com/rizome-dev/go-verifiers/pkg/parsers"
 	"github.com/rizome-dev/go-verifiers/pkg/rubrics"
 	"github.com/rizome-dev/go-verifiers/pkg/types"
 )
 
+// DefaultVerifyPrompt is the verification message used by NewDoubleCheckEnv
+const DefaultVerifyPrompt = "Are you sure? Double-check your answer."
+
 // DoubleCheckEnv implements a double-checking mechanism for answers
 type DoubleCheckEnv struct {
 	*MultiTurnEnv
+	Rounds       int    // Number of verification rounds
+	VerifyPrompt string // Message sent after each answer to ask for verification
 }
 
-// NewDoubleCheckEnv creates a new double-check environment
+// NewDoubleCheckEnv creates a new double-check environment with a single verification round
 func NewDoubleCheckEnv(config types.Config) (*DoubleCheckEnv, error) {
+	return NewDoubleCheckEnvWithConfig(config, 1, DefaultVerifyPrompt)
+}
+
+// NewDoubleCheckEnvWithConfig creates a double-check environment that asks the model
+// to verify its answer rounds times with verifyPrompt. The answer given after the
+// final round is scored. An empty verifyPrompt uses DefaultVerifyPrompt.
+func NewDoubleCheckEnvWithConfig(config types.Config, rounds int, verifyPrompt string) (*DoubleCheckEnv, error) {
+	if rounds < 1 {
+		return nil, fmt.Errorf("rounds must be at least 1, got %d", rounds)
+	}
+	if verifyPrompt == "" {
+		verifyPrompt = DefaultVerifyPrompt
+	}
+
 	// Create parser for think/answer format
 	parser, err := parsers.NewXMLParser([]interface{}{"think", "answer"}, "answer")
 	if err != nil {
@@ -24,7 +42,9 @@ func NewDoubleCheckEnv(config types.Config) (*DoubleCheckEnv, error) {
 	}
 
 	env := &DoubleCheckEnv{
-		MultiTurnEnv: NewMultiTurnEnv(config, 2), // Max 2 turns: answer + verification
+		MultiTurnEnv: NewMultiTurnEnv(config, rounds+1), // Initial answer plus one answer per round
+		Rounds:       rounds,
+		VerifyPrompt: verifyPrompt,
 	}
 
 	// Set parser and rubric
@@ -39,35 +59,29 @@ func NewDoubleCheckEnv(config types.Config) (*DoubleCheckEnv, error) {
 	return env, nil
 }
 
-// IsCompleted checks if double-checking is done
-func (e *DoubleCheckEnv) IsCompleted(ctx context.Context, messages []types.Message, state map[string]interface{}) bool {
-	// Complete after the double-check question has been asked
-	if askedDoubleCheck, ok := state["asked_double_check"].(bool); ok && askedDoubleCheck {
-		return true
-	}
-
-	// Also complete if we have a final answer after being asked "Are you sure?"
-	for i := len(messages) - 1; i >= 0; i-- {
-		if messages[i].Role == "user" && strings.Contains(strings.ToLower(messages[i].Content), "are you sure") {
-			// Check if there's an assistant response after this
-			if i < len(messages)-1 && messages[i+1].Role == "assistant" {
-				return true
-			}
-		}
+// IsCompleted checks if double-checking is done: every verification round has
+// been asked and the model has answered the last one
+func (e *DoubleCheckEnv) IsCompleted(ctx context.Context, messages []types.Message, state *types.State) bool {
+	if len(messages) == 0 || messages[len(messages)-1].Role != "assistant" {
+		return false
 	}
 
-	return false
+	value, _ := state.Get("verify_rounds")
+	rounds, _ := value.(int)
+	return rounds >= e.Rounds
 }
 
-// EnvResponse provides the double-check prompt
-func (e *DoubleCheckEnv) EnvResponse(ctx context.Context, messages []types.Message, state map[string]interface{}) (types.Message, map[string]interface{}, error) {
+// EnvResponse provides the verification prompt
+func (e *DoubleCheckEnv) EnvResponse(ctx context.Context, messages []types.Message, state *types.State) (types.Message, *types.State, error) {
 	if len(messages) == 0 {
 		return types.Message{}, state, fmt.Errorf("no messages to process")
 	}
 
-	// Check if we've already asked the double-check question
-	if askedDoubleCheck, ok := state["asked_double_check"].(bool); ok && askedDoubleCheck {
-		return types.Message{}, state, fmt.Errorf("already asked double-check question")
+	// Check if we've already asked every verification round
+	value, _ := state.Get("verify_rounds")
+	rounds, _ := value.(int)
+	if rounds >= e.Rounds {
+		return types.Message{}, state, fmt.Errorf("already asked %d verification rounds", rounds)
 	}
 
 	// Get last assistant message
@@ -77,7 +91,8 @@ func (e *DoubleCheckEnv) EnvResponse(ctx context.Context, messages []types.Messa
 	}
 
 	// Parse the response to check if there's an answer
-	if parser, ok := e.parser.(*parsers.XMLParser); ok {
+	parser, _ := e.parserAndRubric()
+	if parser, ok := parser.(*parsers.XMLParser); ok {
 		parsed, err := parser.ParseXML(lastMsg.Content, true)
 		if err != nil || parsed.Fields["answer"] == "" {
 			return types.Message{
@@ -87,13 +102,13 @@ func (e *DoubleCheckEnv) EnvResponse(ctx context.Context, messages []types.Messa
 		}
 	}
 
-	// Mark that we're asking the double-check question
-	state["asked_double_check"] = true
+	// Record the round we're asking
+	state.Set("verify_rounds", rounds+1)
 
-	// Ask the double-check question
+	// Ask the verification question
 	return types.Message{
 		Role:    "user",
-		Content: "Are you sure? Double-check your answer.",
+		Content: e.VerifyPrompt,
 	}, state, nil
 }
 
@@ -105,7 +120,8 @@ func (e *DoubleCheckEnv) Rollout(ctx context.Context, client types.Client, model
 	}
 
 	// Score based on the final answer (after double-checking)
-	if e.parser != nil && len(rollout.Messages) > 0 {
+	parser, rubric := e.parserAndRubric()
+	if parser != nil && rollout.Error == nil && len(rollout.Messages) > 0 {
 		// Find the last assistant message
 		var finalResponse string
 		for i := len(rollout.Messages) - 1; i >= 0; i-- {
@@ -116,20 +132,25 @@ func (e *DoubleCheckEnv) Rollout(ctx context.Context, client types.Client, model
 		}
 
 		if finalResponse != "" {
-			parsed, err := e.parser.Parse(ctx, finalResponse)
+			parsed, err := parser.Parse(ctx, finalResponse)
 			if err != nil {
+				e.logRollout(ctx, rollout)
 				return rollout, nil
 			}
 
-			if e.rubric != nil {
-				score, err := e.rubric.ComputeReward(ctx, parsed, answer)
+			if rubric != nil {
+				ctx := rubrics.WithRawResponse(ctx, finalResponse)
+				score, metrics, err := computeReward(ctx, rubric, parsed, answer)
 				if err != nil {
+					e.logRollout(ctx, rollout)
 					return rollout, nil
 				}
 				rollout.Score = score
+				rollout.Metrics = metrics
 			}
 		}
 	}
 
+	e.logRollout(ctx, rollout)
 	return rollout, nil
 }
\ No newline at end of file
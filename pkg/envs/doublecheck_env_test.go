@@ -0,0 +1,71 @@
+package envs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rizome-dev/go-verifiers/pkg/types"
+)
+
+// sequenceClient returns its responses in order, repeating the last one
+type sequenceClient struct {
+	MockClient
+	Responses []string
+	calls     int
+}
+
+func (c *sequenceClient) CreateChatCompletion(ctx context.Context, model string, messages []types.Message, args types.SamplingArgs) (string, error) {
+	idx := c.calls
+	if idx >= len(c.Responses) {
+		idx = len(c.Responses) - 1
+	}
+	c.calls++
+	return c.Responses[idx], nil
+}
+
+func TestDoubleCheckEnv_MultipleRounds(t *testing.T) {
+	const verifyPrompt = "Check your work once more."
+
+	env, err := NewDoubleCheckEnvWithConfig(types.Config{MessageType: "chat"}, 2, verifyPrompt)
+	if err != nil {
+		t.Fatalf("NewDoubleCheckEnvWithConfig failed: %v", err)
+	}
+	if env.MaxTurns != 3 {
+		t.Errorf("Expected MaxTurns 3, got %d", env.MaxTurns)
+	}
+
+	client := &sequenceClient{Responses: []string{
+		"<think>\nquick guess\n</think>\n<answer>\n5\n</answer>",
+		"<think>\nrechecking\n</think>\n<answer>\n5\n</answer>",
+		"<think>\n2 + 2 is 4\n</think>\n<answer>\n4\n</answer>",
+	}}
+
+	prompt := env.FormatPrompt("What is 2 + 2?")
+	rollout, err := env.Rollout(context.Background(), client, "test-model", prompt, "4", types.SamplingArgs{})
+	if err != nil {
+		t.Fatalf("Rollout failed: %v", err)
+	}
+
+	verifications := 0
+	for _, msg := range rollout.Messages {
+		if msg.Role == "user" && msg.Content == verifyPrompt {
+			verifications++
+		}
+	}
+	if verifications != 2 {
+		t.Errorf("Expected the verify prompt twice, got %d", verifications)
+	}
+	if client.calls != 3 {
+		t.Errorf("Expected 3 model calls, got %d", client.calls)
+	}
+	if rollout.Terminated != types.TerminatedCompleted {
+		t.Errorf("Expected completed rollout, got %q", rollout.Terminated)
+	}
+	if rollout.Metrics["correct_answer"] != 1.0 {
+		t.Errorf("Expected the final answer 4 to be scored, got metrics %v", rollout.Metrics)
+	}
+
+	if _, err := NewDoubleCheckEnvWithConfig(types.Config{}, 0, ""); err == nil {
+		t.Error("Expected error for zero rounds")
+	}
+}
@@ -2,7 +2,10 @@ package envs
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math/rand"
+	"sort"
 	"strings"
 
 	"github.com/rizome-dev/go-verifiers/pkg/types"
@@ -13,6 +16,7 @@ type EnvGroup struct {
 	*BaseEnvironment
 	envs      map[string]Environment
 	envNames  []string // Ordered list of environment names
+	taskWeights map[string]float64 // Normalized sampling weights; nil concatenates datasets
 }
 
 // NewEnvGroup creates a new environment group
@@ -27,15 +31,49 @@ func NewEnvGroup(config types.Config, envs map[string]Environment) *EnvGroup {
 	for name := range envs {
 		group.envNames = append(group.envNames, name)
 	}
+	sort.Strings(group.envNames)
 
 	return group
 }
 
-// Rollout routes to the appropriate sub-environment based on task
+// taskKey is the context key for the task being rolled out
+type taskKey struct{}
+
+// WithTask attaches the task name to the context, so reward functions returned by
+// EnvGroup.GetRewardFuncs can route without a "task:" prefix on the ground truth
+func WithTask(ctx context.Context, task string) context.Context {
+	return context.WithValue(ctx, taskKey{}, task)
+}
+
+// TaskFromContext returns the task name attached by WithTask
+func TaskFromContext(ctx context.Context) (string, bool) {
+	task, ok := ctx.Value(taskKey{}).(string)
+	return task, ok
+}
+
+// Rollout routes to the appropriate sub-environment based on task.
+// A task set with WithTask takes precedence; otherwise the answer is read in the
+// legacy "task:answer" format. An answer without a registered task prefix is an
+// error, since GetDataset items carry their task in the "task" column instead.
+// Prefer RolloutForTask, which leaves the answer untouched.
 func (g *EnvGroup) Rollout(ctx context.Context, client types.Client, model string, prompt interface{}, answer string, samplingArgs types.SamplingArgs) (*types.Rollout, error) {
+	if task, ok := TaskFromContext(ctx); ok {
+		return g.RolloutForTask(ctx, client, model, task, prompt, answer, samplingArgs)
+	}
+
 	// Extract task from answer format "task:answer"
-	task, actualAnswer := g.parseTaskAnswer(answer)
-	
+	task, actualAnswer, ok := g.parseTaskAnswer(answer)
+	if !ok {
+		return nil, errNoTask
+	}
+	return g.RolloutForTask(ctx, client, model, task, prompt, actualAnswer, samplingArgs)
+}
+
+// errNoTask is returned when neither the context nor the answer names a task
+var errNoTask = errors.New("no task given: use RolloutForTask, attach one with WithTask, or prefix the answer with a registered task as \"task:answer\"")
+
+// RolloutForTask delegates the rollout to the environment registered for task
+func (g *EnvGroup) RolloutForTask(ctx context.Context, client types.Client, model string, task string, prompt interface{}, answer string, samplingArgs types.SamplingArgs) (*types.Rollout, error) {
 	// Find the appropriate environment
 	env, exists := g.envs[task]
 	if !exists {
@@ -43,11 +81,49 @@ func (g *EnvGroup) Rollout(ctx context.Context, client types.Client, model strin
 	}
 
 	// Delegate to the specific environment
-	return env.Rollout(ctx, client, model, prompt, actualAnswer, samplingArgs)
+	return env.Rollout(WithTask(ctx, task), client, model, prompt, answer, samplingArgs)
+}
+
+// SetTaskWeights sets the task mixture used by GetDataset. Weights must be
+// non-negative, name registered tasks and not all be zero; they are normalized to
+// sum to 1. Tasks missing from the map get weight 0. Passing nil restores plain
+// concatenation.
+func (g *EnvGroup) SetTaskWeights(weights map[string]float64) error {
+	if weights == nil {
+		g.taskWeights = nil
+		return nil
+	}
+
+	total := 0.0
+	for task, weight := range weights {
+		if _, exists := g.envs[task]; !exists {
+			return fmt.Errorf("unknown task: %s", task)
+		}
+		if weight < 0 {
+			return fmt.Errorf("weight for task %s must be non-negative, got %v", task, weight)
+		}
+		total += weight
+	}
+	if total == 0 {
+		return fmt.Errorf("task weights must not all be zero")
+	}
+
+	normalized := make(map[string]float64, len(weights))
+	for task, weight := range weights {
+		normalized[task] = weight / total
+	}
+	g.taskWeights = normalized
+	return nil
 }
 
-// GetDataset returns concatenated datasets with task labels
+// GetDataset returns concatenated datasets with task labels.
+// Each item keeps its original answer and names its environment in the "task" column.
+// When task weights are set and n > 0, n items are sampled according to the weights instead.
 func (g *EnvGroup) GetDataset(n int, seed int64) types.Dataset {
+	if g.taskWeights != nil && n > 0 {
+		return g.sampleByWeight(n, seed)
+	}
+
 	datasets := make([]types.Dataset, 0)
 	
 	for _, envName := range g.envNames {
@@ -55,20 +131,7 @@ func (g *EnvGroup) GetDataset(n int, seed int64) types.Dataset {
 		dataset := env.GetDataset(-1, seed) // Get all items
 		
 		if dataset != nil {
-			// Add task label to each item
-			labeledDataset := dataset.Map(func(item map[string]interface{}) map[string]interface{} {
-				newItem := make(map[string]interface{})
-				for k, v := range item {
-					newItem[k] = v
-				}
-				// Store original answer and create task-prefixed answer
-				if answer, ok := item["answer"].(string); ok {
-					newItem["answer"] = fmt.Sprintf("%s:%s", envName, answer)
-				}
-				newItem["task"] = envName
-				return newItem
-			})
-			datasets = append(datasets, labeledDataset)
+			datasets = append(datasets, labelTask(dataset, envName))
 		}
 	}
 
@@ -81,12 +144,83 @@ func (g *EnvGroup) GetDataset(n int, seed int64) types.Dataset {
 	
 	// Apply sampling if requested
 	if n > 0 && n < combined.Len() {
-		return combined.Shuffle(seed).Select(makeRange(n))
+		return sampleDataset(combined, n, seed)
 	}
 	
 	return combined
 }
 
+// sampleByWeight draws n labeled items, splitting n across tasks in proportion to
+// the task weights. A task with fewer items than its quota is sampled with replacement.
+func (g *EnvGroup) sampleByWeight(n int, seed int64) types.Dataset {
+	// Only tasks with data take part in the mixture
+	datasets := make(map[string]types.Dataset)
+	total := 0.0
+	for _, envName := range g.envNames {
+		weight := g.taskWeights[envName]
+		if weight == 0 {
+			continue
+		}
+		dataset := g.envs[envName].GetDataset(-1, seed)
+		if dataset == nil || dataset.Len() == 0 {
+			continue
+		}
+		datasets[envName] = labelTask(dataset, envName)
+		total += weight
+	}
+	if len(datasets) == 0 {
+		return nil
+	}
+
+	// Largest remainder apportionment, so the quotas sum to n
+	quotas := make(map[string]int, len(datasets))
+	type remainder struct {
+		task  string
+		value float64
+	}
+	remainders := make([]remainder, 0, len(datasets))
+	assigned := 0
+	for _, envName := range g.envNames {
+		if _, ok := datasets[envName]; !ok {
+			continue
+		}
+		exact := float64(n) * g.taskWeights[envName] / total
+		quotas[envName] = int(exact)
+		assigned += quotas[envName]
+		remainders = append(remainders, remainder{task: envName, value: exact - float64(int(exact))})
+	}
+	sort.SliceStable(remainders, func(i, j int) bool {
+		return remainders[i].value > remainders[j].value
+	})
+	for i := 0; assigned < n; i++ {
+		quotas[remainders[i%len(remainders)].task]++
+		assigned++
+	}
+
+	r := rand.New(rand.NewSource(seed))
+	samples := make([]types.Dataset, 0, len(datasets))
+	for _, envName := range g.envNames {
+		dataset, ok := datasets[envName]
+		if !ok || quotas[envName] == 0 {
+			continue
+		}
+
+		quota := quotas[envName]
+		if quota <= dataset.Len() {
+			samples = append(samples, sampleDataset(dataset, quota, seed))
+			continue
+		}
+
+		indices := makeRange(dataset.Len())
+		for len(indices) < quota {
+			indices = append(indices, r.Intn(dataset.Len()))
+		}
+		samples = append(samples, dataset.Select(indices))
+	}
+
+	return types.DatasetUtils{}.Concatenate(samples...).Shuffle(seed)
+}
+
 // GetEvalDataset returns concatenated eval datasets with task labels
 func (g *EnvGroup) GetEvalDataset(n int, seed int64) types.Dataset {
 	datasets := make([]types.Dataset, 0)
@@ -96,20 +230,7 @@ func (g *EnvGroup) GetEvalDataset(n int, seed int64) types.Dataset {
 		dataset := env.GetEvalDataset(-1, seed)
 		
 		if dataset != nil {
-			// Add task label to each item
-			labeledDataset := dataset.Map(func(item map[string]interface{}) map[string]interface{} {
-				newItem := make(map[string]interface{})
-				for k, v := range item {
-					newItem[k] = v
-				}
-				// Store original answer and create task-prefixed answer
-				if answer, ok := item["answer"].(string); ok {
-					newItem["answer"] = fmt.Sprintf("%s:%s", envName, answer)
-				}
-				newItem["task"] = envName
-				return newItem
-			})
-			datasets = append(datasets, labeledDataset)
+			datasets = append(datasets, labelTask(dataset, envName))
 		}
 	}
 
@@ -122,7 +243,7 @@ func (g *EnvGroup) GetEvalDataset(n int, seed int64) types.Dataset {
 	
 	// Apply sampling if requested
 	if n > 0 && n < combined.Len() {
-		return combined.Shuffle(seed).Select(makeRange(n))
+		return sampleDataset(combined, n, seed)
 	}
 	
 	return combined
@@ -147,6 +268,39 @@ func (g *EnvGroup) GetRewardFuncs() []types.RewardFunc {
 	return funcs
 }
 
+// rewardFuncNamer is implemented by environments that name their reward functions,
+// such as those embedding BaseEnvironment
+type rewardFuncNamer interface {
+	GetRewardFuncNames() []string
+}
+
+// GetRewardFuncNames returns names for all reward functions, each prefixed with its
+// environment's name, as in "math/reward". The i-th name always corresponds to the
+// i-th function from GetRewardFuncs. Functions of an environment that does not name
+// them, or names them inconsistently, are numbered "reward_0", "reward_1", ...
+func (g *EnvGroup) GetRewardFuncNames() []string {
+	names := make([]string, 0)
+	
+	for _, envName := range g.envNames {
+		env := g.envs[envName]
+		envFuncs := env.GetRewardFuncs()
+		
+		var envFuncNames []string
+		if namer, ok := env.(rewardFuncNamer); ok {
+			envFuncNames = namer.GetRewardFuncNames()
+		}
+		for i := range envFuncs {
+			name := fmt.Sprintf("reward_%d", i)
+			if len(envFuncNames) == len(envFuncs) {
+				name = envFuncNames[i]
+			}
+			names = append(names, envName+"/"+name)
+		}
+	}
+	
+	return names
+}
+
 // GetRewardWeights returns weights for all reward functions
 func (g *EnvGroup) GetRewardWeights() []float64 {
 	weights := make([]float64, 0)
@@ -161,24 +315,44 @@ func (g *EnvGroup) GetRewardWeights() []float64 {
 	return weights
 }
 
-// parseTaskAnswer extracts task and answer from "task:answer" format
-func (g *EnvGroup) parseTaskAnswer(answer string) (string, string) {
+// labelTask copies each item of dataset and sets its "task" column
+func labelTask(dataset types.Dataset, task string) types.Dataset {
+	return dataset.Map(func(item map[string]interface{}) map[string]interface{} {
+		newItem := make(map[string]interface{}, len(item)+1)
+		for k, v := range item {
+			newItem[k] = v
+		}
+		newItem["task"] = task
+		return newItem
+	})
+}
+
+// parseTaskAnswer extracts task and answer from "task:answer" format.
+// The prefix is only treated as a task when it names a registered environment,
+// so answers such as "3:4" or "12:30" are not split. It reports false when the
+// answer has no such prefix.
+func (g *EnvGroup) parseTaskAnswer(answer string) (string, string, bool) {
 	parts := strings.SplitN(answer, ":", 2)
 	if len(parts) == 2 {
-		return parts[0], parts[1]
-	}
-	// Default to first environment if no task specified
-	if len(g.envNames) > 0 {
-		return g.envNames[0], answer
+		if _, exists := g.envs[parts[0]]; exists {
+			return parts[0], parts[1], true
+		}
 	}
-	return "", answer
+	return "", answer, false
 }
 
 // wrapRewardFunc wraps a reward function to handle task routing
 func (g *EnvGroup) wrapRewardFunc(envName string, fn types.RewardFunc) types.RewardFunc {
 	return func(ctx context.Context, parsed, groundTruth string) (float64, error) {
-		// Extract task from ground truth
-		task, actualGroundTruth := g.parseTaskAnswer(groundTruth)
+		// Use the task from the context, falling back to the ground truth prefix
+		task, ok := TaskFromContext(ctx)
+		actualGroundTruth := groundTruth
+		if !ok {
+			task, actualGroundTruth, ok = g.parseTaskAnswer(groundTruth)
+			if !ok {
+				return 0.0, errNoTask
+			}
+		}
 		
 		// If this isn't the right task, return 0
 		if task != envName {
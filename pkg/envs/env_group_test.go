@@ -0,0 +1,161 @@
+package envs
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/rizome-dev/go-verifiers/pkg/parsers"
+	"github.com/rizome-dev/go-verifiers/pkg/rubrics"
+	"github.com/rizome-dev/go-verifiers/pkg/types"
+)
+
+// newTestEnvGroup builds a group of exact-match single-turn environments with one
+// question each
+func newTestEnvGroup(items map[string]map[string]interface{}) *EnvGroup {
+	envMap := make(map[string]Environment, len(items))
+	for name, item := range items {
+		env := NewSingleTurnEnv(types.Config{MessageType: "chat"})
+		env.SetParser(parsers.NewBaseParser())
+		env.SetRubric(rubrics.NewBaseRubric())
+		env.SetDataset(types.NewSimpleDataset([]map[string]interface{}{item}))
+		envMap[name] = env
+	}
+	return NewEnvGroup(types.Config{MessageType: "chat"}, envMap)
+}
+
+func TestEnvGroup_RoutesAnswersContainingColons(t *testing.T) {
+	group := newTestEnvGroup(map[string]map[string]interface{}{
+		"math":  {"question": "What is 2 + 2?", "answer": "4"},
+		"ratio": {"question": "Simplify 6:8", "answer": "3:4"},
+	})
+	ctx := context.Background()
+	mockClient := &MockClient{Response: "3:4"}
+	prompt := group.FormatPrompt("Simplify 6:8")
+
+	rollout, err := group.RolloutForTask(ctx, mockClient, "test-model", "ratio", prompt, "3:4", types.SamplingArgs{})
+	if err != nil {
+		t.Fatalf("RolloutForTask failed: %v", err)
+	}
+	if rollout.Score != 1.0 {
+		t.Errorf("Expected score 1.0 for answer 3:4, got %v", rollout.Score)
+	}
+
+	// The legacy prefix still routes, and unknown prefixes are not stripped
+	rollout, err = group.Rollout(ctx, mockClient, "test-model", prompt, "ratio:3:4", types.SamplingArgs{})
+	if err != nil {
+		t.Fatalf("Rollout failed: %v", err)
+	}
+	if rollout.Score != 1.0 {
+		t.Errorf("Expected score 1.0 for legacy answer ratio:3:4, got %v", rollout.Score)
+	}
+
+	if _, err := group.RolloutForTask(ctx, mockClient, "test-model", "missing", prompt, "3:4", types.SamplingArgs{}); err == nil {
+		t.Error("Expected error for unknown task")
+	}
+
+	// Without a task in the context or a registered prefix, nothing is guessed
+	if _, err := group.Rollout(ctx, mockClient, "test-model", prompt, "3:4", types.SamplingArgs{}); err == nil {
+		t.Error("Expected error for an answer without a task")
+	}
+	if rollout, err := group.Rollout(WithTask(ctx, "ratio"), mockClient, "test-model", prompt, "3:4", types.SamplingArgs{}); err != nil || rollout.Score != 1.0 {
+		t.Errorf("Expected the task in the context to route the rollout, got %v", err)
+	}
+
+	// Dataset items keep their answers and carry the task column
+	dataset := group.GetDataset(-1, 0)
+	for i := 0; i < dataset.Len(); i++ {
+		item := dataset.Get(i)
+		if item["task"] == "ratio" && item["answer"] != "3:4" {
+			t.Errorf("Expected ratio answer 3:4, got %v", item["answer"])
+		}
+	}
+
+	result, err := Evaluate(ctx, group, mockClient, "test-model", dataset, EvalOptions{})
+	if err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+	if result.NumErrors != 0 || result.MeanScore != 0.5 {
+		t.Errorf("Expected only the ratio item to score, got mean %v errors %v", result.MeanScore, result.Errors)
+	}
+
+	// Reward functions route on the task in the context
+	funcs := group.GetRewardFuncs()
+	total := 0.0
+	for _, fn := range funcs {
+		score, err := fn(WithTask(ctx, "ratio"), "3:4", "3:4")
+		if err != nil {
+			t.Fatalf("reward func failed: %v", err)
+		}
+		total += score
+	}
+	if total != 1.0 {
+		t.Errorf("Expected exactly one reward func to score the ratio task, got total %v", total)
+	}
+
+	// Without a task they fail rather than scoring against the first environment
+	for _, fn := range funcs {
+		if _, err := fn(ctx, "3:4", "3:4"); err == nil {
+			t.Error("Expected reward func error for a ground truth without a task")
+		}
+	}
+}
+
+func TestEnvGroup_SetTaskWeights(t *testing.T) {
+	group := newTestEnvGroup(map[string]map[string]interface{}{
+		"math":  {"question": "What is 2 + 2?", "answer": "4"},
+		"ratio": {"question": "Simplify 6:8", "answer": "3:4"},
+	})
+
+	invalid := []map[string]float64{
+		{"math": -1, "ratio": 2},
+		{"math": 0, "ratio": 0},
+		{"missing": 1},
+	}
+	for _, weights := range invalid {
+		if err := group.SetTaskWeights(weights); err == nil {
+			t.Errorf("Expected error for weights %v", weights)
+		}
+	}
+
+	if err := group.SetTaskWeights(map[string]float64{"math": 3, "ratio": 1}); err != nil {
+		t.Fatalf("SetTaskWeights failed: %v", err)
+	}
+
+	const n = 1000
+	dataset := group.GetDataset(n, 42)
+	if dataset.Len() != n {
+		t.Fatalf("Expected %d items, got %d", n, dataset.Len())
+	}
+
+	counts := make(map[string]int)
+	for i := 0; i < dataset.Len(); i++ {
+		task, _ := dataset.Get(i)["task"].(string)
+		counts[task]++
+	}
+
+	expected := map[string]float64{"math": 0.75, "ratio": 0.25}
+	for task, want := range expected {
+		got := float64(counts[task]) / n
+		if got < want-0.02 || got > want+0.02 {
+			t.Errorf("Task %s proportion = %v, want %v", task, got, want)
+		}
+	}
+}
+
+func TestEnvGroup_GetRewardFuncNames(t *testing.T) {
+	group := newTestEnvGroup(map[string]map[string]interface{}{
+		"math":  {"question": "What is 2 + 2?", "answer": "4"},
+		"ratio": {"question": "Simplify 6:8", "answer": "3:4"},
+	})
+
+	funcs := group.GetRewardFuncs()
+	weights := group.GetRewardWeights()
+	names := group.GetRewardFuncNames()
+	if len(funcs) != 2 || len(weights) != len(funcs) || len(names) != len(funcs) {
+		t.Fatalf("Expected matching lengths, got %d funcs, %d weights and %d names", len(funcs), len(weights), len(names))
+	}
+	if want := []string{"math/reward", "ratio/reward"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("GetRewardFuncNames() = %v, want %v", names, want)
+	}
+}
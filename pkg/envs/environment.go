@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/rizome-dev/go-verifiers/pkg/parsers"
 	"github.com/rizome-dev/go-verifiers/pkg/rubrics"
@@ -16,9 +18,14 @@ const (
 	DatasetMaxConcurrent = 32
 )
 
+// DefaultTranscriptTemplate renders each message of a completion-mode multi-turn
+// transcript; "{role}" and "{content}" are replaced with the message's fields
+const DefaultTranscriptTemplate = "{role}: {content}\n\n"
+
 // Environment is the base interface for all environments
 type Environment interface {
-	// Rollout performs a single environment rollout
+	// Rollout performs a single environment rollout. Implementations must be safe
+	// for concurrent use so one environment can serve a whole batch.
 	Rollout(ctx context.Context, client types.Client, model string, prompt interface{}, answer string, samplingArgs types.SamplingArgs) (*types.Rollout, error)
 	
 	// GetDataset returns the training dataset
@@ -34,7 +41,11 @@ type Environment interface {
 	GetRewardWeights() []float64
 }
 
-// BaseEnvironment provides common functionality for all environments
+// BaseEnvironment provides common functionality for all environments.
+// Configuration fixed at construction is read-only afterwards; fields with setters
+// are guarded by mu, and rollouts take a snapshot of the parser and rubric so that
+// Rollout may be called from many goroutines while setters run. Per-rollout state
+// lives in the rollout itself and is never shared between calls.
 type BaseEnvironment struct {
 	client        types.Client
 	model         string
@@ -42,6 +53,7 @@ type BaseEnvironment struct {
 	evalDataset   types.Dataset
 	systemPrompt  string
 	fewShot       []types.Message
+	fewShotMode   types.FewShotMode
 	parser        parsers.Parser
 	rubric        rubrics.Rubric
 	samplingArgs  types.SamplingArgs
@@ -49,6 +61,8 @@ type BaseEnvironment struct {
 	messageType   string
 	logger        *slog.Logger
 	mu            sync.RWMutex
+
+	transcriptTemplate string
 }
 
 // NewBaseEnvironment creates a new base environment
@@ -57,10 +71,13 @@ func NewBaseEnvironment(config types.Config) *BaseEnvironment {
 		model:         config.Model,
 		systemPrompt:  config.SystemPrompt,
 		fewShot:       config.FewShot,
+		fewShotMode:   config.FewShotMode,
 		samplingArgs:  config.SamplingArgs,
 		maxConcurrent: config.MaxConcurrent,
 		messageType:   config.MessageType,
 		logger:        slog.Default().With("component", "environment"),
+
+		transcriptTemplate: DefaultTranscriptTemplate,
 	}
 
 	if env.maxConcurrent == 0 {
@@ -71,6 +88,10 @@ func NewBaseEnvironment(config types.Config) *BaseEnvironment {
 		env.messageType = "chat"
 	}
 
+	if env.fewShotMode == "" {
+		env.fewShotMode = types.FewShotReplace
+	}
+
 	// Set default sampling args
 	if env.samplingArgs.N == 0 {
 		env.samplingArgs.N = 1
@@ -87,17 +108,99 @@ func NewBaseEnvironment(config types.Config) *BaseEnvironment {
 
 // FormatPrompt formats a prompt with system prompt and few-shot examples
 func (e *BaseEnvironment) FormatPrompt(prompt string) []types.Message {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return formatMessages(e.systemPrompt, e.fewShot, prompt)
+}
+
+// FormatPromptFromItem formats a dataset item into a prompt.
+// The item's "question" (or "prompt") column becomes the user message. A string
+// "system_prompt" column replaces the environment's system prompt; an empty string
+// drops it. If the item carries a "few_shot" column, its examples take precedence over the environment's
+// default few-shot examples: with FewShotReplace (the default) they replace them,
+// with FewShotAppend they are added after them.
+func (e *BaseEnvironment) FormatPromptFromItem(item map[string]interface{}) ([]types.Message, error) {
+	question, ok := item["question"].(string)
+	if !ok {
+		question, ok = item["prompt"].(string)
+	}
+	if !ok {
+		return nil, fmt.Errorf("dataset item has no string 'question' or 'prompt' column")
+	}
+
+	itemFewShot, err := types.MessagesFromItem(item, "few_shot")
+	if err != nil {
+		return nil, fmt.Errorf("invalid few_shot column: %w", err)
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	systemPrompt := e.systemPrompt
+	if itemSystemPrompt, ok := item["system_prompt"].(string); ok {
+		systemPrompt = itemSystemPrompt
+	}
+
+	return formatMessages(systemPrompt, e.resolveFewShot(itemFewShot), question), nil
+}
+
+// PromptFromItem builds the prompt for a dataset item in the environment's message
+// type: the chat messages of FormatPromptFromItem, or in completion mode the item's
+// question as is, since completion prompts carry no system prompt or few-shot turns.
+func (e *BaseEnvironment) PromptFromItem(item map[string]interface{}) (interface{}, error) {
+	messages, err := e.FormatPromptFromItem(item)
+	if err != nil {
+		return nil, err
+	}
+	if e.messageType == "completion" {
+		return messages[len(messages)-1].Content, nil
+	}
+	return messages, nil
+}
+
+// GetSamplingArgs returns the environment's configured sampling arguments
+func (e *BaseEnvironment) GetSamplingArgs() types.SamplingArgs {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.samplingArgs
+}
+
+// SetFewShotMode sets how per-item few-shot examples combine with the environment default
+func (e *BaseEnvironment) SetFewShotMode(mode types.FewShotMode) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.fewShotMode = mode
+}
+
+// resolveFewShot combines item-level few-shot examples with the environment default
+func (e *BaseEnvironment) resolveFewShot(itemFewShot []types.Message) []types.Message {
+	if itemFewShot == nil {
+		return e.fewShot
+	}
+
+	if e.fewShotMode == types.FewShotAppend {
+		combined := make([]types.Message, 0, len(e.fewShot)+len(itemFewShot))
+		combined = append(combined, e.fewShot...)
+		return append(combined, itemFewShot...)
+	}
+
+	return itemFewShot
+}
+
+// formatMessages builds a chat prompt from a system prompt, few-shot examples and a user prompt
+func formatMessages(systemPrompt string, fewShot []types.Message, prompt string) []types.Message {
 	messages := make([]types.Message, 0)
 	
-	if e.systemPrompt != "" {
+	if systemPrompt != "" {
 		messages = append(messages, types.Message{
 			Role:    "system",
-			Content: e.systemPrompt,
+			Content: systemPrompt,
 		})
 	}
 	
-	if len(e.fewShot) > 0 {
-		messages = append(messages, e.fewShot...)
+	if len(fewShot) > 0 {
+		messages = append(messages, fewShot...)
 	}
 	
 	messages = append(messages, types.Message{
@@ -108,8 +211,72 @@ func (e *BaseEnvironment) FormatPrompt(prompt string) []types.Message {
 	return messages
 }
 
-// GetModelResponse gets a response from the model
+// GetModelResponse gets a response from the model, logging each request and its
+// latency at debug level
 func (e *BaseEnvironment) GetModelResponse(ctx context.Context, prompt interface{}, client types.Client, model string, samplingArgs types.SamplingArgs) (string, error) {
+	logger := e.Logger()
+	logger.DebugContext(ctx, "model request started", "model", model, "message_type", e.messageType)
+	start := time.Now()
+
+	response, err := e.getModelResponse(ctx, prompt, client, model, samplingArgs)
+	if err != nil {
+		logger.DebugContext(ctx, "model request failed", "model", model, "latency", time.Since(start), "error", err)
+		return "", err
+	}
+
+	logger.DebugContext(ctx, "model request finished", "model", model, "latency", time.Since(start), "response_chars", len(response))
+	return response, nil
+}
+
+// getMessagesResponse gets the next assistant turn of a multi-turn conversation.
+// In completion mode the messages are rendered into a transcript with
+// RenderTranscript, so base models without a chat template can hold a dialog.
+func (e *BaseEnvironment) getMessagesResponse(ctx context.Context, client types.Client, model string, messages []types.Message, samplingArgs types.SamplingArgs) (string, error) {
+	if e.messageType == "completion" {
+		return e.GetModelResponse(ctx, e.RenderTranscript(messages), client, model, samplingArgs)
+	}
+	return e.GetModelResponse(ctx, messages, client, model, samplingArgs)
+}
+
+// SetTranscriptTemplate sets the per-message template used to render completion-mode
+// transcripts. An empty template restores DefaultTranscriptTemplate.
+func (e *BaseEnvironment) SetTranscriptTemplate(template string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if template == "" {
+		template = DefaultTranscriptTemplate
+	}
+	e.transcriptTemplate = template
+}
+
+// RenderTranscript renders messages with the transcript template and ends with the
+// template's prefix for an assistant message, cueing the model to take its turn
+func (e *BaseEnvironment) RenderTranscript(messages []types.Message) string {
+	e.mu.RLock()
+	template := e.transcriptTemplate
+	e.mu.RUnlock()
+
+	var transcript strings.Builder
+	for _, msg := range messages {
+		transcript.WriteString(renderTranscriptMessage(template, msg.Role, msg.Content))
+	}
+
+	// The text before {content} introduces the assistant's reply
+	cue := template
+	if idx := strings.Index(cue, "{content}"); idx >= 0 {
+		cue = cue[:idx]
+	}
+	transcript.WriteString(strings.ReplaceAll(cue, "{role}", "assistant"))
+	return transcript.String()
+}
+
+// renderTranscriptMessage fills in the transcript template for one message
+func renderTranscriptMessage(template, role, content string) string {
+	return strings.NewReplacer("{role}", role, "{content}", content).Replace(template)
+}
+
+// getModelResponse dispatches the request by message type
+func (e *BaseEnvironment) getModelResponse(ctx context.Context, prompt interface{}, client types.Client, model string, samplingArgs types.SamplingArgs) (string, error) {
 	switch e.messageType {
 	case "chat":
 		messages, ok := prompt.([]types.Message)
@@ -138,7 +305,7 @@ func (e *BaseEnvironment) GetDataset(n int, seed int64) types.Dataset {
 	}
 	
 	if n > 0 && n < e.dataset.Len() {
-		return e.dataset.Shuffle(seed).Select(makeRange(n))
+		return sampleDataset(e.dataset, n, seed)
 	}
 	return e.dataset
 }
@@ -153,23 +320,31 @@ func (e *BaseEnvironment) GetEvalDataset(n int, seed int64) types.Dataset {
 	}
 	
 	if n > 0 && n < e.evalDataset.Len() {
-		return e.evalDataset.Shuffle(seed).Select(makeRange(n))
+		return sampleDataset(e.evalDataset, n, seed)
 	}
 	return e.evalDataset
 }
 
 // GetRewardFuncs returns the reward functions from the rubric
 func (e *BaseEnvironment) GetRewardFuncs() []types.RewardFunc {
-	if e.rubric != nil {
-		return e.rubric.GetRewardFuncs()
+	if _, rubric := e.parserAndRubric(); rubric != nil {
+		return rubric.GetRewardFuncs()
 	}
 	return nil
 }
 
 // GetRewardWeights returns the reward weights from the rubric
 func (e *BaseEnvironment) GetRewardWeights() []float64 {
-	if e.rubric != nil {
-		return e.rubric.GetRewardWeights()
+	if _, rubric := e.parserAndRubric(); rubric != nil {
+		return rubric.GetRewardWeights()
+	}
+	return nil
+}
+
+// GetRewardFuncNames returns the reward function names from the rubric
+func (e *BaseEnvironment) GetRewardFuncNames() []string {
+	if _, rubric := e.parserAndRubric(); rubric != nil {
+		return rubric.GetRewardFuncNames()
 	}
 	return nil
 }
@@ -195,6 +370,31 @@ func (e *BaseEnvironment) SetParser(parser parsers.Parser) {
 	e.parser = parser
 }
 
+// AppendFormatInstructions appends the parser's format description to the system
+// prompt, so the prompt always matches what the parser expects. It does nothing when
+// the parser does not implement parsers.Formatter or the prompt already contains
+// the format.
+func (e *BaseEnvironment) AppendFormatInstructions() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	formatter, ok := e.parser.(parsers.Formatter)
+	if !ok {
+		return
+	}
+	format := formatter.GetFormatStr()
+	if format == "" || strings.Contains(e.systemPrompt, format) {
+		return
+	}
+
+	instructions := "Respond in the following format:\n" + format
+	if e.systemPrompt == "" {
+		e.systemPrompt = instructions
+		return
+	}
+	e.systemPrompt += "\n\n" + instructions
+}
+
 // SetRubric sets the rubric
 func (e *BaseEnvironment) SetRubric(rubric rubrics.Rubric) {
 	e.mu.Lock()
@@ -202,6 +402,100 @@ func (e *BaseEnvironment) SetRubric(rubric rubrics.Rubric) {
 	e.rubric = rubric
 }
 
+// SetLogger sets the logger used for rollout logging. A nil logger restores the default.
+func (e *BaseEnvironment) SetLogger(logger *slog.Logger) {
+	if logger == nil {
+		logger = slog.Default().With("component", "environment")
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.logger = logger
+}
+
+// Logger returns the environment's logger
+func (e *BaseEnvironment) Logger() *slog.Logger {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.logger
+}
+
+// logRollout records the outcome of a finished rollout at info level
+func (e *BaseEnvironment) logRollout(ctx context.Context, rollout *types.Rollout) {
+	turns := 0
+	for _, msg := range rollout.Messages {
+		if msg.Role == "assistant" {
+			turns++
+		}
+	}
+
+	attrs := []any{"score", rollout.Score, "turns", turns}
+	if rollout.Terminated != "" {
+		attrs = append(attrs, "terminated", string(rollout.Terminated))
+	}
+	if rollout.Error != nil {
+		attrs = append(attrs, "error", rollout.Error.Message)
+	}
+	e.Logger().InfoContext(ctx, "rollout finished", attrs...)
+}
+
+// loggerProvider is implemented by environments that expose their logger
+type loggerProvider interface {
+	Logger() *slog.Logger
+}
+
+// envLogger returns env's logger, or the default logger if it has none
+func envLogger(env interface{}) *slog.Logger {
+	if provider, ok := env.(loggerProvider); ok {
+		if logger := provider.Logger(); logger != nil {
+			return logger
+		}
+	}
+	return slog.Default()
+}
+
+// parserAndRubric returns a consistent snapshot of the parser and rubric for one rollout
+func (e *BaseEnvironment) parserAndRubric() (parsers.Parser, rubrics.Rubric) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.parser, e.rubric
+}
+
+// computeReward scores parsed against answer and returns the per-metric breakdown
+// when the rubric reports one. A BreakdownScorer runs each metric once. Other
+// BreakdownRubrics need a second pass for the breakdown; it only annotates the
+// rollout, so if it fails the rollout keeps its score without metrics.
+func computeReward(ctx context.Context, rubric rubrics.Rubric, parsed string, answer string) (float64, map[string]float64, error) {
+	if scorer, ok := rubric.(rubrics.BreakdownScorer); ok {
+		return scorer.ComputeRewardWithBreakdown(ctx, parsed, answer)
+	}
+
+	score, err := rubric.ComputeReward(ctx, parsed, answer)
+	if err != nil {
+		return 0.0, nil, err
+	}
+
+	var metrics map[string]float64
+	if breakdownRubric, ok := rubric.(rubrics.BreakdownRubric); ok {
+		metrics, _ = breakdownRubric.ComputeRewardBreakdown(ctx, parsed, answer)
+	}
+	return score, metrics, nil
+}
+
+// indexShuffler is implemented by datasets that can produce a seeded permutation of
+// their indices without copying items
+type indexShuffler interface {
+	ShuffleIndices(seed int64) []int
+}
+
+// sampleDataset returns n items drawn by the seeded shuffle, copying only the
+// selected items when the dataset supports ShuffleIndices
+func sampleDataset(dataset types.Dataset, n int, seed int64) types.Dataset {
+	if shuffler, ok := dataset.(indexShuffler); ok {
+		return dataset.Select(shuffler.ShuffleIndices(seed)[:n])
+	}
+	return dataset.Shuffle(seed).Select(makeRange(n))
+}
+
 // Helper function to create a range of indices
 func makeRange(n int) []int {
 	indices := make([]int, n)
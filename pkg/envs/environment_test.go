@@ -0,0 +1,234 @@
+package envs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/rizome-dev/go-verifiers/pkg/parsers"
+	"github.com/rizome-dev/go-verifiers/pkg/rubrics"
+	"github.com/rizome-dev/go-verifiers/pkg/types"
+)
+
+// TestEnvironment_ConcurrentRollouts shares one environment across many goroutines.
+// Run with -race to detect unsynchronized access.
+func TestEnvironment_ConcurrentRollouts(t *testing.T) {
+	config := types.Config{
+		Model:        "test-model",
+		SystemPrompt: "You are a test assistant.",
+		MessageType:  "chat",
+	}
+
+	singleTurn := NewSingleTurnEnv(config)
+	singleTurn.SetParser(parsers.NewBaseParser())
+	singleTurn.SetRubric(rubrics.NewBaseRubric())
+
+	dialog := NewDialogMultiTurnEnv(config, 3, "DONE")
+	dialog.SetParser(parsers.NewLastLineParser())
+	dialog.SetRubric(rubrics.NewBaseRubric())
+
+	envs := map[string]interface {
+		Environment
+		FormatPrompt(string) []types.Message
+		SetParser(parsers.Parser)
+		SetRubric(rubrics.Rubric)
+	}{
+		"single_turn": singleTurn,
+		"dialog":      dialog,
+	}
+
+	mockClient := &MockClient{Response: "4\nDONE"}
+	ctx := context.Background()
+
+	for name, env := range envs {
+		t.Run(name, func(t *testing.T) {
+			var wg sync.WaitGroup
+			errs := make(chan error, 64)
+
+			for i := 0; i < 64; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					prompt := env.FormatPrompt("What is 2 + 2?")
+					rollout, err := env.Rollout(ctx, mockClient, config.Model, prompt, "DONE", config.SamplingArgs)
+					if err != nil {
+						errs <- err
+						return
+					}
+					if rollout.Response != "4\nDONE" {
+						t.Errorf("Unexpected response %q", rollout.Response)
+					}
+				}()
+			}
+
+			// Reconfigure while rollouts are in flight
+			for i := 0; i < 8; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					env.SetParser(parsers.NewLastLineParser())
+					env.SetRubric(rubrics.NewBaseRubric())
+					env.GetRewardFuncs()
+				}()
+			}
+
+			wg.Wait()
+			close(errs)
+			for err := range errs {
+				t.Errorf("Rollout failed: %v", err)
+			}
+		})
+	}
+}
+
+// countingClient answers each request with a unique, numbered response
+type countingClient struct {
+	MockClient
+	calls atomic.Int64
+}
+
+func (c *countingClient) CreateChatCompletion(ctx context.Context, model string, messages []types.Message, args types.SamplingArgs) (string, error) {
+	return fmt.Sprintf("response %d", c.calls.Add(1)), nil
+}
+
+// TestEnvironment_SharedPrompt runs concurrent rollouts on one prompt value with
+// spare capacity, as FormatPrompt can return. Run with -race to detect writes to
+// the shared backing array.
+func TestEnvironment_SharedPrompt(t *testing.T) {
+	config := types.Config{
+		Model:        "test-model",
+		SystemPrompt: "You are a test assistant.",
+		MessageType:  "chat",
+	}
+
+	singleTurn := NewSingleTurnEnv(config)
+	singleTurn.SetParser(parsers.NewBaseParser())
+	singleTurn.SetRubric(rubrics.NewBaseRubric())
+
+	selfConsistency, err := NewSelfConsistencyEnv(singleTurn, nil, 1)
+	if err != nil {
+		t.Fatalf("NewSelfConsistencyEnv failed: %v", err)
+	}
+
+	envs := map[string]Environment{
+		"single_turn":      singleTurn,
+		"self_consistency": selfConsistency,
+	}
+
+	prompt := make([]types.Message, 0, 8)
+	prompt = append(prompt, singleTurn.FormatPrompt("What is 2 + 2?")...)
+	ctx := context.Background()
+
+	for name, env := range envs {
+		t.Run(name, func(t *testing.T) {
+			client := &countingClient{}
+			rollouts := make([]*types.Rollout, 32)
+
+			var wg sync.WaitGroup
+			for i := range rollouts {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					rollout, err := env.Rollout(ctx, client, config.Model, prompt, "4", config.SamplingArgs)
+					if err != nil {
+						t.Errorf("Rollout failed: %v", err)
+						return
+					}
+					rollouts[i] = rollout
+				}()
+			}
+			wg.Wait()
+
+			for _, rollout := range rollouts {
+				if rollout == nil {
+					continue
+				}
+				last := rollout.Messages[len(rollout.Messages)-1]
+				if last.Content != rollout.Response {
+					t.Errorf("Transcript ends with %q, want its own response %q", last.Content, rollout.Response)
+				}
+			}
+			if len(prompt) != 2 {
+				t.Errorf("Shared prompt changed length to %d", len(prompt))
+			}
+		})
+	}
+}
+
+func TestEnvironment_LogsRollout(t *testing.T) {
+	config := types.Config{
+		Model:       "test-model",
+		MessageType: "chat",
+	}
+
+	env := NewSingleTurnEnv(config)
+	env.SetParser(parsers.NewBaseParser())
+	env.SetRubric(rubrics.NewBaseRubric())
+
+	var buf bytes.Buffer
+	env.SetLogger(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+
+	prompt := env.FormatPrompt("What is 2 + 2?")
+	if _, err := env.Rollout(context.Background(), &MockClient{Response: "4"}, config.Model, prompt, "4", config.SamplingArgs); err != nil {
+		t.Fatalf("Rollout failed: %v", err)
+	}
+
+	records := make(map[string]map[string]interface{})
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var record map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("invalid log line %q: %v", line, err)
+		}
+		records[record["msg"].(string)] = record
+	}
+
+	if _, ok := records["model request finished"]; !ok {
+		t.Errorf("Expected a model request record, got %v", buf.String())
+	}
+	finished, ok := records["rollout finished"]
+	if !ok {
+		t.Fatalf("Expected a rollout finished record, got %v", buf.String())
+	}
+	if finished["level"] != "INFO" {
+		t.Errorf("level = %v, want INFO", finished["level"])
+	}
+	if finished["score"] != 1.0 {
+		t.Errorf("score = %v, want 1", finished["score"])
+	}
+	if finished["turns"] != 1.0 {
+		t.Errorf("turns = %v, want 1", finished["turns"])
+	}
+}
+
+func TestBaseEnvironment_AppendFormatInstructions(t *testing.T) {
+	parser, err := parsers.NewXMLParser([]interface{}{"think", "answer"}, "answer")
+	if err != nil {
+		t.Fatalf("Failed to create parser: %v", err)
+	}
+
+	env := NewBaseEnvironment(types.Config{SystemPrompt: "Solve the problem."})
+	env.AppendFormatInstructions()
+	if env.systemPrompt != "Solve the problem." {
+		t.Errorf("Expected no change without a parser, got %q", env.systemPrompt)
+	}
+
+	env.SetParser(parser)
+	env.AppendFormatInstructions()
+	env.AppendFormatInstructions()
+
+	skeleton := "<think>\n...\n</think>\n<answer>\n...\n</answer>"
+	if !strings.HasPrefix(env.systemPrompt, "Solve the problem.\n\n") || strings.Count(env.systemPrompt, skeleton) != 1 {
+		t.Errorf("Expected the XML skeleton appended once, got %q", env.systemPrompt)
+	}
+
+	messages := env.FormatPrompt("What is 6 * 7?")
+	if !strings.Contains(messages[0].Content, parser.GetFormatStr()) {
+		t.Errorf("Expected formatted prompts to include the format, got %q", messages[0].Content)
+	}
+}
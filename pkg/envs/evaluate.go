@@ -0,0 +1,144 @@
+package envs
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/rizome-dev/go-verifiers/pkg/types"
+	"github.com/rizome-dev/go-verifiers/pkg/utils"
+)
+
+// EvalOptions configures Evaluate. Zero values use the defaults.
+type EvalOptions struct {
+	MaxConcurrent int                // Concurrent rollouts (default DatasetMaxConcurrent)
+	NumExamples   int                // Evaluate a seeded random subset of this size; <= 0 uses all items
+	Seed          int64              // Seed used to pick the subset
+	SamplingArgs  types.SamplingArgs // Sampling arguments passed to every rollout (default: the environment's own)
+	Timeout       time.Duration      // Per-rollout timeout (default 30s; utils.NoTimeout disables it)
+}
+
+// EvalResult aggregates the rollouts of an evaluation
+type EvalResult struct {
+	MeanScore float64          // Mean score over successful rollouts
+	Scores    []float64        // Per-item scores in dataset order; 0 for failed items
+	Errors    []error          // Per-item errors in dataset order; nil for successful items
+	NumErrors int              // Number of failed rollouts
+	Rollouts  []*types.Rollout // Per-item rollouts in dataset order; nil for failed items
+}
+
+// itemPromptFormatter is implemented by environments that can build a prompt from a dataset item
+type itemPromptFormatter interface {
+	PromptFromItem(item map[string]interface{}) (interface{}, error)
+	FormatPromptFromItem(item map[string]interface{}) ([]types.Message, error)
+}
+
+// samplingArgsProvider is implemented by environments with configured sampling arguments
+type samplingArgsProvider interface {
+	GetSamplingArgs() types.SamplingArgs
+}
+
+// taskRouter is implemented by environments that dispatch rollouts by task name
+type taskRouter interface {
+	RolloutForTask(ctx context.Context, client types.Client, model string, task string, prompt interface{}, answer string, samplingArgs types.SamplingArgs) (*types.Rollout, error)
+}
+
+// RolloutItem rolls out env on a single dataset item. The prompt is built with the
+// environment's PromptFromItem, so the item's "system_prompt" and "few_shot" columns
+// apply; multi-turn environments get the chat messages in completion mode too, since
+// they render their own transcripts. The answer is read from the "answer" column. Environments that route by
+// task, such as EnvGroup, receive the item's "task" column.
+func RolloutItem(ctx context.Context, env Environment, client types.Client, model string, item map[string]interface{}, samplingArgs types.SamplingArgs) (*types.Rollout, error) {
+	formatter, ok := env.(itemPromptFormatter)
+	if !ok {
+		return nil, fmt.Errorf("environment %T cannot format prompts from dataset items", env)
+	}
+	var prompt interface{}
+	var err error
+	if _, ok := env.(MultiTurnEnvironment); ok {
+		// Multi-turn environments render completion transcripts themselves, so they
+		// keep the item's system prompt and few-shot turns in either mode
+		prompt, err = formatter.FormatPromptFromItem(item)
+	} else {
+		prompt, err = formatter.PromptFromItem(item)
+	}
+	if err != nil {
+		return nil, err
+	}
+	answer, _ := item["answer"].(string)
+	if router, ok := env.(taskRouter); ok {
+		if task, ok := item["task"].(string); ok {
+			return router.RolloutForTask(ctx, client, model, task, prompt, answer, samplingArgs)
+		}
+	}
+	return env.Rollout(ctx, client, model, prompt, answer, samplingArgs)
+}
+
+// Evaluate runs env over a dataset and aggregates the scores. Each item is rolled
+// out with RolloutItem, so prompts match the environment's message type and honour
+// per-item columns. Items are read from the dataset as they are rolled out, so
+// streaming datasets are never loaded whole.
+//
+// Evaluate takes the environment as an argument, like BaseMultiTurnRollout, because a
+// method on the embedded BaseEnvironment could not reach the concrete Rollout.
+// Individual rollout failures are counted in the result; an error is returned only
+// when the evaluation cannot start.
+func Evaluate(ctx context.Context, env Environment, client types.Client, model string, dataset types.Dataset, opts EvalOptions) (*EvalResult, error) {
+	if _, ok := env.(itemPromptFormatter); !ok {
+		return nil, fmt.Errorf("environment %T cannot format prompts from dataset items", env)
+	}
+	if dataset == nil {
+		return nil, fmt.Errorf("dataset is nil")
+	}
+
+	if opts.NumExamples > 0 && opts.NumExamples < dataset.Len() {
+		dataset = sampleDataset(dataset, opts.NumExamples, opts.Seed)
+	}
+	if opts.MaxConcurrent <= 0 {
+		opts.MaxConcurrent = DatasetMaxConcurrent
+	}
+	if provider, ok := env.(samplingArgsProvider); ok && reflect.ValueOf(opts.SamplingArgs).IsZero() {
+		opts.SamplingArgs = provider.GetSamplingArgs()
+	}
+
+	indices := make([]int, dataset.Len())
+	for i := range indices {
+		indices[i] = i
+	}
+
+	rollout := func(ctx context.Context, index int) (*types.Rollout, error) {
+		item := dataset.Get(index)
+		if item == nil {
+			return nil, fmt.Errorf("item could not be read from the dataset")
+		}
+		return RolloutItem(ctx, env, client, model, item, opts.SamplingArgs)
+	}
+
+	processor := utils.NewBatchProcessor[int, *types.Rollout](opts.MaxConcurrent, opts.Timeout)
+	results := processor.Process(ctx, indices, rollout)
+
+	result := &EvalResult{
+		Scores:   make([]float64, len(results)),
+		Errors:   make([]error, len(results)),
+		Rollouts: make([]*types.Rollout, len(results)),
+	}
+
+	total := 0.0
+	for _, res := range results {
+		if res.Error != nil {
+			result.Errors[res.Index] = fmt.Errorf("item %d: %w", res.Index, res.Error)
+			result.NumErrors++
+			continue
+		}
+		result.Scores[res.Index] = res.Result.Score
+		result.Rollouts[res.Index] = res.Result
+		total += res.Result.Score
+	}
+
+	if succeeded := len(results) - result.NumErrors; succeeded > 0 {
+		result.MeanScore = total / float64(succeeded)
+	}
+
+	return result, nil
+}
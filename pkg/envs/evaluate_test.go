@@ -0,0 +1,202 @@
+package envs
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/rizome-dev/go-verifiers/pkg/parsers"
+	"github.com/rizome-dev/go-verifiers/pkg/rubrics"
+	"github.com/rizome-dev/go-verifiers/pkg/types"
+)
+
+func TestEvaluate_MeanScore(t *testing.T) {
+	config := types.Config{
+		Model:       "test-model",
+		MessageType: "chat",
+	}
+
+	env := NewSingleTurnEnv(config)
+	env.SetParser(parsers.NewBaseParser())
+	env.SetRubric(rubrics.NewBaseRubric())
+
+	dataset := types.NewSimpleDataset([]map[string]interface{}{
+		{"question": "What is 2 + 2?", "answer": "4"},
+		{"question": "What is 1 + 3?", "answer": "4"},
+		{"question": "What is 2 + 3?", "answer": "5"},
+		{"prompt": "What is 3 + 1?", "answer": "4"},
+	})
+
+	mockClient := &MockClient{Response: "4"}
+	result, err := Evaluate(context.Background(), env, mockClient, config.Model, dataset, EvalOptions{MaxConcurrent: 2})
+	if err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+
+	if result.NumErrors != 0 {
+		t.Fatalf("Expected no errors, got %v", result.Errors)
+	}
+	if result.MeanScore != 0.75 {
+		t.Errorf("Expected mean score 0.75, got %v", result.MeanScore)
+	}
+	if result.Scores[2] != 0.0 || result.Scores[3] != 1.0 {
+		t.Errorf("Per-item scores out of order: %v", result.Scores)
+	}
+
+	// Subsets are drawn with the seed
+	subset, err := Evaluate(context.Background(), env, mockClient, config.Model, dataset, EvalOptions{NumExamples: 2, Seed: 7})
+	if err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+	if len(subset.Scores) != 2 {
+		t.Errorf("Expected 2 scores for NumExamples=2, got %d", len(subset.Scores))
+	}
+}
+
+func TestEvaluate_CountsErrors(t *testing.T) {
+	env := NewSingleTurnEnv(types.Config{MessageType: "chat"})
+	env.SetParser(parsers.NewBaseParser())
+	env.SetRubric(rubrics.NewBaseRubric())
+
+	dataset := types.NewSimpleDataset([]map[string]interface{}{
+		{"question": "What is 2 + 2?", "answer": "4"},
+		{"answer": "missing question"},
+	})
+
+	result, err := Evaluate(context.Background(), env, &MockClient{Response: "4"}, "test-model", dataset, EvalOptions{})
+	if err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+	if result.NumErrors != 1 || result.Errors[1] == nil {
+		t.Errorf("Expected the item without a question to fail, got %v", result.Errors)
+	}
+	if result.MeanScore != 1.0 {
+		t.Errorf("Expected mean over successful items to be 1.0, got %v", result.MeanScore)
+	}
+}
+
+func TestEvaluate_CompletionMode(t *testing.T) {
+	env := NewSingleTurnCompletionEnv(types.Config{SystemPrompt: "Be brief."})
+	env.SetParser(parsers.NewBaseParser())
+	env.SetRubric(rubrics.NewBaseRubric())
+
+	dataset := types.NewSimpleDataset([]map[string]interface{}{
+		{"question": "2 + 2 =", "answer": "4"},
+		{"question": "1 + 3 =", "answer": "4"},
+	})
+	client := &completionClient{
+		MockClient: MockClient{Error: errors.New("chat completions are not supported")},
+		responses:  []string{"4", "4"},
+	}
+
+	result, err := Evaluate(context.Background(), env, client, "test-model", dataset, EvalOptions{MaxConcurrent: 1})
+	if err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+	if result.NumErrors != 0 {
+		t.Fatalf("Expected no errors, got %v", result.Errors)
+	}
+	if result.MeanScore != 1.0 {
+		t.Errorf("Expected mean score 1.0, got %v", result.MeanScore)
+	}
+	if !reflect.DeepEqual(client.prompts, []string{"2 + 2 =", "1 + 3 ="}) {
+		t.Errorf("Expected the questions as completion prompts, got %q", client.prompts)
+	}
+}
+
+func TestEvaluate_CompletionModeMultiTurn(t *testing.T) {
+	env := NewDialogMultiTurnEnv(types.Config{MessageType: "completion"}, 3, "DONE")
+	env.SetParser(parsers.NewBaseParser())
+	env.SetRubric(rubrics.NewBaseRubric())
+
+	dataset := types.NewSimpleDataset([]map[string]interface{}{
+		{"question": "2 + 2 =", "answer": "4 DONE"},
+		{"question": "1 + 3 =", "answer": "4 DONE"},
+	})
+	client := &completionClient{
+		MockClient: MockClient{Error: errors.New("chat completions are not supported")},
+		responses:  []string{"4 DONE", "4 DONE"},
+	}
+
+	result, err := Evaluate(context.Background(), env, client, "test-model", dataset, EvalOptions{MaxConcurrent: 1})
+	if err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+	if result.NumErrors != 0 {
+		t.Fatalf("Expected no errors, got %v", result.Errors)
+	}
+	if result.MeanScore != 1.0 {
+		t.Errorf("Expected mean score 1.0, got %v", result.MeanScore)
+	}
+	want := []string{"user: 2 + 2 =\n\nassistant: ", "user: 1 + 3 =\n\nassistant: "}
+	if !reflect.DeepEqual(client.prompts, want) {
+		t.Errorf("Expected rendered transcripts %q, got %q", want, client.prompts)
+	}
+}
+
+func TestEvaluate_CompletionModeMultiTurnKeepsSystemPrompt(t *testing.T) {
+	env := NewDialogMultiTurnEnv(types.Config{MessageType: "completion", SystemPrompt: "Be brief."}, 3, "DONE")
+
+	dataset := types.NewSimpleDataset([]map[string]interface{}{
+		{"question": "2 + 2 =", "answer": "4"},
+		{"question": "1 + 3 =", "answer": "4", "system_prompt": "Show your work."},
+	})
+	client := &completionClient{
+		MockClient: MockClient{Error: errors.New("chat completions are not supported")},
+		responses:  []string{"4 DONE", "4 DONE"},
+	}
+
+	result, err := Evaluate(context.Background(), env, client, "test-model", dataset, EvalOptions{MaxConcurrent: 1})
+	if err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+	if result.NumErrors != 0 {
+		t.Fatalf("Expected no errors, got %v", result.Errors)
+	}
+	want := []string{
+		"system: Be brief.\n\nuser: 2 + 2 =\n\nassistant: ",
+		"system: Show your work.\n\nuser: 1 + 3 =\n\nassistant: ",
+	}
+	if !reflect.DeepEqual(client.prompts, want) {
+		t.Errorf("Expected transcripts with the system prompts %q, got %q", want, client.prompts)
+	}
+}
+
+func TestEvaluate_SamplingArgs(t *testing.T) {
+	env := NewSingleTurnEnv(types.Config{
+		MessageType:  "chat",
+		SamplingArgs: types.SamplingArgs{Temperature: 0.3, MaxTokens: 64},
+	})
+	env.SetParser(parsers.NewBaseParser())
+	env.SetRubric(rubrics.NewBaseRubric())
+
+	dataset := types.NewSimpleDataset([]map[string]interface{}{
+		{"question": "What is 2 + 2?", "answer": "4"},
+	})
+
+	tests := []struct {
+		name            string
+		samplingArgs    types.SamplingArgs
+		wantTemperature float64
+		wantMaxTokens   int
+	}{
+		{name: "environment default", wantTemperature: 0.3, wantMaxTokens: 64},
+		{name: "explicit", samplingArgs: types.SamplingArgs{Temperature: 0.9}, wantTemperature: 0.9},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &argsRecordingClient{MockClient: MockClient{Response: "4"}}
+			if _, err := Evaluate(context.Background(), env, client, "test-model", dataset, EvalOptions{SamplingArgs: tt.samplingArgs}); err != nil {
+				t.Fatalf("Evaluate failed: %v", err)
+			}
+			if len(client.Args) != 1 {
+				t.Fatalf("Expected 1 request, got %d", len(client.Args))
+			}
+			if got := client.Args[0]; got.Temperature != tt.wantTemperature || got.MaxTokens != tt.wantMaxTokens {
+				t.Errorf("Sampling args = %+v, want temperature %v and max tokens %d", got, tt.wantTemperature, tt.wantMaxTokens)
+			}
+		})
+	}
+}
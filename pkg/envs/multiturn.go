@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/rizome-dev/go-verifiers/pkg/rubrics"
 	"github.com/rizome-dev/go-verifiers/pkg/types"
 )
 
@@ -17,8 +18,66 @@ type MultiTurnEnv struct {
 // MultiTurnEnvironment extends Environment with multi-turn specific methods
 type MultiTurnEnvironment interface {
 	Environment
-	IsCompleted(ctx context.Context, messages []types.Message, state map[string]interface{}) bool
-	EnvResponse(ctx context.Context, messages []types.Message, state map[string]interface{}) (types.Message, map[string]interface{}, error)
+	IsCompleted(ctx context.Context, messages []types.Message, state *types.State) bool
+	EnvResponse(ctx context.Context, messages []types.Message, state *types.State) (types.Message, *types.State, error)
+}
+
+// ControlledMultiTurnEnvironment is optionally implemented by multi-turn environments
+// whose environment response can end the rollout, e.g. when the task has just
+// succeeded or failed. BaseMultiTurnRollout prefers EnvResponseWithControl over
+// EnvResponse when it is available.
+//
+// IsCompleted is checked first after every assistant turn; EnvResponseWithControl
+// is only called when IsCompleted returned false. When it reports done, its message
+// is appended as the final message and the rollout stops without asking the model
+// again. Environments can leave a reward hint in state, which the rollout returns.
+type ControlledMultiTurnEnvironment interface {
+	MultiTurnEnvironment
+	EnvResponseWithControl(ctx context.Context, messages []types.Message, state *types.State) (types.Message, *types.State, bool, error)
+}
+
+// TurnSamplingEnvironment is optionally implemented by multi-turn environments that
+// vary sampling args between turns, e.g. a creative first planning turn followed by
+// deterministic ones. SamplingArgsForTurn is called before each model request with
+// the zero-based turn, the current state and the rollout's sampling args, and
+// returns the args to use for that turn. base shares its Stop slice and ExtraBody
+// map with the caller, so copy them, e.g. with Clone, before making changes. MultiTurnEnv provides a
+// default that returns base unchanged.
+type TurnSamplingEnvironment interface {
+	MultiTurnEnvironment
+	SamplingArgsForTurn(turn int, state *types.State, base types.SamplingArgs) types.SamplingArgs
+}
+
+// samplingArgsForTurn returns the sampling args env chooses for turn, or base when
+// env has no SamplingArgsForTurn hook. Any environment with the hook qualifies,
+// so NativeToolEnv, which has no EnvResponse, can use it too.
+func samplingArgsForTurn(env Environment, turn int, state *types.State, base types.SamplingArgs) types.SamplingArgs {
+	if turnSampling, ok := env.(interface {
+		SamplingArgsForTurn(turn int, state *types.State, base types.SamplingArgs) types.SamplingArgs
+	}); ok {
+		return turnSampling.SamplingArgsForTurn(turn, state, base)
+	}
+	return base
+}
+
+// InitialMessageEnvironment is optionally implemented by multi-turn environments
+// that speak first, e.g. to present the opening state of a puzzle or game.
+// BaseMultiTurnRollout calls InitialEnvMessage once before the first model turn;
+// a non-nil message is appended to the transcript, nil leaves the prompt as is.
+// The opening message does not count as a turn.
+type InitialMessageEnvironment interface {
+	MultiTurnEnvironment
+	InitialEnvMessage(ctx context.Context, state *types.State) (*types.Message, error)
+}
+
+// envResponseWithControl calls EnvResponseWithControl when env implements it and
+// otherwise wraps EnvResponse, which never ends the rollout
+func envResponseWithControl(ctx context.Context, env MultiTurnEnvironment, messages []types.Message, state *types.State) (types.Message, *types.State, bool, error) {
+	if controlled, ok := env.(ControlledMultiTurnEnvironment); ok {
+		return controlled.EnvResponseWithControl(ctx, messages, state)
+	}
+	msg, newState, err := env.EnvResponse(ctx, messages, state)
+	return msg, newState, false, err
 }
 
 // NewMultiTurnEnv creates a new multi-turn environment
@@ -32,22 +91,44 @@ func NewMultiTurnEnv(config types.Config, maxTurns int) *MultiTurnEnv {
 	}
 }
 
+// SamplingArgsForTurn returns base for every turn. Environments embedding
+// MultiTurnEnv override it to change sampling args per turn.
+func (e *MultiTurnEnv) SamplingArgsForTurn(turn int, state *types.State, base types.SamplingArgs) types.SamplingArgs {
+	return base
+}
+
+// multiTurnMessages returns prompt as a message list. A string prompt, such as the
+// completion-mode prompt of PromptFromItem, becomes a single user message.
+func multiTurnMessages(prompt interface{}) ([]types.Message, error) {
+	switch p := prompt.(type) {
+	case []types.Message:
+		return p, nil
+	case string:
+		return []types.Message{{Role: "user", Content: p}}, nil
+	default:
+		return nil, fmt.Errorf("multi-turn environment requires a []types.Message or string prompt, got %T", prompt)
+	}
+}
+
 // BaseMultiTurnRollout implements the common rollout logic for multi-turn environments
+// The final state is returned in Rollout.State so environments can score from it.
 func BaseMultiTurnRollout(ctx context.Context, env MultiTurnEnvironment, client types.Client, model string, prompt interface{}, answer string, samplingArgs types.SamplingArgs, maxTurns int) (*types.Rollout, error) {
-	// Ensure prompt is a message list
-	messages, ok := prompt.([]types.Message)
-	if !ok {
-		return nil, fmt.Errorf("multi-turn environment requires []types.Message prompt, got %T", prompt)
+	messages, err := multiTurnMessages(prompt)
+	if err != nil {
+		return nil, err
 	}
+	ctx, usage := types.WithUsageTracking(ctx)
 
 	// Make a copy of messages to avoid modifying the original
 	workingMessages := make([]types.Message, len(messages))
 	copy(workingMessages, messages)
 
-	// Initialize state
-	state := map[string]interface{}{
-		"answer": answer,
-	}
+	// Initialize state; prompt_length lets environments tell the prompt, including
+	// any few-shot examples it carries, apart from the conversation
+	state := types.NewState(map[string]interface{}{
+		"answer":        answer,
+		"prompt_length": len(messages),
+	})
 
 	// Track completion messages
 	completion := make([]types.Message, 0)
@@ -57,21 +138,51 @@ func BaseMultiTurnRollout(ctx context.Context, env MultiTurnEnvironment, client
 		maxTurns = 10
 	}
 
-	// Run the multi-turn conversation
+	logger := envLogger(env)
+
+	// Let the environment open the conversation
+	if opener, ok := env.(InitialMessageEnvironment); ok {
+		msg, err := opener.InitialEnvMessage(ctx, state)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get initial environment message: %w", err)
+		}
+		if msg != nil {
+			workingMessages = append(workingMessages, *msg)
+			completion = append(completion, *msg)
+		}
+	}
+
+	// Run the multi-turn conversation, recording which exit condition fires
+	terminated := types.TerminatedMaxTurns
+	var modelErr *types.ModelError
 	for turn < maxTurns {
+		// Stop promptly once the context is cancelled
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("rollout cancelled at turn %d: %w", turn, ctx.Err())
+		default:
+		}
+
 		// Check if already completed
 		if env.IsCompleted(ctx, workingMessages, state) {
+			terminated = types.TerminatedCompleted
 			break
 		}
 
 		// Get model response
-		response, err := client.CreateChatCompletion(ctx, model, workingMessages, samplingArgs)
+		turnArgs := samplingArgsForTurn(env, turn, state, samplingArgs)
+		response, err := multiTurnResponse(ctx, env, client, model, workingMessages, turnArgs)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get model response at turn %d: %w", turn, err)
 		}
 
-		// Check for errors in response
-		hasError := strings.HasPrefix(response, "[ERROR]")
+		// An "[ERROR] ..." response is not model output; stop without recording it
+		if err, ok := types.ParseModelError(response); ok {
+			modelErr = err
+			terminated = err.Reason
+			turn++
+			break
+		}
 
 		// Add assistant message
 		assistantMsg := types.Message{
@@ -81,14 +192,19 @@ func BaseMultiTurnRollout(ctx context.Context, env MultiTurnEnvironment, client
 		workingMessages = append(workingMessages, assistantMsg)
 		completion = append(completion, assistantMsg)
 		turn++
+		logger.DebugContext(ctx, "turn completed", "turn", turn, "response_chars", len(response))
 
 		// Check completion conditions
-		if env.IsCompleted(ctx, workingMessages, state) || turn >= maxTurns || hasError {
+		if env.IsCompleted(ctx, workingMessages, state) {
+			terminated = types.TerminatedCompleted
+			break
+		}
+		if turn >= maxTurns {
 			break
 		}
 
 		// Get environment response
-		envMsg, newState, err := env.EnvResponse(ctx, workingMessages, state)
+		envMsg, newState, done, err := envResponseWithControl(ctx, env, workingMessages, state)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get environment response at turn %d: %w", turn, err)
 		}
@@ -97,11 +213,18 @@ func BaseMultiTurnRollout(ctx context.Context, env MultiTurnEnvironment, client
 		// Add environment message
 		workingMessages = append(workingMessages, envMsg)
 		completion = append(completion, envMsg)
+
+		// The environment ended the rollout
+		if done {
+			terminated = types.TerminatedCompleted
+			break
+		}
 	}
+	logger.DebugContext(ctx, "multi-turn loop finished", "turns", turn, "terminated", string(terminated))
 
-	// Extract final response for scoring
+	// Extract final response for scoring; a failed rollout has none
 	finalResponse := ""
-	if len(completion) > 0 {
+	if modelErr == nil && len(completion) > 0 {
 		// Find last assistant message
 		for i := len(completion) - 1; i >= 0; i-- {
 			if completion[i].Role == "assistant" {
@@ -116,14 +239,36 @@ func BaseMultiTurnRollout(ctx context.Context, env MultiTurnEnvironment, client
 
 	// Create rollout result
 	rollout := &types.Rollout{
-		Messages: workingMessages,
-		Response: finalResponse,
-		Score:    0.0, // Concrete implementations should handle scoring
+		Messages:   workingMessages,
+		Response:   finalResponse,
+		Score:      0.0, // Concrete implementations should handle scoring
+		State:      state.Map(),
+		Terminated: terminated,
+		Error:      modelErr,
+		Model:      model,
+		Usage:      usage(),
+
+		SamplingArgs: samplingArgs.Clone(),
 	}
 
 	return rollout, nil
 }
 
+// messagesResponder is implemented by environments embedding BaseEnvironment, whose
+// message type decides between chat and completion requests
+type messagesResponder interface {
+	getMessagesResponse(ctx context.Context, client types.Client, model string, messages []types.Message, samplingArgs types.SamplingArgs) (string, error)
+}
+
+// multiTurnResponse requests the next assistant turn through the environment when
+// it supports completion mode, and as a chat completion otherwise
+func multiTurnResponse(ctx context.Context, env MultiTurnEnvironment, client types.Client, model string, messages []types.Message, samplingArgs types.SamplingArgs) (string, error) {
+	if responder, ok := env.(messagesResponder); ok {
+		return responder.getMessagesResponse(ctx, client, model, messages, samplingArgs)
+	}
+	return client.CreateChatCompletion(ctx, model, messages, samplingArgs)
+}
+
 // Example implementation of a simple dialog multi-turn environment
 type DialogMultiTurnEnv struct {
 	*MultiTurnEnv
@@ -142,7 +287,7 @@ func NewDialogMultiTurnEnv(config types.Config, maxTurns int, completionKeyword
 }
 
 // IsCompleted checks if the dialog is completed
-func (e *DialogMultiTurnEnv) IsCompleted(ctx context.Context, messages []types.Message, state map[string]interface{}) bool {
+func (e *DialogMultiTurnEnv) IsCompleted(ctx context.Context, messages []types.Message, state *types.State) bool {
 	if len(messages) == 0 {
 		return false
 	}
@@ -153,7 +298,7 @@ func (e *DialogMultiTurnEnv) IsCompleted(ctx context.Context, messages []types.M
 }
 
 // EnvResponse generates a simple acknowledgment
-func (e *DialogMultiTurnEnv) EnvResponse(ctx context.Context, messages []types.Message, state map[string]interface{}) (types.Message, map[string]interface{}, error) {
+func (e *DialogMultiTurnEnv) EnvResponse(ctx context.Context, messages []types.Message, state *types.State) (types.Message, *types.State, error) {
 	// Simple acknowledgment
 	msg := types.Message{
 		Role:    "user",
@@ -170,20 +315,24 @@ func (e *DialogMultiTurnEnv) Rollout(ctx context.Context, client types.Client, m
 	}
 
 	// Apply parsing and scoring
-	if e.parser != nil && rollout.Response != "" {
-		parsed, err := e.parser.Parse(ctx, rollout.Response)
+	parser, rubric := e.parserAndRubric()
+	if parser != nil && rollout.Response != "" {
+		parsed, err := parser.Parse(ctx, rollout.Response)
 		if err != nil {
 			return nil, fmt.Errorf("failed to parse response: %w", err)
 		}
 
-		if e.rubric != nil {
-			score, err := e.rubric.ComputeReward(ctx, parsed, answer)
+		if rubric != nil {
+			ctx := rubrics.WithRawResponse(ctx, rollout.Response)
+			score, metrics, err := computeReward(ctx, rubric, parsed, answer)
 			if err != nil {
 				return nil, fmt.Errorf("failed to compute reward: %w", err)
 			}
 			rollout.Score = score
+			rollout.Metrics = metrics
 		}
 	}
 
+	e.logRollout(ctx, rollout)
 	return rollout, nil
 }
\ No newline at end of file
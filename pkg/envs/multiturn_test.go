@@ -0,0 +1,313 @@
+package envs
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/rizome-dev/go-verifiers/pkg/tools"
+	"github.com/rizome-dev/go-verifiers/pkg/types"
+)
+
+// controlledEnv ends the rollout from its environment response after stopAfter responses
+type controlledEnv struct {
+	*MultiTurnEnv
+	stopAfter int
+}
+
+func (e *controlledEnv) IsCompleted(ctx context.Context, messages []types.Message, state *types.State) bool {
+	return false
+}
+
+func (e *controlledEnv) EnvResponse(ctx context.Context, messages []types.Message, state *types.State) (types.Message, *types.State, error) {
+	msg, state, _, err := e.EnvResponseWithControl(ctx, messages, state)
+	return msg, state, err
+}
+
+func (e *controlledEnv) EnvResponseWithControl(ctx context.Context, messages []types.Message, state *types.State) (types.Message, *types.State, bool, error) {
+	value, _ := state.Get("responses")
+	count, _ := value.(int)
+	count++
+	state.Set("responses", count)
+
+	if count >= e.stopAfter {
+		state.Set("reward_hint", 1.0)
+		return types.Message{Role: "user", Content: "Solved."}, state, true, nil
+	}
+	return types.Message{Role: "user", Content: "Keep going."}, state, false, nil
+}
+
+func (e *controlledEnv) Rollout(ctx context.Context, client types.Client, model string, prompt interface{}, answer string, samplingArgs types.SamplingArgs) (*types.Rollout, error) {
+	return BaseMultiTurnRollout(ctx, e, client, model, prompt, answer, samplingArgs, e.MaxTurns)
+}
+
+func TestBaseMultiTurnRollout_EnvResponseDone(t *testing.T) {
+	env := &controlledEnv{
+		MultiTurnEnv: NewMultiTurnEnv(types.Config{MessageType: "chat"}, 10),
+		stopAfter:    2,
+	}
+
+	prompt := env.FormatPrompt("Solve the task")
+	rollout, err := env.Rollout(context.Background(), &MockClient{Response: "attempt"}, "test-model", prompt, "", types.SamplingArgs{})
+	if err != nil {
+		t.Fatalf("Rollout failed: %v", err)
+	}
+
+	// user, assistant, env, assistant, env
+	if len(rollout.Messages) != 5 {
+		t.Fatalf("Expected rollout to stop after 2 environment responses, got %d messages", len(rollout.Messages))
+	}
+	if last := rollout.Messages[len(rollout.Messages)-1]; last.Content != "Solved." {
+		t.Errorf("Expected final environment message, got %q", last.Content)
+	}
+	if rollout.State["reward_hint"] != 1.0 {
+		t.Errorf("Expected reward hint in state, got %v", rollout.State["reward_hint"])
+	}
+}
+
+func TestBaseMultiTurnRollout_Terminated(t *testing.T) {
+	config := types.Config{MessageType: "chat"}
+	env, err := NewToolEnv(config, nil, 3)
+	if err != nil {
+		t.Fatalf("NewToolEnv failed: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		response string
+		expected types.TerminationReason
+	}{
+		{name: "answer", response: "<think>\nok\n</think>\n<answer>\n4\n</answer>", expected: types.TerminatedCompleted},
+		{name: "never answers", response: "<think>\nstill thinking\n</think>", expected: types.TerminatedMaxTurns},
+		{name: "error", response: "[ERROR] context_length_exceeded", expected: types.TerminatedError},
+		{name: "max tokens", response: "[ERROR] max_tokens_reached", expected: types.TerminatedMaxTokens},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prompt := env.FormatPrompt("What is 2 + 2?")
+			rollout, err := env.Rollout(context.Background(), &MockClient{Response: tt.response}, "test-model", prompt, "4", types.SamplingArgs{})
+			if err != nil {
+				t.Fatalf("Rollout failed: %v", err)
+			}
+			if rollout.Terminated != tt.expected {
+				t.Errorf("Terminated = %q, want %q", rollout.Terminated, tt.expected)
+			}
+		})
+	}
+}
+
+// cancellingClient cancels the rollout context after its first response
+type cancellingClient struct {
+	cancel context.CancelFunc
+	calls  int
+}
+
+func (c *cancellingClient) CreateChatCompletion(ctx context.Context, model string, messages []types.Message, args types.SamplingArgs) (string, error) {
+	c.calls++
+	if c.calls == 1 {
+		c.cancel()
+	}
+	return "attempt", nil
+}
+
+func (c *cancellingClient) CreateCompletion(ctx context.Context, model string, prompt string, args types.SamplingArgs) (string, error) {
+	return c.CreateChatCompletion(ctx, model, nil, args)
+}
+
+func TestBaseMultiTurnRollout_ContextCancelled(t *testing.T) {
+	env := &controlledEnv{
+		MultiTurnEnv: NewMultiTurnEnv(types.Config{MessageType: "chat"}, 10),
+		stopAfter:    10,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client := &cancellingClient{cancel: cancel}
+
+	prompt := env.FormatPrompt("Solve the task")
+	_, err := env.Rollout(ctx, client, "test-model", prompt, "", types.SamplingArgs{})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+	if client.calls != 1 {
+		t.Errorf("Expected no model calls after cancellation, got %d calls", client.calls)
+	}
+}
+
+// completionClient answers text completions in sequence and records each prompt
+type completionClient struct {
+	MockClient
+	responses []string
+	prompts   []string
+}
+
+func (c *completionClient) CreateCompletion(ctx context.Context, model string, prompt string, args types.SamplingArgs) (string, error) {
+	c.prompts = append(c.prompts, prompt)
+	return c.responses[len(c.prompts)-1], nil
+}
+
+func TestBaseMultiTurnRollout_CompletionMode(t *testing.T) {
+	env := &controlledEnv{
+		MultiTurnEnv: NewMultiTurnEnv(types.Config{SystemPrompt: "Be brief.", MessageType: "completion"}, 5),
+		stopAfter:    2,
+	}
+	client := &completionClient{
+		MockClient: MockClient{Error: errors.New("chat completions are not supported")},
+		responses:  []string{"Thinking", "42"},
+	}
+
+	prompt := env.FormatPrompt("What is 6 * 7?")
+	rollout, err := env.Rollout(context.Background(), client, "test-model", prompt, "42", types.SamplingArgs{})
+	if err != nil {
+		t.Fatalf("Rollout failed: %v", err)
+	}
+
+	first := "system: Be brief.\n\nuser: What is 6 * 7?\n\nassistant: "
+	second := first + "Thinking\n\nuser: Keep going.\n\nassistant: "
+	if len(client.prompts) != 2 || client.prompts[0] != first || client.prompts[1] != second {
+		t.Fatalf("Unexpected transcripts: %q", client.prompts)
+	}
+	if rollout.Response != "42" || len(rollout.Messages) != 6 {
+		t.Errorf("Unexpected rollout: response %q with %d messages", rollout.Response, len(rollout.Messages))
+	}
+
+	env.SetTranscriptTemplate("<|{role}|>{content}\n")
+	if got := env.RenderTranscript(prompt[1:]); got != "<|user|>What is 6 * 7?\n<|assistant|>" {
+		t.Errorf("RenderTranscript() with custom template = %q", got)
+	}
+}
+
+func TestBaseMultiTurnRollout_ModelErrorNotScored(t *testing.T) {
+	env, err := NewToolEnv(types.Config{MessageType: "chat"}, []tools.Tool{tools.NewCalculator()}, 5)
+	if err != nil {
+		t.Fatalf("NewToolEnv failed: %v", err)
+	}
+
+	// The context overflows after one tool call
+	client := &sequenceClient{Responses: []string{
+		"<think>\nadd\n</think>\n<tool>\n{\"name\": \"calculate\", \"args\": {\"expression\": \"2 + 2\"}}\n</tool>",
+		"[ERROR] context_length_exceeded",
+	}}
+
+	prompt := env.FormatPrompt("What is 2 + 2?")
+	rollout, err := env.Rollout(context.Background(), client, "test-model", prompt, "4", types.SamplingArgs{})
+	if err != nil {
+		t.Fatalf("Rollout failed: %v", err)
+	}
+
+	if rollout.Score != 0 || rollout.Response != "" {
+		t.Errorf("Expected an unscored rollout, got score %v for response %q", rollout.Score, rollout.Response)
+	}
+	if rollout.Terminated != types.TerminatedError {
+		t.Errorf("Terminated = %q, want %q", rollout.Terminated, types.TerminatedError)
+	}
+	var modelErr *types.ModelError
+	if !errors.As(error(rollout.Error), &modelErr) || modelErr.Message != "context_length_exceeded" {
+		t.Errorf("Expected a context length ModelError, got %v", rollout.Error)
+	}
+	for _, msg := range rollout.Messages {
+		if strings.HasPrefix(msg.Content, "[ERROR]") {
+			t.Errorf("Error sentinel recorded as a %s message", msg.Role)
+		}
+	}
+}
+
+// coolingEnv lowers the temperature after the first turn
+type coolingEnv struct {
+	*controlledEnv
+}
+
+func (e *coolingEnv) SamplingArgsForTurn(turn int, state *types.State, base types.SamplingArgs) types.SamplingArgs {
+	if turn > 0 {
+		base.Temperature = 0.1
+	}
+	return base
+}
+
+func (e *coolingEnv) Rollout(ctx context.Context, client types.Client, model string, prompt interface{}, answer string, samplingArgs types.SamplingArgs) (*types.Rollout, error) {
+	return BaseMultiTurnRollout(ctx, e, client, model, prompt, answer, samplingArgs, e.MaxTurns)
+}
+
+// argsRecordingClient records the sampling args of every request
+type argsRecordingClient struct {
+	MockClient
+	Args []types.SamplingArgs
+}
+
+func (c *argsRecordingClient) CreateChatCompletion(ctx context.Context, model string, messages []types.Message, args types.SamplingArgs) (string, error) {
+	c.Args = append(c.Args, args)
+	return c.MockClient.CreateChatCompletion(ctx, model, messages, args)
+}
+
+func TestBaseMultiTurnRollout_SamplingArgsForTurn(t *testing.T) {
+	env := &coolingEnv{&controlledEnv{
+		MultiTurnEnv: NewMultiTurnEnv(types.Config{MessageType: "chat"}, 10),
+		stopAfter:    3,
+	}}
+	client := &argsRecordingClient{MockClient: MockClient{Response: "attempt"}}
+
+	prompt := env.FormatPrompt("Solve the task")
+	if _, err := env.Rollout(context.Background(), client, "test-model", prompt, "", types.SamplingArgs{Temperature: 0.9}); err != nil {
+		t.Fatalf("Rollout failed: %v", err)
+	}
+
+	expected := []float64{0.9, 0.1, 0.1}
+	if len(client.Args) != len(expected) {
+		t.Fatalf("Expected %d requests, got %d", len(expected), len(client.Args))
+	}
+	for turn, temperature := range expected {
+		if client.Args[turn].Temperature != temperature {
+			t.Errorf("Turn %d: expected temperature %v, got %v", turn, temperature, client.Args[turn].Temperature)
+		}
+	}
+
+	// The default hook keeps the rollout's args
+	base := types.SamplingArgs{Temperature: 0.9}
+	if got := env.MultiTurnEnv.SamplingArgsForTurn(2, nil, base); got.Temperature != base.Temperature {
+		t.Errorf("Expected default hook to return base args, got %v", got.Temperature)
+	}
+}
+
+// openingEnv presents a puzzle before the model's first turn
+type openingEnv struct {
+	*controlledEnv
+}
+
+func (e *openingEnv) InitialEnvMessage(ctx context.Context, state *types.State) (*types.Message, error) {
+	state.Set("board", "3x3")
+	return &types.Message{Role: "user", Content: "The board is empty. Your move."}, nil
+}
+
+func (e *openingEnv) Rollout(ctx context.Context, client types.Client, model string, prompt interface{}, answer string, samplingArgs types.SamplingArgs) (*types.Rollout, error) {
+	return BaseMultiTurnRollout(ctx, e, client, model, prompt, answer, samplingArgs, e.MaxTurns)
+}
+
+func TestBaseMultiTurnRollout_InitialEnvMessage(t *testing.T) {
+	env := &openingEnv{&controlledEnv{
+		MultiTurnEnv: NewMultiTurnEnv(types.Config{MessageType: "chat"}, 10),
+		stopAfter:    1,
+	}}
+
+	prompt := env.FormatPrompt("Play tic-tac-toe")
+	rollout, err := env.Rollout(context.Background(), &MockClient{Response: "X in the center"}, "test-model", prompt, "", types.SamplingArgs{})
+	if err != nil {
+		t.Fatalf("Rollout failed: %v", err)
+	}
+
+	// prompt, opening, assistant, env
+	transcript := rollout.Messages[len(prompt):]
+	if len(transcript) != 3 {
+		t.Fatalf("Expected 3 messages after the prompt, got %+v", transcript)
+	}
+	if transcript[0].Content != "The board is empty. Your move." {
+		t.Errorf("Expected the transcript to start with the opening message, got %+v", transcript[0])
+	}
+	if transcript[1].Role != "assistant" {
+		t.Errorf("Expected the model to answer the opening message, got %+v", transcript[1])
+	}
+	if rollout.State["board"] != "3x3" {
+		t.Errorf("Expected opening state to be kept, got %v", rollout.State["board"])
+	}
+}
@@ -0,0 +1,189 @@
+package envs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/rizome-dev/go-verifiers/pkg/rubrics"
+	"github.com/rizome-dev/go-verifiers/pkg/tools"
+	"github.com/rizome-dev/go-verifiers/pkg/types"
+)
+
+// NativeToolEnv implements tool use through the model's native tool calling API.
+// Tool schemas are sent with every request, the tool calls in each assistant message
+// are executed with tools.ExecuteTool, and each result is appended as a "tool"
+// message. The rollout ends when the model answers without calling a tool.
+type NativeToolEnv struct {
+	*MultiTurnEnv
+	Tools           map[string]tools.Tool
+	ToolDefinitions []types.ToolDefinition
+	MaxResultChars  int // Truncate tool results to this many characters
+}
+
+// NewNativeToolEnv creates a native tool calling environment. The client passed to
+// Rollout must implement types.ToolCallingClient.
+func NewNativeToolEnv(config types.Config, toolList []tools.Tool, maxTurns int) *NativeToolEnv {
+	toolMap := make(map[string]tools.Tool, len(toolList))
+	definitions := make([]types.ToolDefinition, 0, len(toolList))
+
+	for _, tool := range toolList {
+		schema := tool.Schema()
+		toolMap[tool.Name()] = tool
+		definitions = append(definitions, types.ToolDefinition{
+			Type: "function",
+			Function: types.FunctionDefinition{
+				Name:        tool.Name(),
+				Description: tool.Description(),
+				Parameters:  schema.Parameters(),
+			},
+		})
+	}
+
+	return &NativeToolEnv{
+		MultiTurnEnv:    NewMultiTurnEnv(config, maxTurns),
+		Tools:           toolMap,
+		ToolDefinitions: definitions,
+		MaxResultChars:  DefaultMaxToolResultChars,
+	}
+}
+
+// Rollout runs the tool calling loop and scores the final assistant message
+func (e *NativeToolEnv) Rollout(ctx context.Context, client types.Client, model string, prompt interface{}, answer string, samplingArgs types.SamplingArgs) (*types.Rollout, error) {
+	return NativeToolRollout(ctx, e, e, client, model, prompt, answer, samplingArgs)
+}
+
+// NativeToolRollout runs the tool calling loop of e for env, the environment
+// embedding it. Like BaseMultiTurnRollout, it takes env so that hooks env overrides,
+// such as SamplingArgsForTurn, are called; environments embedding NativeToolEnv
+// that override a hook call it from their own Rollout.
+func NativeToolRollout(ctx context.Context, env Environment, e *NativeToolEnv, client types.Client, model string, prompt interface{}, answer string, samplingArgs types.SamplingArgs) (*types.Rollout, error) {
+	toolClient, ok := client.(types.ToolCallingClient)
+	if !ok {
+		return nil, fmt.Errorf("native tool calling requires a types.ToolCallingClient, got %T", client)
+	}
+
+	messages, err := multiTurnMessages(prompt)
+	if err != nil {
+		return nil, err
+	}
+	workingMessages := make([]types.Message, len(messages))
+	copy(workingMessages, messages)
+
+	if samplingArgs.Tools == nil {
+		samplingArgs.Tools = e.ToolDefinitions
+	}
+
+	state := types.NewState(map[string]interface{}{
+		"answer": answer,
+	})
+
+	// Track the tokens of the model calls apart from any made while scoring
+	modelCtx, usage := types.WithUsageTracking(ctx)
+	terminated := types.TerminatedMaxTurns
+	response := ""
+	var modelErr *types.ModelError
+	for turn := 0; turn < e.MaxTurns; turn++ {
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("rollout cancelled at turn %d: %w", turn, ctx.Err())
+		default:
+		}
+
+		turnArgs := samplingArgsForTurn(env, turn, state, samplingArgs)
+		msg, err := toolClient.CreateChatCompletionWithTools(modelCtx, model, workingMessages, turnArgs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get model response at turn %d: %w", turn, err)
+		}
+		// An "[ERROR] ..." response is not model output; stop without recording it
+		if err, ok := types.ParseModelError(msg.Content); ok {
+			modelErr = err
+			terminated = err.Reason
+			response = ""
+			break
+		}
+
+		msg.Role = "assistant"
+		workingMessages = append(workingMessages, msg)
+		response = msg.Content
+
+		// An answer without tool calls ends the rollout
+		if len(msg.ToolCalls) == 0 {
+			terminated = types.TerminatedCompleted
+			break
+		}
+
+		for _, call := range msg.ToolCalls {
+			workingMessages = append(workingMessages, types.Message{
+				Role:       "tool",
+				Content:    e.callTool(ctx, call, state),
+				ToolCallID: call.ID,
+			})
+		}
+	}
+
+	rollout := &types.Rollout{
+		Messages:   workingMessages,
+		Response:   response,
+		State:      state.Map(),
+		Terminated: terminated,
+		Error:      modelErr,
+		Model:      model,
+		Usage:      usage(),
+
+		SamplingArgs: samplingArgs.Clone(),
+	}
+
+	if err := e.score(ctx, rollout, answer); err != nil {
+		return nil, err
+	}
+
+	e.logRollout(ctx, rollout)
+	return rollout, nil
+}
+
+// callTool executes a native tool call and records it in state["tool_executions"]
+func (e *NativeToolEnv) callTool(ctx context.Context, call types.ToolCall, state *types.State) string {
+	args := make(map[string]interface{})
+	if strings.TrimSpace(call.Function.Arguments) != "" {
+		if err := json.Unmarshal([]byte(call.Function.Arguments), &args); err != nil {
+			result := fmt.Sprintf("Error: invalid arguments for %s: %v", call.Function.Name, err)
+			recordToolExecution(state, rubrics.NewToolExecution(call.Function.Name, nil, result, false))
+			return result
+		}
+	}
+
+	result := tools.ExecuteTool(ctx, e.Tools, &tools.ToolCall{Name: call.Function.Name, Args: args}, e.MaxResultChars)
+	success := !strings.HasPrefix(result, "Error:")
+	recordToolExecution(state, rubrics.NewToolExecution(call.Function.Name, args, result, success))
+	e.Logger().DebugContext(ctx, "tool executed", "tool", call.Function.Name, "success", success)
+	return result
+}
+
+// score parses the final response and applies the rubric with the execution trace
+func (e *NativeToolEnv) score(ctx context.Context, rollout *types.Rollout, answer string) error {
+	parser, rubric := e.parserAndRubric()
+	if rubric == nil || rollout.Error != nil {
+		return nil
+	}
+
+	parsed := rollout.Response
+	if parser != nil {
+		if answerText, err := parser.Parse(ctx, rollout.Response); err == nil && answerText != "" {
+			parsed = answerText
+		}
+	}
+
+	trace, _ := rollout.State["tool_executions"].([]rubrics.ToolExecution)
+	ctx = rubrics.WithToolTrace(rubrics.WithRawResponse(ctx, rollout.Response), trace)
+
+	score, metrics, err := computeReward(ctx, rubric, parsed, answer)
+	if err != nil {
+		return fmt.Errorf("failed to compute reward: %w", err)
+	}
+	rollout.Score = score
+	rollout.Metrics = metrics
+
+	return nil
+}
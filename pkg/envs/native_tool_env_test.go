@@ -0,0 +1,114 @@
+package envs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rizome-dev/go-verifiers/pkg/parsers"
+	"github.com/rizome-dev/go-verifiers/pkg/rubrics"
+	"github.com/rizome-dev/go-verifiers/pkg/tools"
+	"github.com/rizome-dev/go-verifiers/pkg/types"
+)
+
+// toolCallingClient returns scripted assistant messages in order
+type toolCallingClient struct {
+	MockClient
+	Messages []types.Message
+	Requests []types.SamplingArgs
+}
+
+func (c *toolCallingClient) CreateChatCompletionWithTools(ctx context.Context, model string, messages []types.Message, args types.SamplingArgs) (types.Message, error) {
+	c.Requests = append(c.Requests, args)
+	msg := c.Messages[0]
+	if len(c.Messages) > 1 {
+		c.Messages = c.Messages[1:]
+	}
+	return msg, nil
+}
+
+func TestNativeToolEnv_Rollout(t *testing.T) {
+	env := NewNativeToolEnv(types.Config{MessageType: "chat"}, []tools.Tool{tools.NewCalculator()}, 5)
+	env.SetParser(parsers.NewBaseParser())
+	env.SetRubric(rubrics.NewBaseRubric())
+
+	client := &toolCallingClient{Messages: []types.Message{
+		{Role: "assistant", ToolCalls: []types.ToolCall{{
+			ID:       "call_1",
+			Type:     "function",
+			Function: types.FunctionCall{Name: "calculate", Arguments: `{"expression": "6 * 7"}`},
+		}}},
+		{Role: "assistant", Content: "42"},
+	}}
+
+	prompt := env.FormatPrompt("What is 6 * 7?")
+	rollout, err := env.Rollout(context.Background(), client, "test-model", prompt, "42", types.SamplingArgs{})
+	if err != nil {
+		t.Fatalf("Rollout failed: %v", err)
+	}
+
+	if len(client.Requests) != 2 || len(client.Requests[0].Tools) != 1 {
+		t.Fatalf("Expected tool definitions sent with both requests, got %+v", client.Requests)
+	}
+	if params := client.Requests[0].Tools[0].Function.Parameters; params["type"] != "object" {
+		t.Errorf("Expected JSON Schema parameters, got %v", params)
+	}
+
+	var toolMsg *types.Message
+	for i := range rollout.Messages {
+		if rollout.Messages[i].Role == "tool" {
+			toolMsg = &rollout.Messages[i]
+		}
+	}
+	if toolMsg == nil || toolMsg.ToolCallID != "call_1" || toolMsg.Content != "42" {
+		t.Fatalf("Expected tool result 42 for call_1, got %+v", toolMsg)
+	}
+
+	if rollout.Score != 1.0 || rollout.Terminated != types.TerminatedCompleted {
+		t.Errorf("Expected completed rollout with score 1.0, got %v (%q)", rollout.Score, rollout.Terminated)
+	}
+	if trace, _ := rollout.State["tool_executions"].([]rubrics.ToolExecution); len(trace) != 1 || !trace[0].Success {
+		t.Errorf("Expected one successful execution, got %+v", trace)
+	}
+
+	if _, err := env.Rollout(context.Background(), &MockClient{Response: "42"}, "test-model", prompt, "42", types.SamplingArgs{}); err == nil {
+		t.Error("Expected error for a client without native tool calling")
+	}
+}
+
+// coolingNativeToolEnv lowers the temperature after the first turn
+type coolingNativeToolEnv struct {
+	*NativeToolEnv
+}
+
+func (e *coolingNativeToolEnv) SamplingArgsForTurn(turn int, state *types.State, base types.SamplingArgs) types.SamplingArgs {
+	if turn > 0 {
+		base.Temperature = 0.1
+	}
+	return base
+}
+
+func (e *coolingNativeToolEnv) Rollout(ctx context.Context, client types.Client, model string, prompt interface{}, answer string, samplingArgs types.SamplingArgs) (*types.Rollout, error) {
+	return NativeToolRollout(ctx, e, e.NativeToolEnv, client, model, prompt, answer, samplingArgs)
+}
+
+func TestNativeToolEnv_SamplingArgsForTurnOverride(t *testing.T) {
+	env := &coolingNativeToolEnv{NewNativeToolEnv(types.Config{MessageType: "chat"}, []tools.Tool{tools.NewCalculator()}, 5)}
+
+	client := &toolCallingClient{Messages: []types.Message{
+		{Role: "assistant", ToolCalls: []types.ToolCall{{
+			ID:       "call_1",
+			Type:     "function",
+			Function: types.FunctionCall{Name: "calculate", Arguments: `{"expression": "6 * 7"}`},
+		}}},
+		{Role: "assistant", Content: "42"},
+	}}
+
+	prompt := env.FormatPrompt("What is 6 * 7?")
+	if _, err := env.Rollout(context.Background(), client, "test-model", prompt, "42", types.SamplingArgs{Temperature: 0.9}); err != nil {
+		t.Fatalf("Rollout failed: %v", err)
+	}
+
+	if len(client.Requests) != 2 || client.Requests[0].Temperature != 0.9 || client.Requests[1].Temperature != 0.1 {
+		t.Errorf("Expected the overriding hook to cool the second turn, got %+v", client.Requests)
+	}
+}
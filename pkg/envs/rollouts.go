@@ -0,0 +1,77 @@
+package envs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rizome-dev/go-verifiers/pkg/types"
+	"github.com/rizome-dev/go-verifiers/pkg/utils"
+)
+
+// RolloutInput is one prompt to roll out with RunRollouts. When Item is set, the
+// input is rolled out with RolloutItem and Prompt and Answer are ignored, so the
+// item's own system prompt, few-shot examples and task apply.
+type RolloutInput struct {
+	Prompt       interface{}
+	Answer       string
+	Item         map[string]interface{}
+	SamplingArgs types.SamplingArgs
+}
+
+// rolloutConfig holds the settings applied by RolloutOptions
+type rolloutConfig struct {
+	timeout time.Duration
+}
+
+// RolloutOption configures optional RunRollouts behavior
+type RolloutOption func(*rolloutConfig)
+
+// WithRolloutTimeout cancels each rollout after timeout. Values <= 0 leave
+// rollouts without a timeout, which is the default.
+func WithRolloutTimeout(timeout time.Duration) RolloutOption {
+	return func(c *rolloutConfig) {
+		c.timeout = timeout
+	}
+}
+
+// RunRollouts rolls out env on each input with at most maxConcurrent rollouts in
+// flight (default DatasetMaxConcurrent). Rollouts have no timeout unless one is set
+// with WithRolloutTimeout, so long multi-turn rollouts run until they finish or ctx
+// is cancelled. Rollouts and errors are returned in input order; a failed item has
+// a nil rollout and its error, and does not stop the others.
+func RunRollouts(ctx context.Context, env Environment, client types.Client, model string, items []RolloutInput, maxConcurrent int, opts ...RolloutOption) ([]*types.Rollout, []error) {
+	config := rolloutConfig{}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	if maxConcurrent <= 0 {
+		maxConcurrent = DatasetMaxConcurrent
+	}
+	timeout := config.timeout
+	if timeout <= 0 {
+		timeout = utils.NoTimeout
+	}
+
+	rollout := func(ctx context.Context, input RolloutInput) (*types.Rollout, error) {
+		if input.Item != nil {
+			return RolloutItem(ctx, env, client, model, input.Item, input.SamplingArgs)
+		}
+		return env.Rollout(ctx, client, model, input.Prompt, input.Answer, input.SamplingArgs)
+	}
+
+	processor := utils.NewBatchProcessor[RolloutInput, *types.Rollout](maxConcurrent, timeout)
+	results := processor.Process(ctx, items, rollout)
+
+	rollouts := make([]*types.Rollout, len(items))
+	errs := make([]error, len(items))
+	for _, res := range results {
+		if res.Error != nil {
+			errs[res.Index] = fmt.Errorf("item %d: %w", res.Index, res.Error)
+			continue
+		}
+		rollouts[res.Index] = res.Result
+	}
+
+	return rollouts, errs
+}
@@ -0,0 +1,132 @@
+package envs
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rizome-dev/go-verifiers/pkg/parsers"
+	"github.com/rizome-dev/go-verifiers/pkg/rubrics"
+	"github.com/rizome-dev/go-verifiers/pkg/types"
+)
+
+func TestRunRollouts_OrderAndErrors(t *testing.T) {
+	config := types.Config{
+		Model:       "test-model",
+		MessageType: "chat",
+	}
+
+	env := NewSingleTurnEnv(config)
+	env.SetParser(parsers.NewBaseParser())
+	env.SetRubric(rubrics.NewBaseRubric())
+
+	items := []RolloutInput{
+		{Prompt: env.FormatPrompt("What is 2 + 2?"), Answer: "4"},
+		{Prompt: env.FormatPrompt("What is 2 + 3?"), Answer: "5"},
+		{Prompt: "not a message list", Answer: "4"}, // Chat mode rejects string prompts
+		{Prompt: env.FormatPrompt("What is 3 + 1?"), Answer: "4"},
+		{Prompt: env.FormatPrompt("What is 3 + 3?"), Answer: "6"},
+	}
+	wantScores := []float64{1.0, 0.0, 0.0, 1.0, 0.0}
+
+	rollouts, errs := RunRollouts(context.Background(), env, &MockClient{Response: "4"}, config.Model, items, 2)
+	if len(rollouts) != len(items) || len(errs) != len(items) {
+		t.Fatalf("Expected %d results, got %d rollouts and %d errors", len(items), len(rollouts), len(errs))
+	}
+
+	for i, want := range wantScores {
+		if i == 2 {
+			if errs[i] == nil || rollouts[i] != nil {
+				t.Errorf("Expected item 2 to fail, got rollout %v and error %v", rollouts[i], errs[i])
+			}
+			continue
+		}
+		if errs[i] != nil {
+			t.Fatalf("Item %d failed: %v", i, errs[i])
+		}
+		if rollouts[i].Score != want {
+			t.Errorf("Item %d score = %v, want %v", i, rollouts[i].Score, want)
+		}
+		if got := rollouts[i].Messages[len(rollouts[i].Messages)-2].Content; got != items[i].Prompt.([]types.Message)[0].Content {
+			t.Errorf("Item %d rollout is out of order: prompt %q", i, got)
+		}
+	}
+}
+
+// deadlineClient reports whether requests carry a deadline, and with Block waits
+// for the request context to end
+type deadlineClient struct {
+	MockClient
+	Block       bool
+	hasDeadline atomic.Bool
+}
+
+func (c *deadlineClient) CreateChatCompletion(ctx context.Context, model string, messages []types.Message, args types.SamplingArgs) (string, error) {
+	_, ok := ctx.Deadline()
+	c.hasDeadline.Store(ok)
+	if c.Block {
+		<-ctx.Done()
+		return "", ctx.Err()
+	}
+	return "4", nil
+}
+
+func TestRunRollouts_Timeout(t *testing.T) {
+	env := NewSingleTurnEnv(types.Config{MessageType: "chat"})
+	env.SetParser(parsers.NewBaseParser())
+	env.SetRubric(rubrics.NewBaseRubric())
+	items := []RolloutInput{{Prompt: env.FormatPrompt("What is 2 + 2?"), Answer: "4"}}
+
+	// 0 leaves rollouts without a deadline
+	client := &deadlineClient{}
+	if _, errs := RunRollouts(context.Background(), env, client, "test-model", items, 1); errs[0] != nil {
+		t.Fatalf("Rollout failed: %v", errs[0])
+	}
+	if client.hasDeadline.Load() {
+		t.Error("Expected no per-rollout deadline with timeout 0")
+	}
+
+	// A timeout cancels a rollout that runs too long
+	client = &deadlineClient{Block: true}
+	_, errs := RunRollouts(context.Background(), env, client, "test-model", items, 1, WithRolloutTimeout(10*time.Millisecond))
+	if !errors.Is(errs[0], context.DeadlineExceeded) {
+		t.Errorf("Expected the rollout to time out, got %v", errs[0])
+	}
+}
+
+func TestRunRollouts_Item(t *testing.T) {
+	env := NewSingleTurnEnv(types.Config{Model: "test-model", MessageType: "chat", SystemPrompt: "Be brief."})
+	env.SetParser(parsers.NewBaseParser())
+	env.SetRubric(rubrics.NewBaseRubric())
+
+	items := []RolloutInput{{Item: map[string]interface{}{
+		"question":      "What is 2 + 2?",
+		"answer":        "4",
+		"system_prompt": "Answer with a number.",
+		"few_shot": []types.Message{
+			{Role: "user", Content: "What is 1 + 1?"},
+			{Role: "assistant", Content: "2"},
+		},
+	}}}
+
+	rollouts, errs := RunRollouts(context.Background(), env, &MockClient{Response: "4"}, "test-model", items, 1)
+	if errs[0] != nil {
+		t.Fatalf("Item rollout failed: %v", errs[0])
+	}
+
+	want := []string{"Answer with a number.", "What is 1 + 1?", "2", "What is 2 + 2?", "4"}
+	messages := rollouts[0].Messages
+	if len(messages) != len(want) {
+		t.Fatalf("Expected the item's system prompt and few-shot in the rollout, got %v", messages)
+	}
+	for i, content := range want {
+		if messages[i].Content != content {
+			t.Errorf("Message %d = %q, want %q", i, messages[i].Content, content)
+		}
+	}
+	if rollouts[0].Score != 1.0 {
+		t.Errorf("Expected the item's answer to be used, got score %v", rollouts[0].Score)
+	}
+}
@@ -0,0 +1,126 @@
+package envs
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/rizome-dev/go-verifiers/pkg/parsers"
+	"github.com/rizome-dev/go-verifiers/pkg/rubrics"
+	"github.com/rizome-dev/go-verifiers/pkg/types"
+)
+
+// SelfConsistencyEnv samples a single-turn environment K times and scores the
+// plurality answer. Answers are compared after trimming whitespace, and ties go to
+// the answer seen first. Samples that parse to an empty answer or are "[ERROR] ..."
+// model errors abstain. The vote counts are returned in Rollout.State["votes"],
+// the parsed answers in sample order in State["answers"] ("" for abstentions), the
+// number of abstentions in State["abstentions"] and the winner in
+// State["majority_answer"]. When every sample abstains the rollout is not scored.
+type SelfConsistencyEnv struct {
+	*SingleTurnEnv
+	Parser parsers.Parser // Extracts the answer voted on; nil uses the inner parser
+	K      int            // Number of samples
+}
+
+// NewSelfConsistencyEnv wraps inner so each rollout takes a majority vote over k
+// samples, scored with inner's rubric
+func NewSelfConsistencyEnv(inner *SingleTurnEnv, parser parsers.Parser, k int) (*SelfConsistencyEnv, error) {
+	if inner == nil {
+		return nil, fmt.Errorf("inner environment is required")
+	}
+	if k < 1 {
+		return nil, fmt.Errorf("k must be at least 1, got %d", k)
+	}
+	return &SelfConsistencyEnv{
+		SingleTurnEnv: inner,
+		Parser:        parser,
+		K:             k,
+	}, nil
+}
+
+// Rollout samples K responses, votes on their parsed answers and scores the winner
+func (e *SelfConsistencyEnv) Rollout(ctx context.Context, client types.Client, model string, prompt interface{}, answer string, samplingArgs types.SamplingArgs) (*types.Rollout, error) {
+	innerParser, rubric := e.parserAndRubric()
+	parser := e.Parser
+	if parser == nil {
+		parser = innerParser
+	}
+
+	responses := make([]string, 0, e.K)
+	answers := make([]string, 0, e.K)
+	votes := make(map[string]int)
+	abstentions := 0
+	modelCtx, usage := types.WithUsageTracking(ctx)
+	for i := 0; i < e.K; i++ {
+		response, err := e.GetModelResponse(modelCtx, prompt, client, model, samplingArgs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get model response for sample %d: %w", i, err)
+		}
+		responses = append(responses, response)
+
+		// A model error is not an answer
+		if _, ok := types.ParseModelError(response); ok {
+			answers = append(answers, "")
+			abstentions++
+			continue
+		}
+
+		parsed := response
+		if parser != nil {
+			parsed, err = parser.Parse(ctx, response)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse sample %d: %w", i, err)
+			}
+		}
+		parsed = strings.TrimSpace(parsed)
+
+		answers = append(answers, parsed)
+		if parsed == "" {
+			abstentions++
+			continue
+		}
+		votes[parsed]++
+	}
+
+	// The first sample with the most votes wins, so ties go to the first-seen answer
+	winner := -1
+	for i, parsed := range answers {
+		if parsed != "" && (winner < 0 || votes[parsed] > votes[answers[winner]]) {
+			winner = i
+		}
+	}
+	voted := winner >= 0
+	if !voted {
+		winner = 0
+	}
+	majority := answers[winner]
+
+	rollout := &types.Rollout{
+		Messages: e.singleTurnMessages(prompt, responses[winner]),
+		Response: responses[winner],
+		Model:    model,
+		Usage:    usage(),
+		State: map[string]interface{}{
+			"votes":           votes,
+			"answers":         answers,
+			"abstentions":     abstentions,
+			"majority_answer": majority,
+		},
+		SamplingArgs: samplingArgs.Clone(),
+		SystemPrompt: e.unsentSystemPrompt(),
+	}
+
+	if rubric != nil && voted {
+		ctx := rubrics.WithRawResponse(ctx, responses[winner])
+		score, metrics, err := computeReward(ctx, rubric, majority, answer)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute reward: %w", err)
+		}
+		rollout.Score = score
+		rollout.Metrics = metrics
+	}
+
+	e.logRollout(ctx, rollout)
+	return rollout, nil
+}
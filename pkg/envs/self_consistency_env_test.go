@@ -0,0 +1,89 @@
+package envs
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/rizome-dev/go-verifiers/pkg/parsers"
+	"github.com/rizome-dev/go-verifiers/pkg/rubrics"
+	"github.com/rizome-dev/go-verifiers/pkg/types"
+)
+
+func TestSelfConsistencyEnv_Rollout(t *testing.T) {
+	tests := []struct {
+		name      string
+		k         int
+		responses []string
+		majority  string
+		votes     map[string]int
+		abstained int
+		score     float64
+	}{
+		{
+			name:      "two of three agree",
+			k:         3,
+			responses: []string{"6 * 7 is\n41", "6 * 7 is\n42", "Multiplying gives\n42"},
+			majority:  "42",
+			votes:     map[string]int{"41": 1, "42": 2},
+			score:     1.0,
+		},
+		{
+			name:      "tie goes to first seen",
+			k:         2,
+			responses: []string{"41", "42"},
+			majority:  "41",
+			votes:     map[string]int{"41": 1, "42": 1},
+			score:     0.0,
+		},
+		{
+			name:      "parse misses and model errors abstain",
+			k:         4,
+			responses: []string{"\n", "[ERROR] context_length_exceeded", " ", "6 * 7 is\n42"},
+			majority:  "42",
+			votes:     map[string]int{"42": 1},
+			abstained: 3,
+			score:     1.0,
+		},
+		{
+			name:      "all abstain",
+			k:         2,
+			responses: []string{"", "[ERROR] timeout"},
+			majority:  "",
+			votes:     map[string]int{},
+			abstained: 2,
+			score:     0.0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inner := NewSingleTurnEnv(types.Config{MessageType: "chat"})
+			inner.SetRubric(rubrics.NewBaseRubric())
+			env, err := NewSelfConsistencyEnv(inner, parsers.NewLastLineParser(), tt.k)
+			if err != nil {
+				t.Fatalf("NewSelfConsistencyEnv failed: %v", err)
+			}
+
+			client := &sequenceClient{Responses: tt.responses}
+			prompt := env.FormatPrompt("What is 6 * 7?")
+			rollout, err := env.Rollout(context.Background(), client, "test-model", prompt, "42", types.SamplingArgs{})
+			if err != nil {
+				t.Fatalf("Rollout failed: %v", err)
+			}
+
+			if client.calls != tt.k {
+				t.Errorf("Expected %d samples, got %d", tt.k, client.calls)
+			}
+			if rollout.State["majority_answer"] != tt.majority || rollout.Score != tt.score {
+				t.Errorf("Majority %v scored %v, want %q scored %v", rollout.State["majority_answer"], rollout.Score, tt.majority, tt.score)
+			}
+			if !reflect.DeepEqual(rollout.State["votes"], tt.votes) {
+				t.Errorf("votes = %v, want %v", rollout.State["votes"], tt.votes)
+			}
+			if rollout.State["abstentions"] != tt.abstained {
+				t.Errorf("abstentions = %v, want %d", rollout.State["abstentions"], tt.abstained)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/rizome-dev/go-verifiers/pkg/rubrics"
 	"github.com/rizome-dev/go-verifiers/pkg/types"
 )
 
@@ -21,16 +22,19 @@ func NewSingleTurnEnv(config types.Config) *SingleTurnEnv {
 
 // Rollout performs a single-turn rollout
 func (e *SingleTurnEnv) Rollout(ctx context.Context, client types.Client, model string, prompt interface{}, answer string, samplingArgs types.SamplingArgs) (*types.Rollout, error) {
-	// Get model response
-	response, err := e.GetModelResponse(ctx, prompt, client, model, samplingArgs)
+	// Get model response, tracking its tokens apart from any made while scoring
+	modelCtx, usage := types.WithUsageTracking(ctx)
+	response, err := e.GetModelResponse(modelCtx, prompt, client, model, samplingArgs)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get model response: %w", err)
 	}
 
+	parser, rubric := e.parserAndRubric()
+
 	// Parse the response
 	parsed := response
-	if e.parser != nil {
-		parsed, err = e.parser.Parse(ctx, response)
+	if parser != nil {
+		parsed, err = parser.Parse(ctx, response)
 		if err != nil {
 			return nil, fmt.Errorf("failed to parse response: %w", err)
 		}
@@ -38,8 +42,10 @@ func (e *SingleTurnEnv) Rollout(ctx context.Context, client types.Client, model
 
 	// Compute reward
 	score := 0.0
-	if e.rubric != nil {
-		score, err = e.rubric.ComputeReward(ctx, parsed, answer)
+	var metrics map[string]float64
+	if rubric != nil {
+		ctx := rubrics.WithRawResponse(ctx, response)
+		score, metrics, err = computeReward(ctx, rubric, parsed, answer)
 		if err != nil {
 			return nil, fmt.Errorf("failed to compute reward: %w", err)
 		}
@@ -47,22 +53,53 @@ func (e *SingleTurnEnv) Rollout(ctx context.Context, client types.Client, model
 
 	// Create rollout result
 	rollout := &types.Rollout{
-		Response: response,
-		Score:    score,
+		Messages:     e.singleTurnMessages(prompt, response),
+		Response:     response,
+		Score:        score,
+		Metrics:      metrics,
+		Model:        model,
+		Usage:        usage(),
+		SamplingArgs: samplingArgs.Clone(),
+		SystemPrompt: e.unsentSystemPrompt(),
 	}
 
-	// Add messages if chat mode
+	e.logRollout(ctx, rollout)
+	return rollout, nil
+}
+
+// singleTurnMessages returns the prompt followed by the response; completion
+// prompts become a user/assistant transcript. Completion prompts are sent as is,
+// so the transcript holds no system message; Rollout.SystemPrompt records it.
+func (e *SingleTurnEnv) singleTurnMessages(prompt interface{}, response string) []types.Message {
 	if e.messageType == "chat" {
 		messages, ok := prompt.([]types.Message)
 		if ok {
-			rollout.Messages = append(messages, types.Message{
+			// Copy so concurrent rollouts sharing one prompt never write to its backing array
+			transcript := make([]types.Message, len(messages), len(messages)+1)
+			copy(transcript, messages)
+			return append(transcript, types.Message{
 				Role:    "assistant",
 				Content: response,
 			})
 		}
+	} else if text, ok := prompt.(string); ok {
+		return []types.Message{
+			{Role: "user", Content: text},
+			{Role: "assistant", Content: response},
+		}
 	}
+	return nil
+}
 
-	return rollout, nil
+// unsentSystemPrompt returns the system prompt in completion mode, where it is not
+// part of the prompt or transcript, and "" in chat mode
+func (e *SingleTurnEnv) unsentSystemPrompt() string {
+	if e.messageType == "chat" {
+		return ""
+	}
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.systemPrompt
 }
 
 // SingleTurnCompletionEnv is a convenience type for completion-mode single turn
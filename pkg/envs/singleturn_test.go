@@ -2,6 +2,7 @@ package envs
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/rizome-dev/go-verifiers/pkg/parsers"
@@ -99,6 +100,16 @@ func TestSingleTurnEnv_CompletionMode(t *testing.T) {
 	if rollout.Score != 1.0 {
 		t.Errorf("Expected score 1.0, got %.2f", rollout.Score)
 	}
+
+	if len(rollout.Messages) != 2 {
+		t.Fatalf("Expected 2 messages, got %d", len(rollout.Messages))
+	}
+	if msg := rollout.Messages[0]; msg.Role != "user" || msg.Content != prompt {
+		t.Errorf("Expected user prompt message, got %+v", msg)
+	}
+	if msg := rollout.Messages[1]; msg.Role != "assistant" || msg.Content != "The answer is 4" {
+		t.Errorf("Expected assistant response message, got %+v", msg)
+	}
 }
 
 func TestBaseEnvironment_FormatPrompt(t *testing.T) {
@@ -124,4 +135,240 @@ func TestBaseEnvironment_FormatPrompt(t *testing.T) {
 	if messages[3].Role != "user" || messages[3].Content != "Test prompt" {
 		t.Errorf("Incorrect user message")
 	}
-}
\ No newline at end of file
+}
+
+func TestSingleTurnEnv_RolloutMetrics(t *testing.T) {
+	config := types.Config{
+		Model:       "test-model",
+		MessageType: "chat",
+	}
+
+	env := NewSingleTurnEnv(config)
+	mathRubric, err := rubrics.NewMathRubric()
+	if err != nil {
+		t.Fatalf("Failed to create rubric: %v", err)
+	}
+	env.SetRubric(mathRubric)
+
+	mockClient := &MockClient{
+		Response: "<think>\n2 + 2 = 4\n</think>\n<answer>\n4\n</answer>",
+	}
+
+	ctx := context.Background()
+	rollout, err := env.Rollout(ctx, mockClient, config.Model, env.FormatPrompt("What is 2 + 2?"), "4", config.SamplingArgs)
+	if err != nil {
+		t.Fatalf("Rollout failed: %v", err)
+	}
+
+	if rollout.Metrics["correct_answer"] != 1.0 {
+		t.Errorf("Expected correct_answer metric 1.0, got %.2f", rollout.Metrics["correct_answer"])
+	}
+	if _, ok := rollout.Metrics["format"]; !ok {
+		t.Errorf("Expected format metric in breakdown")
+	}
+}
+
+func TestSingleTurnEnv_MetricsScoredOnce(t *testing.T) {
+	env := NewSingleTurnEnv(types.Config{MessageType: "chat"})
+
+	judgeCalls := 0
+	rubric := rubrics.NewMultiMetricRubric()
+	rubric.AddMetric("judge", func(ctx context.Context, parsed, groundTruth string) (float64, error) {
+		judgeCalls++
+		return 1.0, nil
+	}, 0.5)
+	rubric.AddMetric("flaky", func(ctx context.Context, parsed, groundTruth string) (float64, error) {
+		return 0.0, errors.New("metric unavailable")
+	}, 0.5)
+	env.SetRubric(rubric)
+
+	rollout, err := env.Rollout(context.Background(), &MockClient{Response: "4"}, "test-model", env.FormatPrompt("What is 2 + 2?"), "4", types.SamplingArgs{})
+	if err != nil {
+		t.Fatalf("Expected a failing metric not to fail the rollout, got %v", err)
+	}
+	if judgeCalls != 1 {
+		t.Errorf("Expected the judge metric to run once per rollout, ran %d times", judgeCalls)
+	}
+	if rollout.Score != 1.0 {
+		t.Errorf("Expected score 1.0 from the surviving metric, got %v", rollout.Score)
+	}
+	if _, ok := rollout.Metrics["flaky"]; ok || rollout.Metrics["judge"] != 1.0 {
+		t.Errorf("Expected only the judge metric in the breakdown, got %v", rollout.Metrics)
+	}
+}
+
+func TestBaseEnvironment_FormatPromptFromItemFewShot(t *testing.T) {
+	config := types.Config{
+		SystemPrompt: "System message",
+		FewShot: []types.Message{
+			{Role: "user", Content: "Default input"},
+			{Role: "assistant", Content: "Default output"},
+		},
+	}
+
+	// Item few-shot as decoded from JSON
+	item := map[string]interface{}{
+		"question": "Test prompt",
+		"few_shot": []interface{}{
+			map[string]interface{}{"role": "user", "content": "Retrieved input"},
+			map[string]interface{}{"role": "assistant", "content": "Retrieved output"},
+		},
+	}
+
+	env := NewBaseEnvironment(config)
+
+	messages, err := env.FormatPromptFromItem(item)
+	if err != nil {
+		t.Fatalf("FormatPromptFromItem failed: %v", err)
+	}
+	if len(messages) != 4 { // system + 2 item few-shot + user
+		t.Fatalf("Expected 4 messages, got %d", len(messages))
+	}
+	if messages[1].Content != "Retrieved input" || messages[2].Content != "Retrieved output" {
+		t.Errorf("Expected item few-shot to replace default, got %v", messages[1:3])
+	}
+
+	env.SetFewShotMode(types.FewShotAppend)
+
+	messages, err = env.FormatPromptFromItem(item)
+	if err != nil {
+		t.Fatalf("FormatPromptFromItem failed: %v", err)
+	}
+	if len(messages) != 6 { // system + 2 default + 2 item few-shot + user
+		t.Fatalf("Expected 6 messages, got %d", len(messages))
+	}
+	if messages[1].Content != "Default input" || messages[3].Content != "Retrieved input" {
+		t.Errorf("Expected item few-shot after default, got %v", messages[1:5])
+	}
+	if messages[5].Role != "user" || messages[5].Content != "Test prompt" {
+		t.Errorf("Incorrect user message")
+	}
+
+	// Items without few-shot fall back to the default
+	messages, err = env.FormatPromptFromItem(map[string]interface{}{"question": "Plain"})
+	if err != nil {
+		t.Fatalf("FormatPromptFromItem failed: %v", err)
+	}
+	if len(messages) != 4 {
+		t.Errorf("Expected default few-shot for plain item, got %d messages", len(messages))
+	}
+}
+
+func TestBaseEnvironment_FormatPromptFromItemSystemPrompt(t *testing.T) {
+	env := NewBaseEnvironment(types.Config{SystemPrompt: "Default system"})
+
+	tests := []struct {
+		name       string
+		item       map[string]interface{}
+		wantSystem string // empty means no system message
+	}{
+		{name: "default", item: map[string]interface{}{"question": "Q"}, wantSystem: "Default system"},
+		{name: "override", item: map[string]interface{}{"question": "Q", "system_prompt": "Answer in French."}, wantSystem: "Answer in French."},
+		{name: "drop", item: map[string]interface{}{"prompt": "Q", "system_prompt": ""}, wantSystem: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			messages, err := env.FormatPromptFromItem(tt.item)
+			if err != nil {
+				t.Fatalf("FormatPromptFromItem failed: %v", err)
+			}
+
+			wantLen := 2
+			if tt.wantSystem == "" {
+				wantLen = 1
+			}
+			if len(messages) != wantLen {
+				t.Fatalf("Expected %d messages, got %d", wantLen, len(messages))
+			}
+			if tt.wantSystem != "" && (messages[0].Role != "system" || messages[0].Content != tt.wantSystem) {
+				t.Errorf("Expected system prompt %q, got %+v", tt.wantSystem, messages[0])
+			}
+			if last := messages[len(messages)-1]; last.Role != "user" || last.Content != "Q" {
+				t.Errorf("Incorrect user message %+v", last)
+			}
+		})
+	}
+}
+
+func TestSingleTurnEnv_RecordsRunConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		messageType string
+		prompt      func(env *SingleTurnEnv) interface{}
+	}{
+		{"chat", "chat", func(env *SingleTurnEnv) interface{} { return env.FormatPrompt("What is 2 + 2?") }},
+		{"completion", "completion", func(env *SingleTurnEnv) interface{} { return "What is 2 + 2?" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := NewSingleTurnEnv(types.Config{SystemPrompt: "Answer briefly.", MessageType: tt.messageType})
+			args := types.SamplingArgs{Temperature: 0.3, Stop: []string{"</answer>"}, ExtraBody: map[string]interface{}{"top_k": 20}}
+
+			rollout, err := env.Rollout(context.Background(), &MockClient{Response: "4"}, "test-model", tt.prompt(env), "4", args)
+			if err != nil {
+				t.Fatalf("Rollout failed: %v", err)
+			}
+
+			if rollout.Model != "test-model" {
+				t.Errorf("Expected model test-model, got %q", rollout.Model)
+			}
+			if rollout.SamplingArgs == nil || rollout.SamplingArgs.Temperature != 0.3 {
+				t.Errorf("Expected temperature 0.3 to be recorded, got %+v", rollout.SamplingArgs)
+			}
+
+			// Reusing the args must not change the recorded ones
+			args.Stop[0] = "</think>"
+			args.ExtraBody["top_k"] = 40
+			if recorded := rollout.SamplingArgs; recorded.Stop[0] != "</answer>" || recorded.ExtraBody["top_k"] != 20 {
+				t.Errorf("Recorded sampling args changed with the caller's: %+v", recorded)
+			}
+			first := rollout.Messages[0]
+			if tt.messageType == "chat" {
+				if first.Role != "system" || first.Content != "Answer briefly." {
+					t.Errorf("Expected the system prompt first, got %+v", first)
+				}
+				return
+			}
+			// Completion prompts are sent without the system prompt
+			if first.Role != "user" || len(rollout.Messages) != 2 {
+				t.Errorf("Expected a user/assistant transcript, got %+v", rollout.Messages)
+			}
+			if rollout.SystemPrompt != "Answer briefly." {
+				t.Errorf("Expected the system prompt to be recorded, got %q", rollout.SystemPrompt)
+			}
+		})
+	}
+}
+
+// usageClient reports fixed token counts for every request
+type usageClient struct {
+	MockClient
+}
+
+func (c *usageClient) CreateChatCompletion(ctx context.Context, model string, messages []types.Message, args types.SamplingArgs) (string, error) {
+	types.AddUsage(ctx, types.Usage{PromptTokens: 10, CompletionTokens: 2, TotalTokens: 12})
+	return c.MockClient.CreateChatCompletion(ctx, model, messages, args)
+}
+
+func TestSingleTurnEnv_RecordsUsage(t *testing.T) {
+	env := NewSingleTurnEnv(types.Config{MessageType: "chat"})
+
+	rollout, err := env.Rollout(context.Background(), &MockClient{Response: "4"}, "test-model", env.FormatPrompt("What is 2 + 2?"), "4", types.SamplingArgs{})
+	if err != nil {
+		t.Fatalf("Rollout failed: %v", err)
+	}
+	if rollout.Usage != nil {
+		t.Errorf("Expected no usage from a client that reports none, got %+v", rollout.Usage)
+	}
+
+	rollout, err = env.Rollout(context.Background(), &usageClient{MockClient{Response: "4"}}, "test-model", env.FormatPrompt("What is 2 + 2?"), "4", types.SamplingArgs{})
+	if err != nil {
+		t.Fatalf("Rollout failed: %v", err)
+	}
+	want := types.Usage{PromptTokens: 10, CompletionTokens: 2, TotalTokens: 12}
+	if rollout.Usage == nil || *rollout.Usage != want {
+		t.Errorf("Usage = %+v, want %+v", rollout.Usage, want)
+	}
+}
@@ -20,7 +20,11 @@ type SmolaToolEnv struct {
 	ToolSchemas     []tools.ToolSchema
 	Parser          *parsers.SmolaParser
 	EnvParser       *parsers.XMLParser
-	ExcludeFewShot  bool
+	ExcludeFewShot  bool // Skip few-shot example messages when counting tool steps
+	fewShot         []types.Message
+
+	// MaxToolResultChars truncates tool results to this many characters; 0 means no limit
+	MaxToolResultChars int
 }
 
 // NewSmolaToolEnv creates a new Smola tool environment
@@ -50,8 +54,11 @@ func NewSmolaToolEnv(config types.Config, toolList []tools.Tool, maxTurns int) (
 		config.SystemPrompt = prompts.DefaultSmolaPromptTemplate
 	}
 	
-	toolDescriptions := tools.FormatToolDescriptions(toolList)
-	config.SystemPrompt = strings.ReplaceAll(config.SystemPrompt, "%s", toolDescriptions)
+	// Earlier releases filled %s with the tool descriptions
+	config.SystemPrompt, err = renderToolSystemPrompt(config.SystemPrompt, toolList, parser.GetFormatStr(), "%s", "{tool_descriptions}")
+	if err != nil {
+		return nil, err
+	}
 	
 	env := &SmolaToolEnv{
 		MultiTurnEnv:   NewMultiTurnEnv(config, maxTurns),
@@ -59,7 +66,10 @@ func NewSmolaToolEnv(config types.Config, toolList []tools.Tool, maxTurns int) (
 		ToolSchemas:    schemas,
 		Parser:         parser,
 		EnvParser:      envParser,
-		ExcludeFewShot: false,
+		ExcludeFewShot: true,
+		fewShot:        config.FewShot,
+
+		MaxToolResultChars: DefaultMaxToolResultChars,
 	}
 	
 	// Set parser and rubric
@@ -76,7 +86,7 @@ func NewSmolaToolEnv(config types.Config, toolList []tools.Tool, maxTurns int) (
 }
 
 // IsCompleted checks if the task is completed
-func (e *SmolaToolEnv) IsCompleted(ctx context.Context, messages []types.Message, state map[string]interface{}) bool {
+func (e *SmolaToolEnv) IsCompleted(ctx context.Context, messages []types.Message, state *types.State) bool {
 	if len(messages) == 0 {
 		return false
 	}
@@ -84,10 +94,15 @@ func (e *SmolaToolEnv) IsCompleted(ctx context.Context, messages []types.Message
 	// Count tool usage steps (excluding few-shot)
 	toolSteps := 0
 	startCounting := false
+	examples := e.fewShotExamples(messages, state)
 	
 	for _, msg := range messages {
+		if e.isFewShotMessage(msg, examples) {
+			continue
+		}
+		
 		// Start counting after few-shot examples
-		if !startCounting && msg.Role == "user" && !e.isFewShotMessage(msg) {
+		if !startCounting && msg.Role == "user" {
 			startCounting = true
 		}
 		
@@ -107,13 +122,13 @@ func (e *SmolaToolEnv) IsCompleted(ctx context.Context, messages []types.Message
 	}
 	
 	// Track tool steps in state
-	state["tool_steps"] = toolSteps
+	state.Set("tool_steps", toolSteps)
 	
 	return false
 }
 
 // EnvResponse generates environment response to tool calls
-func (e *SmolaToolEnv) EnvResponse(ctx context.Context, messages []types.Message, state map[string]interface{}) (types.Message, map[string]interface{}, error) {
+func (e *SmolaToolEnv) EnvResponse(ctx context.Context, messages []types.Message, state *types.State) (types.Message, *types.State, error) {
 	if len(messages) == 0 {
 		return types.Message{}, state, fmt.Errorf("no messages to process")
 	}
@@ -124,33 +139,34 @@ func (e *SmolaToolEnv) EnvResponse(ctx context.Context, messages []types.Message
 		return types.Message{}, state, fmt.Errorf("last message must be from assistant")
 	}
 	
-	// Parse for tool call
-	parsed, err := e.Parser.ParseSmola(lastMsg.Content, true)
-	if err != nil {
-		return types.Message{
-			Role:    "user",
-			Content: e.formatError("Failed to parse response. Please use the correct XML format."),
-		}, state, nil
-	}
-	
-	// Check if there's a tool call
-	toolJSON := parsed.Fields["tool"]
-	if toolJSON == "" {
+	// Collect every tool call in the message, in order
+	contents, _ := e.Parser.ParseSmolaAll(lastMsg.Content)
+	toolJSONs := contents["tool"]
+	if len(toolJSONs) == 0 || (len(toolJSONs) == 1 && toolJSONs[0] == "") {
 		return types.Message{
 			Role:    "user", 
 			Content: e.formatError("No tool call found. Use <tool>{json}</tool> to call a tool."),
 		}, state, nil
 	}
 	
-	// Execute tool call
-	result := e.callTool(ctx, toolJSON, 1024)
-	
-	// Track tool execution
-	if state["tool_executions"] == nil {
-		state["tool_executions"] = []rubrics.ToolExecution{}
+	// Execute the calls in sequence and return their results in one block
+	results := make([]string, 0, len(toolJSONs))
+	for _, toolJSON := range toolJSONs {
+		results = append(results, e.executeAndRecord(ctx, toolJSON, state))
 	}
 	
-	executions := state["tool_executions"].([]rubrics.ToolExecution)
+	// Format result as XML
+	response := fmt.Sprintf("<result>\n%s\n</result>", strings.Join(results, "\n\n"))
+	
+	return types.Message{
+		Role:    "user",
+		Content: response,
+	}, state, nil
+}
+
+// executeAndRecord runs one tool call and appends it to state["tool_executions"]
+func (e *SmolaToolEnv) executeAndRecord(ctx context.Context, toolJSON string, state *types.State) string {
+	result := e.callTool(ctx, toolJSON, e.MaxToolResultChars)
 	
 	// Parse tool call to track execution
 	var toolCall map[string]interface{}
@@ -168,21 +184,17 @@ func (e *SmolaToolEnv) EnvResponse(ctx context.Context, messages []types.Message
 		success = false
 	}
 	
-	executions = append(executions, rubrics.ToolExecution{
-		ToolName: toolName,
-		Args:     toolCall["args"].(map[string]interface{}),
-		Result:   result,
-		Success:  success,
-	})
-	state["tool_executions"] = executions
+	// Missing or non-object args are recorded as a failed call with empty args
+	args, ok := toolCall["args"].(map[string]interface{})
+	if !ok {
+		args = map[string]interface{}{}
+		success = false
+	}
 	
-	// Format result as XML
-	response := fmt.Sprintf("<result>\n%s\n</result>", result)
+	recordToolExecution(state, rubrics.NewToolExecution(toolName, args, result, success))
+	e.Logger().DebugContext(ctx, "tool executed", "tool", toolName, "success", success)
 	
-	return types.Message{
-		Role:    "user",
-		Content: response,
-	}, state, nil
+	return result
 }
 
 // callTool executes a tool based on JSON command
@@ -202,14 +214,40 @@ func (e *SmolaToolEnv) formatError(msg string) string {
 	return fmt.Sprintf("<result>\n%s\n</result>", msg)
 }
 
+// fewShotExamples returns the configured few-shot examples plus those the prompt
+// carried, such as an item's "few_shot" column: every non-system prompt message
+// before the final question
+func (e *SmolaToolEnv) fewShotExamples(messages []types.Message, state *types.State) []types.Message {
+	if state == nil {
+		return e.fewShot
+	}
+	value, ok := state.Get("prompt_length")
+	promptLength, isInt := value.(int)
+	if !ok || !isInt || promptLength < 2 || promptLength > len(messages) {
+		return e.fewShot
+	}
+	
+	examples := append([]types.Message{}, e.fewShot...)
+	for _, msg := range messages[:promptLength-1] {
+		if msg.Role != "system" {
+			examples = append(examples, msg)
+		}
+	}
+	return examples
+}
+
 // isFewShotMessage checks if a message is part of few-shot examples
-func (e *SmolaToolEnv) isFewShotMessage(msg types.Message) bool {
+func (e *SmolaToolEnv) isFewShotMessage(msg types.Message, examples []types.Message) bool {
 	if !e.ExcludeFewShot {
 		return false
 	}
 	
 	// Check if this message matches any few-shot example
-	// This is a simplified check - in practice, we'd compare with actual few-shot examples
+	for _, example := range examples {
+		if msg.Role == example.Role && msg.Content == example.Content {
+			return true
+		}
+	}
 	return false
 }
 
@@ -221,16 +259,19 @@ func (e *SmolaToolEnv) Rollout(ctx context.Context, client types.Client, model s
 	}
 	
 	// Enhanced scoring with execution trace
-	if smolaRubric, ok := e.rubric.(*rubrics.SmolaToolRubric); ok {
-		// Extract execution trace from state
-		var trace []rubrics.ToolExecution
-		// This is simplified - in practice we'd track actual executions from state
+	_, rubric := e.parserAndRubric()
+	if smolaRubric, ok := rubric.(*rubrics.SmolaToolRubric); ok && rollout.Error == nil {
+		// Score tool usage against the executions recorded during the rollout
+		trace, _ := rollout.State["tool_executions"].([]rubrics.ToolExecution)
 		
-		score, err := smolaRubric.ComputeRewardWithTrace(ctx, rollout.Response, answer, trace)
-		if err == nil {
-			rollout.Score = score
+		score, metrics, err := smolaRubric.ComputeRewardWithBreakdown(rubrics.WithToolTrace(ctx, trace), rollout.Response, answer)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute reward: %w", err)
 		}
+		rollout.Score = score
+		rollout.Metrics = metrics
 	}
 	
+	e.logRollout(ctx, rollout)
 	return rollout, nil
 }
\ No newline at end of file
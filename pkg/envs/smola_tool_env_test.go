@@ -0,0 +1,204 @@
+package envs
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/rizome-dev/go-verifiers/pkg/rubrics"
+	"github.com/rizome-dev/go-verifiers/pkg/tools"
+	"github.com/rizome-dev/go-verifiers/pkg/types"
+)
+
+func TestSmolaToolEnv_EnvResponseMalformedArgs(t *testing.T) {
+	env, err := NewSmolaToolEnv(types.Config{MessageType: "chat"}, []tools.Tool{tools.NewCalculator()}, 3)
+	if err != nil {
+		t.Fatalf("NewSmolaToolEnv failed: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		toolJSON string
+	}{
+		{name: "missing args", toolJSON: `{"name":"x"}`},
+		{name: "non-object args", toolJSON: `{"name":"calculator","args":"2 + 2"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			messages := []types.Message{
+				{Role: "user", Content: "What is 2 + 2?"},
+				{Role: "assistant", Content: "<think>\nuse a tool\n</think>\n<tool>" + tt.toolJSON + "</tool>"},
+			}
+			state := types.NewState(nil)
+
+			msg, state, err := env.EnvResponse(context.Background(), messages, state)
+			if err != nil {
+				t.Fatalf("EnvResponse failed: %v", err)
+			}
+			if msg.Role != "user" {
+				t.Errorf("Expected user response, got %q", msg.Role)
+			}
+
+			value, _ := state.Get("tool_executions")
+			executions, _ := value.([]rubrics.ToolExecution)
+			if len(executions) != 1 {
+				t.Fatalf("Expected 1 recorded execution, got %d", len(executions))
+			}
+			if executions[0].Success {
+				t.Errorf("Expected malformed call to be recorded as failed")
+			}
+			if executions[0].Args == nil {
+				t.Errorf("Expected empty args map, got nil")
+			}
+		})
+	}
+}
+
+func TestSmolaToolEnv_ExcludesFewShotFromToolSteps(t *testing.T) {
+	config := types.Config{
+		MessageType: "chat",
+		FewShot: []types.Message{
+			{Role: "user", Content: "What is 3 * 3?"},
+			{Role: "assistant", Content: "<think>\nuse the calculator\n</think>\n<tool>{\"name\": \"calculate\", \"args\": {\"expression\": \"3 * 3\"}}</tool>"},
+			{Role: "user", Content: "<result>\n9\n</result>"},
+			{Role: "assistant", Content: "<think>\nthe tool said 9\n</think>\n<answer>\n9\n</answer>"},
+		},
+	}
+
+	env, err := NewSmolaToolEnv(config, []tools.Tool{tools.NewCalculator()}, 3)
+	if err != nil {
+		t.Fatalf("NewSmolaToolEnv failed: %v", err)
+	}
+
+	messages := append(env.FormatPrompt("What is 2 + 2?"), types.Message{
+		Role:    "assistant",
+		Content: "<think>\nuse the calculator\n</think>\n<tool>{\"name\": \"calculate\", \"args\": {\"expression\": \"2 + 2\"}}</tool>",
+	})
+	state := types.NewState(nil)
+
+	// The few-shot answer must not end the rollout
+	if env.IsCompleted(context.Background(), messages, state) {
+		t.Fatal("Expected few-shot answer to be ignored")
+	}
+	if steps, _ := state.Get("tool_steps"); steps != 1 {
+		t.Errorf("Expected 1 tool step, got %v", steps)
+	}
+}
+
+func TestSmolaToolEnv_ExcludesItemFewShot(t *testing.T) {
+	env, err := NewSmolaToolEnv(types.Config{MessageType: "chat"}, []tools.Tool{tools.NewCalculator()}, 3)
+	if err != nil {
+		t.Fatalf("NewSmolaToolEnv failed: %v", err)
+	}
+
+	prompt, err := env.FormatPromptFromItem(map[string]interface{}{
+		"question": "What is 2 + 2?",
+		"few_shot": []types.Message{
+			{Role: "user", Content: "What is 3 * 3?"},
+			{Role: "assistant", Content: "<think>\nthat is 9\n</think>\n<answer>\n9\n</answer>"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("FormatPromptFromItem failed: %v", err)
+	}
+	messages := append(prompt, types.Message{
+		Role:    "assistant",
+		Content: "<think>\nuse the calculator\n</think>\n<tool>{\"name\": \"calculate\", \"args\": {\"expression\": \"2 + 2\"}}</tool>",
+	})
+	state := types.NewState(map[string]interface{}{"prompt_length": len(prompt)})
+
+	// The item's few-shot answer must not end the rollout
+	if env.IsCompleted(context.Background(), messages, state) {
+		t.Fatal("Expected the item's few-shot answer to be ignored")
+	}
+	if steps, _ := state.Get("tool_steps"); steps != 1 {
+		t.Errorf("Expected 1 tool step, got %v", steps)
+	}
+}
+
+func TestSmolaToolEnv_EnvResponseMultipleToolCalls(t *testing.T) {
+	env, err := NewSmolaToolEnv(types.Config{MessageType: "chat"}, []tools.Tool{tools.NewCalculator()}, 3)
+	if err != nil {
+		t.Fatalf("NewSmolaToolEnv failed: %v", err)
+	}
+
+	messages := []types.Message{
+		{Role: "user", Content: "What are 2 + 2 and 3 * 3?"},
+		{Role: "assistant", Content: "<think>\ntwo tools\n</think>\n" +
+			`<tool>{"name": "calculate", "args": {"expression": "2 + 2"}}</tool>` + "\n" +
+			`<tool>{"name": "calculate", "args": {"expression": "3 * 3"}}</tool>`},
+	}
+
+	msg, state, err := env.EnvResponse(context.Background(), messages, types.NewState(nil))
+	if err != nil {
+		t.Fatalf("EnvResponse failed: %v", err)
+	}
+
+	value, _ := state.Get("tool_executions")
+	executions, _ := value.([]rubrics.ToolExecution)
+	if len(executions) != 2 {
+		t.Fatalf("Expected 2 recorded executions, got %d", len(executions))
+	}
+	if executions[0].Args["expression"] != "2 + 2" || executions[1].Args["expression"] != "3 * 3" {
+		t.Errorf("Expected executions in message order, got %+v", executions)
+	}
+
+	if strings.Count(msg.Content, "<result>") != 1 {
+		t.Errorf("Expected a single result block, got %q", msg.Content)
+	}
+	first, second := strings.Index(msg.Content, "4"), strings.Index(msg.Content, "9")
+	if first == -1 || second == -1 || first > second {
+		t.Errorf("Expected results 4 then 9, got %q", msg.Content)
+	}
+}
+
+func TestSmolaToolEnv_ScoresToolUsageByExecution(t *testing.T) {
+	env, err := NewSmolaToolEnv(types.Config{MessageType: "chat"}, []tools.Tool{tools.NewCalculator()}, 3)
+	if err != nil {
+		t.Fatalf("NewSmolaToolEnv failed: %v", err)
+	}
+
+	rollout := func(expression string) *types.Rollout {
+		t.Helper()
+		client := &sequenceClient{Responses: []string{
+			"<think>\nuse the calculator\n</think>\n<tool>{\"name\": \"calculate\", \"args\": {\"expression\": \"" + expression + "\"}}</tool>",
+			"<think>\nthe answer is 4\n</think>\n<answer>\n4\n</answer>",
+		}}
+		result, err := env.Rollout(context.Background(), client, "test-model", env.FormatPrompt("What is 2 + 2?"), "4", types.SamplingArgs{})
+		if err != nil {
+			t.Fatalf("Rollout failed: %v", err)
+		}
+		return result
+	}
+
+	// Both calls are well-formed JSON, but only the first evaluates
+	succeeded := rollout("2 + 2")
+	failed := rollout("2 +* 2")
+
+	if succeeded.Metrics["calculate_usage"] != 1.0 || failed.Metrics["calculate_usage"] != 0.0 {
+		t.Errorf("Expected calculate_usage 1 and 0, got %v and %v", succeeded.Metrics["calculate_usage"], failed.Metrics["calculate_usage"])
+	}
+	if failed.Score >= succeeded.Score {
+		t.Errorf("Expected the failed execution to score lower: failed=%v, succeeded=%v", failed.Score, succeeded.Score)
+	}
+}
+
+func TestSmolaToolEnv_ReportsScoringErrors(t *testing.T) {
+	env, err := NewSmolaToolEnv(types.Config{MessageType: "chat"}, []tools.Tool{tools.NewCalculator()}, 3)
+	if err != nil {
+		t.Fatalf("NewSmolaToolEnv failed: %v", err)
+	}
+	rubric := &rubrics.SmolaToolRubric{MultiMetricRubric: rubrics.NewMultiMetricRubric()}
+	rubric.AddMetric("judge", func(ctx context.Context, parsed, groundTruth string) (float64, error) {
+		return 0, errors.New("judge unavailable")
+	}, 1.0)
+	env.SetRubric(rubric)
+
+	client := &MockClient{Response: "<think>\neasy\n</think>\n<answer>\n4\n</answer>"}
+	_, err = env.Rollout(context.Background(), client, "test-model", env.FormatPrompt("What is 2 + 2?"), "4", types.SamplingArgs{})
+	if err == nil || !strings.Contains(err.Error(), "judge unavailable") {
+		t.Errorf("Expected the scoring error, got %v", err)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/rizome-dev/go-verifiers/pkg/parsers"
+	"github.com/rizome-dev/go-verifiers/pkg/prompts"
 	"github.com/rizome-dev/go-verifiers/pkg/rubrics"
 	"github.com/rizome-dev/go-verifiers/pkg/tools"
 	"github.com/rizome-dev/go-verifiers/pkg/types"
@@ -18,16 +19,57 @@ type ToolEnv struct {
 	ToolSchemas []tools.ToolSchema
 	Parser      *parsers.XMLParser
 	EnvParser   *parsers.XMLParser
+
+	// MaxToolCalls caps tool invocations per rollout, independently of turns; 0 means no cap
+	MaxToolCalls int
+
+	// MaxToolResultChars truncates tool results to this many characters; 0 means no limit
+	MaxToolResultChars int
+
+	// StopSequences are merged into the sampling arguments of every rollout, so
+	// generation halts once the answer is complete
+	StopSequences []string
+}
+
+// DefaultMaxToolResultChars is the tool result length limit of the tool environments
+const DefaultMaxToolResultChars = 1024
+
+// ToolEnvOption configures optional ToolEnv behavior
+type ToolEnvOption func(*ToolEnv)
+
+// WithMaxToolCalls limits the number of tool invocations per rollout
+func WithMaxToolCalls(n int) ToolEnvOption {
+	return func(e *ToolEnv) {
+		e.MaxToolCalls = n
+	}
+}
+
+// WithMaxToolResultChars sets the tool result length limit; 0 means no limit
+func WithMaxToolResultChars(n int) ToolEnvOption {
+	return func(e *ToolEnv) {
+		e.MaxToolResultChars = n
+	}
+}
+
+// WithStopSequences replaces the default "</answer>" stop sequence; passing none
+// disables it
+func WithStopSequences(stops ...string) ToolEnvOption {
+	return func(e *ToolEnv) {
+		e.StopSequences = stops
+	}
 }
 
 // NewToolEnv creates a new tool environment
-func NewToolEnv(config types.Config, toolList []tools.Tool, maxTurns int) (*ToolEnv, error) {
+func NewToolEnv(config types.Config, toolList []tools.Tool, maxTurns int, opts ...ToolEnvOption) (*ToolEnv, error) {
 	// Create parsers
 	parser, err := parsers.NewXMLParser([]interface{}{"think", []string{"tool", "answer"}}, "answer")
 	if err != nil {
 		return nil, err
 	}
 	
+	// Stop sequences are cut from the response, so accept an unclosed final answer
+	parser.SetAllowUnclosed(true)
+	
 	envParser, err := parsers.NewXMLParser([]interface{}{"result"}, "result")
 	if err != nil {
 		return nil, err
@@ -47,8 +89,10 @@ func NewToolEnv(config types.Config, toolList []tools.Tool, maxTurns int) (*Tool
 		config.SystemPrompt = DefaultToolSystemPrompt
 	}
 	
-	toolDescriptions := tools.FormatToolDescriptions(toolList)
-	config.SystemPrompt = strings.ReplaceAll(config.SystemPrompt, "{tool_descriptions}", toolDescriptions)
+	config.SystemPrompt, err = renderToolSystemPrompt(config.SystemPrompt, toolList, parser.GetFormatStr(), "{tool_descriptions}")
+	if err != nil {
+		return nil, err
+	}
 	
 	env := &ToolEnv{
 		MultiTurnEnv: NewMultiTurnEnv(config, maxTurns),
@@ -56,6 +100,13 @@ func NewToolEnv(config types.Config, toolList []tools.Tool, maxTurns int) (*Tool
 		ToolSchemas:  schemas,
 		Parser:       parser,
 		EnvParser:    envParser,
+
+		MaxToolResultChars: DefaultMaxToolResultChars,
+		StopSequences:      []string{"</answer>"},
+	}
+	
+	for _, opt := range opts {
+		opt(env)
 	}
 	
 	// Set parser and rubric
@@ -72,12 +123,18 @@ func NewToolEnv(config types.Config, toolList []tools.Tool, maxTurns int) (*Tool
 }
 
 // IsCompleted checks if the task is completed
-func (e *ToolEnv) IsCompleted(ctx context.Context, messages []types.Message, state map[string]interface{}) bool {
+func (e *ToolEnv) IsCompleted(ctx context.Context, messages []types.Message, state *types.State) bool {
 	// Check if we have an answer
 	if len(messages) == 0 {
 		return false
 	}
 	
+	// Once the tool budget is exhausted, the next assistant turn is final
+	value, _ := state.Get("tool_budget_exhausted")
+	if exhausted, _ := value.(bool); exhausted && messages[len(messages)-1].Role == "assistant" {
+		return true
+	}
+	
 	// Check last assistant message for answer
 	for i := len(messages) - 1; i >= 0; i-- {
 		if messages[i].Role == "assistant" {
@@ -92,7 +149,7 @@ func (e *ToolEnv) IsCompleted(ctx context.Context, messages []types.Message, sta
 }
 
 // EnvResponse generates environment response to tool calls
-func (e *ToolEnv) EnvResponse(ctx context.Context, messages []types.Message, state map[string]interface{}) (types.Message, map[string]interface{}, error) {
+func (e *ToolEnv) EnvResponse(ctx context.Context, messages []types.Message, state *types.State) (types.Message, *types.State, error) {
 	if len(messages) == 0 {
 		return types.Message{}, state, fmt.Errorf("no messages to process")
 	}
@@ -121,8 +178,18 @@ func (e *ToolEnv) EnvResponse(ctx context.Context, messages []types.Message, sta
 		}, state, nil
 	}
 	
-	// Execute tool call
-	result := e.callTool(ctx, toolJSON, 1024)
+	// Stop executing tools once the budget is spent and ask for a final answer
+	value, _ := state.Get("tool_calls")
+	if calls, _ := value.(int); e.MaxToolCalls > 0 && calls >= e.MaxToolCalls {
+		state.Set("tool_budget_exhausted", true)
+		return types.Message{
+			Role:    "user",
+			Content: e.formatError(fmt.Sprintf("Tool call budget of %d exhausted. Do not call any more tools; provide your final answer in <answer> tags now.", e.MaxToolCalls)),
+		}, state, nil
+	}
+	
+	// Execute tool call and record it in the trace
+	result := e.callTool(ctx, toolJSON, e.MaxToolResultChars, state)
 	
 	// Format result as XML
 	response := fmt.Sprintf("<result>\n%s\n</result>", result)
@@ -133,16 +200,32 @@ func (e *ToolEnv) EnvResponse(ctx context.Context, messages []types.Message, sta
 	}, state, nil
 }
 
-// callTool executes a tool based on JSON command
-func (e *ToolEnv) callTool(ctx context.Context, toolJSON string, maxChars int) string {
+// callTool executes a tool based on JSON command and records the execution in state
+func (e *ToolEnv) callTool(ctx context.Context, toolJSON string, maxChars int, state *types.State) string {
+	state.Update("tool_calls", func(current interface{}) interface{} {
+		calls, _ := current.(int)
+		return calls + 1
+	})
+	
 	// Parse tool call
 	toolCall, err := tools.ParseToolCall(toolJSON)
 	if err != nil {
-		return fmt.Sprintf("Error: %v. Please format your tool call as '{\"name\": \"tool_name\", \"args\": {\"arg1\": \"value1\"}}'", err)
+		result := fmt.Sprintf("Error: %v. Please format your tool call as '{\"name\": \"tool_name\", \"args\": {\"arg1\": \"value1\"}}'", err)
+		recordToolExecution(state, rubrics.NewToolExecution("unknown", nil, result, false))
+		return result
 	}
 	
 	// Execute tool
-	return tools.ExecuteTool(ctx, e.Tools, toolCall, maxChars)
+	result := tools.ExecuteTool(ctx, e.Tools, toolCall, maxChars)
+	success := !strings.HasPrefix(result, "Error:")
+	recordToolExecution(state, rubrics.NewToolExecution(toolCall.Name, toolCall.Args, result, success))
+	e.Logger().DebugContext(ctx, "tool executed", "tool", toolCall.Name, "success", success)
+	return result
+}
+
+// recordToolExecution appends an execution to state["tool_executions"]
+func recordToolExecution(state *types.State, exec rubrics.ToolExecution) {
+	state.Append("tool_executions", exec)
 }
 
 // formatError formats an error message as XML
@@ -152,14 +235,102 @@ func (e *ToolEnv) formatError(msg string) string {
 
 // Rollout performs the tool environment rollout
 func (e *ToolEnv) Rollout(ctx context.Context, client types.Client, model string, prompt interface{}, answer string, samplingArgs types.SamplingArgs) (*types.Rollout, error) {
-	return BaseMultiTurnRollout(ctx, e, client, model, prompt, answer, samplingArgs, e.MaxTurns)
+	samplingArgs.Stop = mergeStops(samplingArgs.Stop, e.StopSequences)
+
+	rollout, err := BaseMultiTurnRollout(ctx, e, client, model, prompt, answer, samplingArgs, e.MaxTurns)
+	if err != nil {
+		return nil, err
+	}
+
+	parser, rubric := e.parserAndRubric()
+	if rubric == nil || rollout.Error != nil {
+		e.logRollout(ctx, rollout)
+		return rollout, nil
+	}
+
+	// Extract the final answer from the last assistant message
+	parsed := rollout.Response
+	if parser != nil {
+		answerText, err := parser.Parse(ctx, rollout.Response)
+		if err == nil && answerText != "" {
+			parsed = answerText
+		}
+	}
+
+	// Format and tool usage metrics inspect every assistant turn, not just the answer
+	ctx = rubrics.WithRawResponse(ctx, assistantTranscript(rollout.Messages))
+	trace, _ := rollout.State["tool_executions"].([]rubrics.ToolExecution)
+
+	// Score with the execution trace so efficiency metrics can see every call
+	score, metrics, err := computeReward(rubrics.WithToolTrace(ctx, trace), rubric, parsed, answer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute reward: %w", err)
+	}
+	rollout.Score = score
+	rollout.Metrics = metrics
+
+	e.logRollout(ctx, rollout)
+	return rollout, nil
+}
+
+// mergeStops returns the user's stop sequences followed by any extra ones they lack,
+// without modifying either slice
+func mergeStops(stops, extra []string) []string {
+	merged := append([]string{}, stops...)
+	for _, stop := range extra {
+		found := false
+		for _, existing := range merged {
+			if existing == stop {
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged = append(merged, stop)
+		}
+	}
+	if len(merged) == 0 {
+		return nil
+	}
+	return merged
+}
+
+// assistantTranscript joins all assistant turns, separated by "\n---\n"
+func assistantTranscript(messages []types.Message) string {
+	turns := make([]string, 0, len(messages))
+	for _, msg := range messages {
+		if msg.Role == "assistant" {
+			turns = append(turns, msg.Content)
+		}
+	}
+	return strings.Join(turns, "\n---\n")
+}
+
+// renderToolSystemPrompt fills {{.tool_descriptions}} and {{.format}} in a tool
+// environment's system prompt. The legacy tool description placeholders the
+// environment filled in earlier releases are rewritten to {{.tool_descriptions}}
+// first, so prompts written for them keep listing the tools.
+func renderToolSystemPrompt(systemPrompt string, toolList []tools.Tool, format string, legacyPlaceholders ...string) (string, error) {
+	for _, placeholder := range legacyPlaceholders {
+		systemPrompt = strings.ReplaceAll(systemPrompt, placeholder, "{{.tool_descriptions}}")
+	}
+	rendered, err := prompts.Render(systemPrompt, map[string]string{
+		"tool_descriptions": tools.FormatToolDescriptions(toolList),
+		"format":            format,
+	})
+	if err != nil {
+		return "", fmt.Errorf("system prompt: %w", err)
+	}
+	return rendered, nil
 }
 
-// DefaultToolSystemPrompt is the default system prompt for tool environments
+// DefaultToolSystemPrompt is the default system prompt for tool environments.
+// Like custom prompts, it is rendered with prompts.Render, which fills in
+// {{.tool_descriptions}} and {{.format}}.
 const DefaultToolSystemPrompt = `You are a helpful assistant with access to tools. You can use tools by wrapping your tool calls in XML tags.
 
 Available tools:
-{tool_descriptions}
+{{.tool_descriptions}}
 
 To use a tool, format your request as:
 <think>
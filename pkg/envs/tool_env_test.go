@@ -0,0 +1,331 @@
+package envs
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/rizome-dev/go-verifiers/pkg/rubrics"
+	"github.com/rizome-dev/go-verifiers/pkg/tools"
+	"github.com/rizome-dev/go-verifiers/pkg/types"
+)
+
+func TestToolEnv_RolloutScoresAnswer(t *testing.T) {
+	config := types.Config{
+		Model:       "test-model",
+		MessageType: "chat",
+	}
+
+	env, err := NewToolEnv(config, []tools.Tool{tools.NewCalculator()}, 3)
+	if err != nil {
+		t.Fatalf("NewToolEnv failed: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		response string
+		answer   string
+		wantHigh bool
+	}{
+		{name: "correct answer", response: "<think>\n2 + 2 is 4\n</think>\n<answer>\n4\n</answer>", answer: "4", wantHigh: true},
+		{name: "wrong answer", response: "<think>\nguessing\n</think>\n<answer>\n5\n</answer>", answer: "4", wantHigh: false},
+	}
+
+	ctx := context.Background()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := &MockClient{Response: tt.response}
+			prompt := env.FormatPrompt("What is 2 + 2?")
+
+			rollout, err := env.Rollout(ctx, mockClient, config.Model, prompt, tt.answer, config.SamplingArgs)
+			if err != nil {
+				t.Fatalf("Rollout failed: %v", err)
+			}
+
+			if rollout.Score <= 0 {
+				t.Errorf("Expected non-zero score, got %v", rollout.Score)
+			}
+			if correct := rollout.Metrics["correct_answer"]; (correct == 1.0) != tt.wantHigh {
+				t.Errorf("correct_answer = %v, want correct=%v", correct, tt.wantHigh)
+			}
+		})
+	}
+}
+
+func TestToolEnv_MaxToolCalls(t *testing.T) {
+	executions := 0
+	counter := tools.NewBaseTool("count", "Counts invocations", func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		executions++
+		return executions, nil
+	})
+
+	env, err := NewToolEnv(types.Config{MessageType: "chat"}, []tools.Tool{counter}, 20, WithMaxToolCalls(3))
+	if err != nil {
+		t.Fatalf("NewToolEnv failed: %v", err)
+	}
+
+	// The model never answers and keeps calling the tool
+	mockClient := &MockClient{Response: "<think>\nagain\n</think>\n<tool>\n{\"name\": \"count\", \"args\": {}}\n</tool>"}
+	prompt := env.FormatPrompt("Count forever")
+
+	rollout, err := env.Rollout(context.Background(), mockClient, "test-model", prompt, "3", types.SamplingArgs{})
+	if err != nil {
+		t.Fatalf("Rollout failed: %v", err)
+	}
+
+	if executions != 3 {
+		t.Errorf("Expected tool to run exactly 3 times, ran %d times", executions)
+	}
+	if rollout.Terminated != types.TerminatedCompleted {
+		t.Errorf("Expected the turn after the budget message to finalize, got %q", rollout.Terminated)
+	}
+
+	// Three tool turns, one turn answered with the budget message, then the final turn
+	assistantTurns := 0
+	for _, msg := range rollout.Messages {
+		if msg.Role == "assistant" {
+			assistantTurns++
+		}
+	}
+	if assistantTurns != 5 {
+		t.Errorf("Expected 5 assistant turns, got %d", assistantTurns)
+	}
+}
+
+func TestToolEnvs_MaxToolResultChars(t *testing.T) {
+	long := tools.NewBaseTool("long", "Returns a long result", func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		return strings.Repeat("x", 5000), nil
+	})
+	toolList := []tools.Tool{long}
+	config := types.Config{MessageType: "chat"}
+
+	toolEnv, err := NewToolEnv(config, toolList, 3, WithMaxToolResultChars(100))
+	if err != nil {
+		t.Fatalf("NewToolEnv failed: %v", err)
+	}
+	unlimitedToolEnv, err := NewToolEnv(config, toolList, 3, WithMaxToolResultChars(0))
+	if err != nil {
+		t.Fatalf("NewToolEnv failed: %v", err)
+	}
+	smolaEnv, err := NewSmolaToolEnv(config, toolList, 3)
+	if err != nil {
+		t.Fatalf("NewSmolaToolEnv failed: %v", err)
+	}
+	if smolaEnv.MaxToolResultChars != DefaultMaxToolResultChars {
+		t.Errorf("Expected default limit %d, got %d", DefaultMaxToolResultChars, smolaEnv.MaxToolResultChars)
+	}
+	smolaEnv.MaxToolResultChars = 200
+
+	tests := []struct {
+		name     string
+		env      MultiTurnEnvironment
+		wantKept int
+	}{
+		{name: "tool env", env: toolEnv, wantKept: 100},
+		{name: "tool env unlimited", env: unlimitedToolEnv, wantKept: 5000},
+		{name: "smola env", env: smolaEnv, wantKept: 200},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			messages := []types.Message{
+				{Role: "user", Content: "Call the tool"},
+				{Role: "assistant", Content: "<think>\ncall\n</think>\n<tool>{\"name\": \"long\", \"args\": {}}</tool>"},
+			}
+			msg, _, err := tt.env.EnvResponse(context.Background(), messages, types.NewState(nil))
+			if err != nil {
+				t.Fatalf("EnvResponse failed: %v", err)
+			}
+			if kept := strings.Count(msg.Content, "x"); kept != tt.wantKept {
+				t.Errorf("Expected %d result characters, got %d", tt.wantKept, kept)
+			}
+		})
+	}
+}
+
+// stopRecordingClient returns a fixed response and records the stop sequences it was sent
+type stopRecordingClient struct {
+	Response string
+	Stops    [][]string
+}
+
+func (c *stopRecordingClient) CreateChatCompletion(ctx context.Context, model string, messages []types.Message, args types.SamplingArgs) (string, error) {
+	c.Stops = append(c.Stops, args.Stop)
+	return c.Response, nil
+}
+
+func (c *stopRecordingClient) CreateCompletion(ctx context.Context, model string, prompt string, args types.SamplingArgs) (string, error) {
+	return c.CreateChatCompletion(ctx, model, nil, args)
+}
+
+func TestToolEnv_StopSequences(t *testing.T) {
+	config := types.Config{MessageType: "chat"}
+
+	tests := []struct {
+		name      string
+		opts      []ToolEnvOption
+		userStops []string
+		response  string
+		wantStops []string
+	}{
+		{
+			name:      "default stop, answer cut at stop sequence",
+			response:  "<think>\n2 + 2 is 4\n</think>\n<answer>\n4\n",
+			wantStops: []string{"</answer>"},
+		},
+		{
+			name:      "merged with user stops",
+			userStops: []string{"\nUser:", "</answer>"},
+			response:  "<think>\n2 + 2 is 4\n</think>\n<answer>\n4\n</answer>",
+			wantStops: []string{"\nUser:", "</answer>"},
+		},
+		{
+			name:      "disabled",
+			opts:      []ToolEnvOption{WithStopSequences()},
+			response:  "<think>\n2 + 2 is 4\n</think>\n<answer>\n4\n</answer>",
+			wantStops: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env, err := NewToolEnv(config, []tools.Tool{tools.NewCalculator()}, 3, tt.opts...)
+			if err != nil {
+				t.Fatalf("NewToolEnv failed: %v", err)
+			}
+
+			client := &stopRecordingClient{Response: tt.response}
+			args := types.SamplingArgs{Stop: tt.userStops}
+			rollout, err := env.Rollout(context.Background(), client, "test-model", env.FormatPrompt("What is 2 + 2?"), "4", args)
+			if err != nil {
+				t.Fatalf("Rollout failed: %v", err)
+			}
+
+			if len(client.Stops) != 1 || !reflect.DeepEqual(client.Stops[0], tt.wantStops) {
+				t.Errorf("Stop sequences sent = %q, want one call with %q", client.Stops, tt.wantStops)
+			}
+			if !reflect.DeepEqual(args.Stop, tt.userStops) {
+				t.Errorf("Expected caller's stop sequences to be left unchanged, got %q", args.Stop)
+			}
+			if rollout.Terminated != types.TerminatedCompleted {
+				t.Errorf("Expected the answer to complete the rollout, got %q", rollout.Terminated)
+			}
+			if rollout.Metrics["correct_answer"] != 1.0 {
+				t.Errorf("Expected the answer to be parsed and scored correct, got %v", rollout.Metrics)
+			}
+		})
+	}
+}
+
+func TestToolEnvs_RenderSystemPrompt(t *testing.T) {
+	toolList := []tools.Tool{tools.NewCalculator()}
+
+	tests := []struct {
+		name    string
+		prompt  string
+		want    []string
+		wantErr string
+	}{
+		{name: "default", prompt: "", want: []string{"calculate: "}},
+		{name: "template", prompt: "Tools:\n{{.tool_descriptions}}\nFormat:\n{{.format}}", want: []string{"Tools:\ncalculate: ", "Format:\n<think>"}},
+		{name: "legacy placeholder", prompt: "Tools:\n{tool_descriptions}", want: []string{"Tools:\ncalculate: "}},
+		{name: "unknown variable", prompt: "Hello {{.user_name}}", wantErr: "missing prompt variables: user_name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env, err := NewToolEnv(types.Config{SystemPrompt: tt.prompt}, toolList, 3)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Expected error %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewToolEnv failed: %v", err)
+			}
+			prompt := env.systemPrompt
+			for _, want := range tt.want {
+				if !strings.Contains(prompt, want) {
+					t.Errorf("Expected system prompt to contain %q, got %q", want, prompt)
+				}
+			}
+			if strings.Contains(prompt, "{{") || strings.Contains(prompt, "{tool_descriptions}") {
+				t.Errorf("Unrendered placeholder in %q", prompt)
+			}
+		})
+	}
+
+	smolaEnv, err := NewSmolaToolEnv(types.Config{}, toolList, 3)
+	if err != nil {
+		t.Fatalf("NewSmolaToolEnv failed: %v", err)
+	}
+	if prompt := smolaEnv.systemPrompt; !strings.Contains(prompt, "calculate: ") || strings.Contains(prompt, "{{") {
+		t.Errorf("Expected the Smola prompt to list the tools, got %q", prompt)
+	}
+
+	// SmolaToolEnv filled %s in earlier releases
+	for _, legacy := range []string{"Tools:\n%s", "Tools:\n{tool_descriptions}"} {
+		smolaEnv, err := NewSmolaToolEnv(types.Config{SystemPrompt: legacy}, toolList, 3)
+		if err != nil {
+			t.Fatalf("NewSmolaToolEnv failed: %v", err)
+		}
+		if prompt := smolaEnv.systemPrompt; !strings.HasPrefix(prompt, "Tools:\ncalculate: ") {
+			t.Errorf("Expected legacy prompt %q to list the tools, got %q", legacy, prompt)
+		}
+	}
+}
+
+// Run with -race: tool executions recorded from parallel goroutines must not race
+func TestRecordToolExecution_Concurrent(t *testing.T) {
+	state := types.NewState(nil)
+
+	const workers, perWorker = 8, 50
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perWorker; i++ {
+				recordToolExecution(state, rubrics.NewToolExecution("calculate", map[string]interface{}{"expression": "1 + 1"}, "2", true))
+				state.Get("tool_executions")
+			}
+		}()
+	}
+	wg.Wait()
+
+	value, _ := state.Get("tool_executions")
+	if executions, _ := value.([]rubrics.ToolExecution); len(executions) != workers*perWorker {
+		t.Errorf("Expected %d recorded executions, got %d", workers*perWorker, len(executions))
+	}
+}
+
+func TestToolEnv_StopTruncatedAnswerScoresLikeClosed(t *testing.T) {
+	env, err := NewToolEnv(types.Config{MessageType: "chat"}, []tools.Tool{tools.NewCalculator()}, 3)
+	if err != nil {
+		t.Fatalf("NewToolEnv failed: %v", err)
+	}
+
+	rollouts := make(map[string]*types.Rollout)
+	for name, response := range map[string]string{
+		"closed":    "<think>\n2 + 2 is 4\n</think>\n<answer>\n4\n</answer>",
+		"truncated": "<think>\n2 + 2 is 4\n</think>\n<answer>\n4\n",
+	} {
+		client := &stopRecordingClient{Response: response}
+		rollout, err := env.Rollout(context.Background(), client, "test-model", env.FormatPrompt("What is 2 + 2?"), "4", types.SamplingArgs{})
+		if err != nil {
+			t.Fatalf("Rollout %s failed: %v", name, err)
+		}
+		rollouts[name] = rollout
+	}
+
+	closed, truncated := rollouts["closed"], rollouts["truncated"]
+	if truncated.Metrics["format"] != 1.0 {
+		t.Errorf("Expected a stop-truncated answer to be well formatted, got format %v", truncated.Metrics["format"])
+	}
+	if truncated.Score != closed.Score {
+		t.Errorf("Expected the stop-truncated answer to score %v like the closed one, got %v", closed.Score, truncated.Score)
+	}
+}
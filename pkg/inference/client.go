@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/rizome-dev/go-verifiers/pkg/types"
@@ -14,9 +16,11 @@ import (
 
 // HTTPClient implements the types.Client interface using HTTP
 type HTTPClient struct {
-	BaseURL    string
+	BaseURL    string // API root such as "http://localhost:8000/v1"; may carry a path prefix and query
 	APIKey     string
 	HTTPClient *http.Client
+
+	defaultQuery map[string]string
 }
 
 // NewHTTPClient creates a new HTTP-based inference client
@@ -52,6 +56,9 @@ type ChatCompletionRequest struct {
 	N           int                    `json:"n,omitempty"`
 	Stop        []string               `json:"stop,omitempty"`
 	ExtraBody   map[string]interface{} `json:"extra_body,omitempty"`
+	Tools       []types.ToolDefinition `json:"tools,omitempty"`
+	ToolChoice  interface{}            `json:"tool_choice,omitempty"`
+	Seed        *int                   `json:"seed,omitempty"`
 }
 
 // CompletionRequest represents the request structure for completions
@@ -64,6 +71,7 @@ type CompletionRequest struct {
 	N           int                    `json:"n,omitempty"`
 	Stop        []string               `json:"stop,omitempty"`
 	ExtraBody   map[string]interface{} `json:"extra_body,omitempty"`
+	Seed        *int                   `json:"seed,omitempty"`
 }
 
 // ChatCompletionResponse represents the response from chat completion
@@ -72,11 +80,7 @@ type ChatCompletionResponse struct {
 	Object  string `json:"object"`
 	Created int64  `json:"created"`
 	Model   string `json:"model"`
-	Choices []struct {
-		Index        int             `json:"index"`
-		Message      types.Message   `json:"message"`
-		FinishReason string          `json:"finish_reason"`
-	} `json:"choices"`
+	Choices []ChatCompletionChoice `json:"choices"`
 	Usage struct {
 		PromptTokens     int `json:"prompt_tokens"`
 		CompletionTokens int `json:"completion_tokens"`
@@ -84,6 +88,13 @@ type ChatCompletionResponse struct {
 	} `json:"usage"`
 }
 
+// ChatCompletionChoice is one generated message of a chat completion response
+type ChatCompletionChoice struct {
+	Index        int           `json:"index"`
+	Message      types.Message `json:"message"`
+	FinishReason string        `json:"finish_reason"`
+}
+
 // CompletionResponse represents the response from completion
 type CompletionResponse struct {
 	ID      string `json:"id"`
@@ -102,8 +113,83 @@ type CompletionResponse struct {
 	} `json:"usage"`
 }
 
+// reportUsage records the token counts of a response with types.AddUsage, unless
+// the server reported none
+func reportUsage(ctx context.Context, promptTokens, completionTokens, totalTokens int) {
+	if promptTokens == 0 && completionTokens == 0 && totalTokens == 0 {
+		return
+	}
+	types.AddUsage(ctx, types.Usage{
+		PromptTokens:     promptTokens,
+		CompletionTokens: completionTokens,
+		TotalTokens:      totalTokens,
+	})
+}
+
+// SetDefaultQuery sets query parameters added to every request URL, e.g. a
+// deployment name required by a gateway. They override parameters of the same
+// name in BaseURL. nil removes them.
+func (c *HTTPClient) SetDefaultQuery(query map[string]string) {
+	c.defaultQuery = make(map[string]string, len(query))
+	for k, v := range query {
+		c.defaultQuery[k] = v
+	}
+}
+
+// endpoint returns the URL of path below BaseURL. Paths are joined without
+// doubled or missing slashes, and the query of BaseURL and the default query
+// are kept.
+func (c *HTTPClient) endpoint(path string) (string, error) {
+	base, err := url.Parse(c.BaseURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid base URL %q: %w", c.BaseURL, err)
+	}
+
+	u := base.JoinPath(path)
+	if len(c.defaultQuery) > 0 {
+		query := u.Query()
+		for k, v := range c.defaultQuery {
+			query.Set(k, v)
+		}
+		u.RawQuery = query.Encode()
+	}
+	return u.String(), nil
+}
+
 // CreateChatCompletion creates a chat completion
 func (c *HTTPClient) CreateChatCompletion(ctx context.Context, model string, messages []types.Message, args types.SamplingArgs) (string, error) {
+	message, errMarker, err := c.chatCompletion(ctx, model, messages, args)
+	if err != nil {
+		return "", err
+	}
+	if errMarker != "" {
+		return errMarker, nil
+	}
+	return message.Content, nil
+}
+
+// CreateChatCompletionWithTools creates a chat completion offering args.Tools to the
+// model and returns the assistant message with any tool calls it requested.
+// Context length and truncation errors are returned as an assistant message whose
+// content is the "[ERROR] ..." marker, as with CreateChatCompletion.
+func (c *HTTPClient) CreateChatCompletionWithTools(ctx context.Context, model string, messages []types.Message, args types.SamplingArgs) (types.Message, error) {
+	message, errMarker, err := c.chatCompletion(ctx, model, messages, args)
+	if err != nil {
+		return types.Message{}, err
+	}
+	if errMarker != "" {
+		return types.Message{Role: "assistant", Content: errMarker}, nil
+	}
+	if message.Role == "" {
+		message.Role = "assistant"
+	}
+	return message, nil
+}
+
+// chatCompletion sends a chat completion request and returns the first choice's
+// message. A non-empty errMarker is an "[ERROR] ..." string that callers return as
+// the model output instead of the message.
+func (c *HTTPClient) chatCompletion(ctx context.Context, model string, messages []types.Message, args types.SamplingArgs) (types.Message, string, error) {
 	req := ChatCompletionRequest{
 		Model:       model,
 		Messages:    messages,
@@ -113,16 +199,23 @@ func (c *HTTPClient) CreateChatCompletion(ctx context.Context, model string, mes
 		N:           args.N,
 		Stop:        args.Stop,
 		ExtraBody:   args.ExtraBody,
+		Tools:       args.Tools,
+		ToolChoice:  args.ToolChoice,
+		Seed:        args.Seed,
 	}
 
 	body, err := json.Marshal(req)
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal request: %w", err)
+		return types.Message{}, "", fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.BaseURL+"/chat/completions", bytes.NewReader(body))
+	endpoint, err := c.endpoint("chat/completions")
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+		return types.Message{}, "", err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(body))
+	if err != nil {
+		return types.Message{}, "", fmt.Errorf("failed to create request: %w", err)
 	}
 
 	httpReq.Header.Set("Content-Type", "application/json")
@@ -130,7 +223,7 @@ func (c *HTTPClient) CreateChatCompletion(ctx context.Context, model string, mes
 
 	resp, err := c.HTTPClient.Do(httpReq)
 	if err != nil {
-		return "", fmt.Errorf("request failed: %w", err)
+		return types.Message{}, "", fmt.Errorf("request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
@@ -138,26 +231,27 @@ func (c *HTTPClient) CreateChatCompletion(ctx context.Context, model string, mes
 		body, _ := io.ReadAll(resp.Body)
 		// Check for context length error
 		if resp.StatusCode == http.StatusBadRequest && bytes.Contains(body, []byte("context_length_exceeded")) {
-			return "[ERROR] context_length_exceeded", nil
+			return types.Message{}, "[ERROR] context_length_exceeded", nil
 		}
-		return "", fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
+		return types.Message{}, "", fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
 	}
 
 	var chatResp ChatCompletionResponse
 	if err := json.NewDecoder(resp.Body).Decode(&chatResp); err != nil {
-		return "", fmt.Errorf("failed to decode response: %w", err)
+		return types.Message{}, "", fmt.Errorf("failed to decode response: %w", err)
 	}
 
 	if len(chatResp.Choices) == 0 {
-		return "", fmt.Errorf("no choices in response")
+		return types.Message{}, "", fmt.Errorf("no choices in response")
 	}
+	reportUsage(ctx, chatResp.Usage.PromptTokens, chatResp.Usage.CompletionTokens, chatResp.Usage.TotalTokens)
 
 	// Check if generation was truncated
 	if chatResp.Choices[0].FinishReason == "length" {
-		return "[ERROR] max_tokens_reached", nil
+		return types.Message{}, "[ERROR] max_tokens_reached", nil
 	}
 
-	return chatResp.Choices[0].Message.Content, nil
+	return chatResp.Choices[0].Message, "", nil
 }
 
 // CreateCompletion creates a text completion
@@ -171,6 +265,7 @@ func (c *HTTPClient) CreateCompletion(ctx context.Context, model string, prompt
 		N:           args.N,
 		Stop:        args.Stop,
 		ExtraBody:   args.ExtraBody,
+		Seed:        args.Seed,
 	}
 
 	body, err := json.Marshal(req)
@@ -178,7 +273,11 @@ func (c *HTTPClient) CreateCompletion(ctx context.Context, model string, prompt
 		return "", fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.BaseURL+"/completions", bytes.NewReader(body))
+	endpoint, err := c.endpoint("completions")
+	if err != nil {
+		return "", err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(body))
 	if err != nil {
 		return "", fmt.Errorf("failed to create request: %w", err)
 	}
@@ -209,6 +308,7 @@ func (c *HTTPClient) CreateCompletion(ctx context.Context, model string, prompt
 	if len(compResp.Choices) == 0 {
 		return "", fmt.Errorf("no choices in response")
 	}
+	reportUsage(ctx, compResp.Usage.PromptTokens, compResp.Usage.CompletionTokens, compResp.Usage.TotalTokens)
 
 	// Check if generation was truncated
 	if compResp.Choices[0].FinishReason == "length" {
@@ -226,8 +326,13 @@ func (c *HTTPClient) CheckServer(ctx context.Context, totalTimeout time.Duration
 
 	deadline := time.Now().Add(totalTimeout)
 	
+	endpoint, err := c.endpoint("models")
+	if err != nil {
+		return err
+	}
+
 	for {
-		req, err := http.NewRequestWithContext(ctx, "GET", c.BaseURL+"/models", nil)
+		req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
 		if err != nil {
 			return fmt.Errorf("failed to create request: %w", err)
 		}
@@ -251,4 +356,62 @@ func (c *HTTPClient) CheckServer(ctx context.Context, totalTimeout time.Duration
 			continue
 		}
 	}
-}
\ No newline at end of file
+}
+
+// ModelsResponse represents the response from the /models endpoint
+type ModelsResponse struct {
+	Data []struct {
+		ID string `json:"id"`
+	} `json:"data"`
+}
+
+// ListModels returns the ids of the models served at /models
+func (c *HTTPClient) ListModels(ctx context.Context) ([]string, error) {
+	endpoint, err := c.endpoint("models")
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Authorization", "Bearer "+c.APIKey)
+
+	resp, err := c.HTTPClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
+	}
+
+	var modelsResp ModelsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&modelsResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	models := make([]string, 0, len(modelsResp.Data))
+	for _, model := range modelsResp.Data {
+		models = append(models, model.ID)
+	}
+	return models, nil
+}
+
+// CheckModel checks that the server serves model, so a mistyped model name is
+// caught before any rollout. The error lists the available models.
+func (c *HTTPClient) CheckModel(ctx context.Context, model string) error {
+	models, err := c.ListModels(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list models: %w", err)
+	}
+
+	for _, available := range models {
+		if available == model {
+			return nil
+		}
+	}
+	return fmt.Errorf("model %q not available; available models: %s", model, strings.Join(models, ", "))
+}
@@ -0,0 +1,203 @@
+package inference
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rizome-dev/go-verifiers/pkg/types"
+)
+
+func TestHTTPClient_CreateChatCompletionWithTools(t *testing.T) {
+	var received ChatCompletionRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"choices": [{"index": 0, "finish_reason": "tool_calls", "message": {
+			"role": "assistant", "content": null,
+			"tool_calls": [{"id": "call_1", "type": "function", "function": {"name": "calculate", "arguments": "{\"expression\": \"2 + 2\"}"}}]
+		}}]}`))
+	}))
+	defer server.Close()
+
+	client := NewHTTPClient(server.URL, "test-key")
+	args := types.SamplingArgs{
+		Tools: []types.ToolDefinition{{
+			Type:     "function",
+			Function: types.FunctionDefinition{Name: "calculate", Parameters: map[string]interface{}{"type": "object"}},
+		}},
+		ToolChoice: "auto",
+	}
+
+	msg, err := client.CreateChatCompletionWithTools(context.Background(), "test-model", []types.Message{{Role: "user", Content: "What is 2 + 2?"}}, args)
+	if err != nil {
+		t.Fatalf("CreateChatCompletionWithTools failed: %v", err)
+	}
+
+	if len(received.Tools) != 1 || received.Tools[0].Function.Name != "calculate" || received.ToolChoice != "auto" {
+		t.Errorf("Tools not serialized in request: %+v", received)
+	}
+	if msg.Role != "assistant" || len(msg.ToolCalls) != 1 {
+		t.Fatalf("Expected one tool call, got %+v", msg)
+	}
+	call := msg.ToolCalls[0]
+	if call.ID != "call_1" || call.Function.Name != "calculate" || call.Function.Arguments != `{"expression": "2 + 2"}` {
+		t.Errorf("Unexpected tool call: %+v", call)
+	}
+}
+
+func TestHTTPClient_Seed(t *testing.T) {
+	seed := 42
+	tests := []struct {
+		name     string
+		seed     *int
+		wantSeed bool
+	}{
+		{name: "seed set", seed: &seed, wantSeed: true},
+		{name: "seed nil", seed: nil, wantSeed: false},
+	}
+
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, _ := io.ReadAll(r.Body)
+		body = nil
+		if err := json.Unmarshal(raw, &body); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"choices": [{"index": 0, "finish_reason": "stop", "text": "4", "message": {"role": "assistant", "content": "4"}}]}`))
+	}))
+	defer server.Close()
+
+	client := NewHTTPClient(server.URL, "test-key")
+	ctx := context.Background()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := types.SamplingArgs{Seed: tt.seed}
+			requests := map[string]func() error{
+				"chat": func() error {
+					_, err := client.CreateChatCompletion(ctx, "test-model", []types.Message{{Role: "user", Content: "What is 2 + 2?"}}, args)
+					return err
+				},
+				"completion": func() error {
+					_, err := client.CreateCompletion(ctx, "test-model", "2 + 2 =", args)
+					return err
+				},
+			}
+
+			for kind, request := range requests {
+				if err := request(); err != nil {
+					t.Fatalf("%s request failed: %v", kind, err)
+				}
+				got, ok := body["seed"]
+				if ok != tt.wantSeed {
+					t.Fatalf("%s request: seed present = %v, want %v (body %v)", kind, ok, tt.wantSeed, body)
+				}
+				if tt.wantSeed && got != float64(seed) {
+					t.Errorf("%s request: seed = %v, want %d", kind, got, seed)
+				}
+			}
+		})
+	}
+}
+
+func TestHTTPClient_CheckModel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/models" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"object": "list", "data": [{"id": "llama-3-8b", "object": "model"}, {"id": "qwen-2.5-7b", "object": "model"}]}`))
+	}))
+	defer server.Close()
+
+	client := NewHTTPClient(server.URL, "test-key")
+	ctx := context.Background()
+
+	if err := client.CheckModel(ctx, "qwen-2.5-7b"); err != nil {
+		t.Errorf("CheckModel failed for a served model: %v", err)
+	}
+
+	err := client.CheckModel(ctx, "qwen-2.5-7B")
+	if err == nil {
+		t.Fatal("Expected an error for a missing model")
+	}
+	for _, want := range []string{`"qwen-2.5-7B"`, "llama-3-8b", "qwen-2.5-7b"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected error to mention %s, got %q", want, err)
+		}
+	}
+}
+
+func TestHTTPClient_EndpointURL(t *testing.T) {
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.RequestURI())
+		json.NewEncoder(w).Encode(MockChatResponse("ok"))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		baseURL  string
+		query    map[string]string
+		expected string
+	}{
+		{"no trailing slash", server.URL + "/api/llm/v1", nil, "/api/llm/v1/chat/completions"},
+		{"trailing slash", server.URL + "/api/llm/v1/", nil, "/api/llm/v1/chat/completions"},
+		{"default query", server.URL + "/api/llm/v1/", map[string]string{"deployment": "x"}, "/api/llm/v1/chat/completions?deployment=x"},
+		{"query in base URL", server.URL + "/v1?api-version=2024", map[string]string{"deployment": "x"}, "/v1/chat/completions?api-version=2024&deployment=x"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requested = nil
+			client := NewHTTPClient(tt.baseURL, "")
+			client.SetDefaultQuery(tt.query)
+
+			response, err := client.CreateChatCompletion(context.Background(), "test-model", []types.Message{{Role: "user", Content: "hi"}}, types.SamplingArgs{})
+			if err != nil {
+				t.Fatalf("CreateChatCompletion failed: %v", err)
+			}
+			if response != "ok" {
+				t.Errorf("Expected mock response, got %q", response)
+			}
+			if len(requested) != 1 || requested[0] != tt.expected {
+				t.Errorf("Expected request to %q, got %v", tt.expected, requested)
+			}
+		})
+	}
+}
+
+func TestHTTPClient_ReportsUsage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(r.URL.Path, "/chat/completions") {
+			w.Write([]byte(`{"choices": [{"message": {"role": "assistant", "content": "4"}}], "usage": {"prompt_tokens": 10, "completion_tokens": 2, "total_tokens": 12}}`))
+			return
+		}
+		w.Write([]byte(`{"choices": [{"text": "4"}], "usage": {"prompt_tokens": 5, "completion_tokens": 1, "total_tokens": 6}}`))
+	}))
+	defer server.Close()
+
+	client := NewHTTPClient(server.URL, "test-key")
+	ctx, usage := types.WithUsageTracking(context.Background())
+	if _, err := client.CreateChatCompletion(ctx, "test-model", []types.Message{{Role: "user", Content: "2 + 2?"}}, types.SamplingArgs{}); err != nil {
+		t.Fatalf("CreateChatCompletion failed: %v", err)
+	}
+	if _, err := client.CreateCompletion(ctx, "test-model", "2 + 2 =", types.SamplingArgs{}); err != nil {
+		t.Fatalf("CreateCompletion failed: %v", err)
+	}
+
+	want := types.Usage{PromptTokens: 15, CompletionTokens: 3, TotalTokens: 18}
+	if got := usage(); got == nil || *got != want {
+		t.Errorf("usage = %+v, want %+v", got, want)
+	}
+}
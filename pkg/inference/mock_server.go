@@ -0,0 +1,51 @@
+package inference
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/rizome-dev/go-verifiers/pkg/types"
+)
+
+// NewMockServer starts an OpenAI-compatible test server for /chat/completions.
+// Each request is decoded and passed to handler, whose response is returned to
+// the client, so tests can assert on the messages and sampling args received
+// and shape the reply. Point an HTTPClient at the server's URL and Close the
+// server when done. Other paths return 404, and undecodable requests 400.
+func NewMockServer(handler func(ChatCompletionRequest) ChatCompletionResponse) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/chat/completions" {
+			http.NotFound(w, r)
+			return
+		}
+
+		var req ChatCompletionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		resp := handler(req)
+		if resp.Object == "" {
+			resp.Object = "chat.completion"
+		}
+		if resp.Model == "" {
+			resp.Model = req.Model
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+}
+
+// MockChatResponse returns a single-choice chat completion response with the
+// given assistant content and a "stop" finish reason
+func MockChatResponse(content string) ChatCompletionResponse {
+	return ChatCompletionResponse{
+		Choices: []ChatCompletionChoice{{
+			Message:      types.Message{Role: "assistant", Content: content},
+			FinishReason: "stop",
+		}},
+	}
+}
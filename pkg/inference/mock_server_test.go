@@ -0,0 +1,87 @@
+package inference
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/rizome-dev/go-verifiers/pkg/types"
+)
+
+func TestMockServer_SamplingArgs(t *testing.T) {
+	var received ChatCompletionRequest
+	server := NewMockServer(func(req ChatCompletionRequest) ChatCompletionResponse {
+		received = req
+		return MockChatResponse("4")
+	})
+	defer server.Close()
+
+	client := NewHTTPClient(server.URL, "test-key")
+	messages := []types.Message{
+		{Role: "system", Content: "Answer with a number."},
+		{Role: "user", Content: "What is 2 + 2?"},
+	}
+	args := types.SamplingArgs{Temperature: 0.7, TopP: 0.9, MaxTokens: 64, Stop: []string{"</answer>", "\n\n"}}
+
+	response, err := client.CreateChatCompletion(context.Background(), "test-model", messages, args)
+	if err != nil {
+		t.Fatalf("CreateChatCompletion failed: %v", err)
+	}
+	if response != "4" {
+		t.Errorf("Expected response 4, got %q", response)
+	}
+
+	if received.Model != "test-model" || !reflect.DeepEqual(received.Messages, messages) {
+		t.Errorf("Unexpected model or messages: %+v", received)
+	}
+	if received.Temperature != 0.7 || received.TopP != 0.9 || received.MaxTokens != 64 {
+		t.Errorf("Sampling args not serialized: temperature=%v top_p=%v max_tokens=%v", received.Temperature, received.TopP, received.MaxTokens)
+	}
+	if !reflect.DeepEqual(received.Stop, args.Stop) {
+		t.Errorf("Stop = %q, want %q", received.Stop, args.Stop)
+	}
+}
+
+func TestMockServer_ShapesResponses(t *testing.T) {
+	tests := []struct {
+		name     string
+		response ChatCompletionResponse
+		want     string
+		wantErr  bool
+	}{
+		{name: "content", response: MockChatResponse("Paris"), want: "Paris"},
+		{
+			name: "truncated",
+			response: ChatCompletionResponse{Choices: []ChatCompletionChoice{{
+				Message:      types.Message{Role: "assistant", Content: "The capital is"},
+				FinishReason: "length",
+			}}},
+			want: "[ERROR] max_tokens_reached",
+		},
+		{name: "no choices", response: ChatCompletionResponse{}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := NewMockServer(func(req ChatCompletionRequest) ChatCompletionResponse {
+				return tt.response
+			})
+			defer server.Close()
+
+			client := NewHTTPClient(server.URL, "test-key")
+			got, err := client.CreateChatCompletion(context.Background(), "test-model", []types.Message{{Role: "user", Content: "Capital of France?"}}, types.SamplingArgs{})
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected an error, got %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CreateChatCompletion failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
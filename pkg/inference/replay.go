@@ -0,0 +1,114 @@
+package inference
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/rizome-dev/go-verifiers/pkg/types"
+)
+
+// ReplayClient implements types.Client by returning the assistant responses of
+// recorded rollouts instead of calling a model, so tests and offline scoring run
+// deterministically.
+//
+// Rollouts are replayed in order. Each chat request returns the recorded message
+// that follows the request's conversation, i.e. the message at index len(messages)
+// of the current rollout's transcript, so prompt and few-shot messages are never
+// returned as responses. A completion prompt does not reveal how many messages it
+// renders, so completion requests return the first assistant message after the
+// last one served, skipping the prompt recorded by multi-turn rollouts. A request
+// that does not advance past the last response served starts the next rollout.
+type ReplayClient struct {
+	mu       sync.Mutex
+	rollouts []types.Rollout
+	current  int
+	served   int // Transcript index of the last response served from the current rollout
+}
+
+// NewReplayClient creates a client that replays rollouts in order
+func NewReplayClient(rollouts []types.Rollout) *ReplayClient {
+	return &ReplayClient{
+		rollouts: rollouts,
+		served:   -1,
+	}
+}
+
+// CreateChatCompletion returns the next recorded assistant response
+func (c *ReplayClient) CreateChatCompletion(ctx context.Context, model string, messages []types.Message, args types.SamplingArgs) (string, error) {
+	return c.next(len(messages))
+}
+
+// CreateCompletion returns the next recorded assistant response
+func (c *ReplayClient) CreateCompletion(ctx context.Context, model string, prompt string, args types.SamplingArgs) (string, error) {
+	return c.next(nextAssistant)
+}
+
+// nextAssistant asks next for the first assistant message after the last one served
+const nextAssistant = -1
+
+// Remaining returns the number of rollouts not yet fully replayed
+func (c *ReplayClient) Remaining() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.rollouts) - c.current
+}
+
+// next returns the recorded assistant message at transcript index position, or
+// after the last one served for nextAssistant, moving on to the next rollout when
+// the current one has no such response
+func (c *ReplayClient) next(position int) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for c.current < len(c.rollouts) {
+		rollout := c.rollouts[c.current]
+		if response, index, ok := recordedResponse(rollout, position, c.served); ok {
+			c.served = index
+			return response, nil
+		}
+		c.current++
+		c.served = -1
+	}
+
+	return "", fmt.Errorf("replay exhausted: no recorded response left after %d rollouts", len(c.rollouts))
+}
+
+// recordedResponse returns the assistant message at transcript index position, or
+// the first one after the prompt for nextAssistant, with its index, when it lies
+// past the last response served. Rollouts without a transcript replay their final
+// response once.
+func recordedResponse(rollout types.Rollout, position, served int) (string, int, bool) {
+	if len(rollout.Messages) == 0 {
+		return rollout.Response, 0, served < 0
+	}
+	if position == nextAssistant {
+		start := served + 1
+		if length := promptLength(rollout); start < length {
+			start = length
+		}
+		for i := start; i < len(rollout.Messages); i++ {
+			if rollout.Messages[i].Role == "assistant" {
+				return rollout.Messages[i].Content, i, true
+			}
+		}
+		return "", 0, false
+	}
+	if position <= served || position >= len(rollout.Messages) || rollout.Messages[position].Role != "assistant" {
+		return "", 0, false
+	}
+	return rollout.Messages[position].Content, position, true
+}
+
+// promptLength returns the number of prompt messages a multi-turn rollout recorded
+// in its state, or 0. Rollouts read from JSONL hold it as a float64.
+func promptLength(rollout types.Rollout) int {
+	switch length := rollout.State["prompt_length"].(type) {
+	case int:
+		return length
+	case float64:
+		return int(length)
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,145 @@
+package inference
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rizome-dev/go-verifiers/pkg/envs"
+	"github.com/rizome-dev/go-verifiers/pkg/types"
+)
+
+// scriptedClient returns its responses in order
+type scriptedClient struct {
+	responses []string
+	calls     int
+}
+
+func (c *scriptedClient) CreateChatCompletion(ctx context.Context, model string, messages []types.Message, args types.SamplingArgs) (string, error) {
+	response := c.responses[c.calls%len(c.responses)]
+	c.calls++
+	return response, nil
+}
+
+func (c *scriptedClient) CreateCompletion(ctx context.Context, model string, prompt string, args types.SamplingArgs) (string, error) {
+	return c.CreateChatCompletion(ctx, model, nil, args)
+}
+
+func TestReplayClient_ReproducesRollout(t *testing.T) {
+	config := types.Config{
+		Model:        "test-model",
+		SystemPrompt: "You are a test assistant.",
+		FewShot: []types.Message{
+			{Role: "user", Content: "Say hi"},
+			{Role: "assistant", Content: "hi DONE"},
+		},
+		MessageType: "chat",
+	}
+	env := envs.NewDialogMultiTurnEnv(config, 5, "DONE")
+	prompt := env.FormatPrompt("Count to three")
+	ctx := context.Background()
+
+	// Record two rollouts and write them as JSONL
+	recorder := &scriptedClient{responses: []string{"one", "two", "three DONE"}}
+	var buf bytes.Buffer
+	recorded := make([]*types.Rollout, 0, 2)
+	for i := 0; i < 2; i++ {
+		rollout, err := env.Rollout(ctx, recorder, config.Model, prompt, "", config.SamplingArgs)
+		if err != nil {
+			t.Fatalf("Rollout failed: %v", err)
+		}
+		line, err := rollout.MarshalJSONL()
+		if err != nil {
+			t.Fatalf("MarshalJSONL failed: %v", err)
+		}
+		buf.Write(line)
+		recorded = append(recorded, rollout)
+	}
+
+	rollouts, err := types.ParseRolloutJSONL(&buf)
+	if err != nil {
+		t.Fatalf("ParseRolloutJSONL failed: %v", err)
+	}
+	if len(rollouts) != 2 {
+		t.Fatalf("Expected 2 rollouts, got %d", len(rollouts))
+	}
+
+	replay := NewReplayClient(rollouts)
+	for i, want := range recorded {
+		got, err := env.Rollout(ctx, replay, config.Model, prompt, "", config.SamplingArgs)
+		if err != nil {
+			t.Fatalf("Replayed rollout %d failed: %v", i, err)
+		}
+		if !reflect.DeepEqual(got.Messages, want.Messages) {
+			t.Errorf("Replayed rollout %d transcript = %+v, want %+v", i, got.Messages, want.Messages)
+		}
+	}
+
+	if _, err := replay.CreateChatCompletion(ctx, config.Model, prompt, config.SamplingArgs); err == nil || !strings.Contains(err.Error(), "replay exhausted") {
+		t.Errorf("Expected replay exhausted error, got %v", err)
+	}
+}
+
+func TestReplayClient_CompletionMode(t *testing.T) {
+	config := types.Config{
+		Model:        "test-model",
+		SystemPrompt: "Be brief.",
+		FewShot: []types.Message{
+			{Role: "user", Content: "Say hi"},
+			{Role: "assistant", Content: "hi DONE"},
+		},
+		MessageType: "completion",
+	}
+	dialog := envs.NewDialogMultiTurnEnv(config, 5, "DONE")
+
+	tests := []struct {
+		name   string
+		env    envs.Environment
+		prompt interface{}
+	}{
+		{name: "single-turn", env: envs.NewSingleTurnCompletionEnv(config), prompt: "Count to three"},
+		{name: "multi-turn", env: dialog, prompt: dialog.FormatPrompt("Count to three")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			recorder := &scriptedClient{responses: []string{"one", "two", "three DONE"}}
+			var buf bytes.Buffer
+			recorded := make([]*types.Rollout, 0, 2)
+			for i := 0; i < 2; i++ {
+				rollout, err := tt.env.Rollout(ctx, recorder, config.Model, tt.prompt, "", config.SamplingArgs)
+				if err != nil {
+					t.Fatalf("Rollout failed: %v", err)
+				}
+				line, err := rollout.MarshalJSONL()
+				if err != nil {
+					t.Fatalf("MarshalJSONL failed: %v", err)
+				}
+				buf.Write(line)
+				recorded = append(recorded, rollout)
+			}
+
+			rollouts, err := types.ParseRolloutJSONL(&buf)
+			if err != nil {
+				t.Fatalf("ParseRolloutJSONL failed: %v", err)
+			}
+
+			replay := NewReplayClient(rollouts)
+			for i, want := range recorded {
+				got, err := tt.env.Rollout(ctx, replay, config.Model, tt.prompt, "", config.SamplingArgs)
+				if err != nil {
+					t.Fatalf("Replayed rollout %d failed: %v", i, err)
+				}
+				if !reflect.DeepEqual(got.Messages, want.Messages) {
+					t.Errorf("Replayed rollout %d transcript = %+v, want %+v", i, got.Messages, want.Messages)
+				}
+			}
+			if replay.Remaining() != 1 {
+				t.Errorf("Expected only the last rollout left, got %d", replay.Remaining())
+			}
+		})
+	}
+}
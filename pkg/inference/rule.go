@@ -0,0 +1,56 @@
+package inference
+
+import (
+	"context"
+
+	"github.com/rizome-dev/go-verifiers/pkg/types"
+)
+
+// Rule computes a response from the conversation so far
+type Rule func(messages []types.Message) string
+
+// RuleClient implements types.Client by computing every response with a Rule
+// instead of calling a model, so environments can be tested end to end without
+// a model server.
+type RuleClient struct {
+	rule Rule
+}
+
+// NewRuleClient creates a client that answers with rule
+func NewRuleClient(rule Rule) *RuleClient {
+	return &RuleClient{rule: rule}
+}
+
+// CreateChatCompletion returns the rule's response to messages
+func (c *RuleClient) CreateChatCompletion(ctx context.Context, model string, messages []types.Message, args types.SamplingArgs) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	return c.rule(messages), nil
+}
+
+// CreateCompletion returns the rule's response to the prompt, passed as a single
+// user message
+func (c *RuleClient) CreateCompletion(ctx context.Context, model string, prompt string, args types.SamplingArgs) (string, error) {
+	return c.CreateChatCompletion(ctx, model, []types.Message{{Role: "user", Content: prompt}}, args)
+}
+
+// AlwaysAnswer returns a rule that responds with response every time
+func AlwaysAnswer(response string) Rule {
+	return func(messages []types.Message) string {
+		return response
+	}
+}
+
+// EchoLastUserMessage returns a rule that repeats the content of the last user
+// message, or an empty response if there is none
+func EchoLastUserMessage() Rule {
+	return func(messages []types.Message) string {
+		for i := len(messages) - 1; i >= 0; i-- {
+			if messages[i].Role == "user" {
+				return messages[i].Content
+			}
+		}
+		return ""
+	}
+}
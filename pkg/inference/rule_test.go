@@ -0,0 +1,70 @@
+package inference
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/rizome-dev/go-verifiers/pkg/envs"
+	"github.com/rizome-dev/go-verifiers/pkg/rubrics"
+	"github.com/rizome-dev/go-verifiers/pkg/tools"
+	"github.com/rizome-dev/go-verifiers/pkg/types"
+)
+
+func TestRuleClient_Helpers(t *testing.T) {
+	ctx := context.Background()
+	messages := []types.Message{
+		{Role: "user", Content: "first"},
+		{Role: "assistant", Content: "reply"},
+		{Role: "user", Content: "second"},
+	}
+
+	if got, _ := NewRuleClient(EchoLastUserMessage()).CreateChatCompletion(ctx, "m", messages, types.SamplingArgs{}); got != "second" {
+		t.Errorf("Expected echo of the last user message, got %q", got)
+	}
+	if got, _ := NewRuleClient(EchoLastUserMessage()).CreateCompletion(ctx, "m", "prompt", types.SamplingArgs{}); got != "prompt" {
+		t.Errorf("Expected echo of the prompt, got %q", got)
+	}
+	if got, _ := NewRuleClient(AlwaysAnswer("<answer>4</answer>")).CreateChatCompletion(ctx, "m", messages, types.SamplingArgs{}); got != "<answer>4</answer>" {
+		t.Errorf("Expected fixed answer, got %q", got)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := NewRuleClient(AlwaysAnswer("x")).CreateChatCompletion(cancelled, "m", messages, types.SamplingArgs{}); err == nil {
+		t.Error("Expected an error for a cancelled context")
+	}
+}
+
+func TestRuleClient_ToolEnvRollout(t *testing.T) {
+	config := types.Config{Model: "rule", MessageType: "chat"}
+	env, err := envs.NewToolEnv(config, []tools.Tool{tools.NewCalculator()}, 4)
+	if err != nil {
+		t.Fatalf("NewToolEnv failed: %v", err)
+	}
+
+	// Call the calculator first, then answer with its result
+	client := NewRuleClient(func(messages []types.Message) string {
+		last := messages[len(messages)-1].Content
+		if result, ok := strings.CutPrefix(last, "<result>"); ok {
+			result = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(result), "</result>"))
+			return "<think>\nthe calculator says " + result + "\n</think>\n<answer>\n" + result + "\n</answer>"
+		}
+		return "<think>\nuse the calculator\n</think>\n<tool>{\"name\": \"calculate\", \"args\": {\"expression\": \"2 + 2\"}}</tool>"
+	})
+
+	rollout, err := env.Rollout(context.Background(), client, config.Model, env.FormatPrompt("What is 2 + 2?"), "4", config.SamplingArgs)
+	if err != nil {
+		t.Fatalf("Rollout failed: %v", err)
+	}
+
+	if rollout.Terminated != types.TerminatedCompleted {
+		t.Errorf("Expected a completed rollout, got %q", rollout.Terminated)
+	}
+	if rollout.Metrics["correct_answer"] != 1.0 {
+		t.Errorf("Expected the correct answer, got metrics %v for %q", rollout.Metrics, rollout.Response)
+	}
+	if trace, _ := rollout.State["tool_executions"].([]rubrics.ToolExecution); len(trace) != 1 || !trace[0].Success {
+		t.Errorf("Expected one successful tool execution, got %+v", trace)
+	}
+}
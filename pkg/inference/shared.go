@@ -0,0 +1,104 @@
+package inference
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/rizome-dev/go-verifiers/pkg/types"
+	"github.com/rizome-dev/go-verifiers/pkg/utils"
+)
+
+// SharedClientOptions configures NewSharedClient
+type SharedClientOptions struct {
+	BaseURL           string       // API root, as for NewHTTPClient
+	APIKey            string       // API key, as for NewHTTPClient
+	MaxConcurrent     int          // Maximum requests in flight; 0 means no limit
+	RequestsPerSecond float64      // Maximum requests started per second; 0 means no limit
+	HTTPClient        *http.Client // Optional; defaults to the pooled client of NewHTTPClient
+}
+
+// SharedClient is an HTTPClient meant to be shared by every environment that
+// talks to one server. All completion requests made through it share a single
+// budget of concurrent requests and requests per second, so running many
+// environments at once cannot overwhelm the server. Waiting for the budget
+// honors context cancellation. Other requests, such as ListModels, are not limited.
+type SharedClient struct {
+	*HTTPClient
+	slots   chan struct{}      // Semaphore of in-flight requests; nil means no limit
+	limiter *utils.RateLimiter // nil means no rate limit
+}
+
+// NewSharedClient creates a client whose completion requests respect the
+// concurrency and rate limits in opts
+func NewSharedClient(opts SharedClientOptions) *SharedClient {
+	client := &SharedClient{
+		HTTPClient: NewHTTPClient(opts.BaseURL, opts.APIKey),
+	}
+	if opts.HTTPClient != nil {
+		client.HTTPClient.HTTPClient = opts.HTTPClient
+	}
+	if opts.MaxConcurrent > 0 {
+		client.slots = make(chan struct{}, opts.MaxConcurrent)
+		// Keep an idle connection for every request that may be in flight
+		if transport, ok := client.HTTPClient.HTTPClient.Transport.(*http.Transport); ok && opts.HTTPClient == nil {
+			transport.MaxIdleConnsPerHost = max(transport.MaxIdleConnsPerHost, opts.MaxConcurrent)
+		}
+	}
+	if opts.RequestsPerSecond > 0 {
+		client.limiter = utils.NewRateLimiter(opts.RequestsPerSecond)
+	}
+	return client
+}
+
+// acquire waits for a free request slot and a rate token. The returned release
+// function must be called once the request finishes.
+func (c *SharedClient) acquire(ctx context.Context) (func(), error) {
+	release := func() {}
+	if c.slots != nil {
+		select {
+		case c.slots <- struct{}{}:
+			release = func() { <-c.slots }
+		case <-ctx.Done():
+			return nil, fmt.Errorf("waiting for a request slot: %w", ctx.Err())
+		}
+	}
+	if c.limiter != nil {
+		if err := c.limiter.Wait(ctx); err != nil {
+			release()
+			return nil, fmt.Errorf("waiting for the rate limit: %w", err)
+		}
+	}
+	return release, nil
+}
+
+// CreateChatCompletion creates a chat completion within the shared limits
+func (c *SharedClient) CreateChatCompletion(ctx context.Context, model string, messages []types.Message, args types.SamplingArgs) (string, error) {
+	release, err := c.acquire(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer release()
+	return c.HTTPClient.CreateChatCompletion(ctx, model, messages, args)
+}
+
+// CreateChatCompletionWithTools creates a native tool calling chat completion
+// within the shared limits
+func (c *SharedClient) CreateChatCompletionWithTools(ctx context.Context, model string, messages []types.Message, args types.SamplingArgs) (types.Message, error) {
+	release, err := c.acquire(ctx)
+	if err != nil {
+		return types.Message{}, err
+	}
+	defer release()
+	return c.HTTPClient.CreateChatCompletionWithTools(ctx, model, messages, args)
+}
+
+// CreateCompletion creates a text completion within the shared limits
+func (c *SharedClient) CreateCompletion(ctx context.Context, model string, prompt string, args types.SamplingArgs) (string, error) {
+	release, err := c.acquire(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer release()
+	return c.HTTPClient.CreateCompletion(ctx, model, prompt, args)
+}
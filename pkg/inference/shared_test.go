@@ -0,0 +1,82 @@
+package inference
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rizome-dev/go-verifiers/pkg/types"
+)
+
+func TestSharedClient_LimitsInFlight(t *testing.T) {
+	var inFlight, peak atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			old := peak.Load()
+			if current <= old || peak.CompareAndSwap(old, current) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		json.NewEncoder(w).Encode(MockChatResponse("ok"))
+	}))
+	defer server.Close()
+
+	const limit = 3
+	client := NewSharedClient(SharedClientOptions{BaseURL: server.URL, MaxConcurrent: limit})
+	messages := []types.Message{{Role: "user", Content: "hi"}}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.CreateChatCompletion(context.Background(), "test-model", messages, types.SamplingArgs{}); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("CreateChatCompletion failed: %v", err)
+	}
+	if got := peak.Load(); got > limit {
+		t.Errorf("Expected at most %d requests in flight, got %d", limit, got)
+	}
+}
+
+func TestSharedClient_HonorsCancellation(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		json.NewEncoder(w).Encode(MockChatResponse("ok"))
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := NewSharedClient(SharedClientOptions{BaseURL: server.URL, MaxConcurrent: 1})
+	messages := []types.Message{{Role: "user", Content: "hi"}}
+
+	// Occupy the only slot
+	go client.CreateChatCompletion(context.Background(), "test-model", messages, types.SamplingArgs{})
+	for len(client.slots) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := client.CreateChatCompletion(ctx, "test-model", messages, types.SamplingArgs{}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the wait to end with the context, got %v", err)
+	}
+}
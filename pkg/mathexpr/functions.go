@@ -0,0 +1,368 @@
+package mathexpr
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Mathematical function wrappers for govaluate
+func sqrt(args ...interface{}) (interface{}, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("sqrt requires exactly 1 argument")
+	}
+	val, err := toFloat64(args[0])
+	if err != nil {
+		return nil, err
+	}
+	if val < 0 {
+		return nil, fmt.Errorf("math domain error (sqrt of negative)")
+	}
+	return math.Sqrt(val), nil
+}
+
+func sin(args ...interface{}) (interface{}, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("sin requires exactly 1 argument")
+	}
+	val, err := toFloat64(args[0])
+	if err != nil {
+		return nil, err
+	}
+	return math.Sin(val), nil
+}
+
+func cos(args ...interface{}) (interface{}, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("cos requires exactly 1 argument")
+	}
+	val, err := toFloat64(args[0])
+	if err != nil {
+		return nil, err
+	}
+	return math.Cos(val), nil
+}
+
+func tan(args ...interface{}) (interface{}, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("tan requires exactly 1 argument")
+	}
+	val, err := toFloat64(args[0])
+	if err != nil {
+		return nil, err
+	}
+	return math.Tan(val), nil
+}
+
+func log(args ...interface{}) (interface{}, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("log requires exactly 1 argument")
+	}
+	val, err := toFloat64(args[0])
+	if err != nil {
+		return nil, err
+	}
+	if err := checkLogDomain(val); err != nil {
+		return nil, err
+	}
+	return math.Log10(val), nil
+}
+
+func ln(args ...interface{}) (interface{}, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("ln requires exactly 1 argument")
+	}
+	val, err := toFloat64(args[0])
+	if err != nil {
+		return nil, err
+	}
+	if err := checkLogDomain(val); err != nil {
+		return nil, err
+	}
+	return math.Log(val), nil
+}
+
+func exp(args ...interface{}) (interface{}, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("exp requires exactly 1 argument")
+	}
+	val, err := toFloat64(args[0])
+	if err != nil {
+		return nil, err
+	}
+	return math.Exp(val), nil
+}
+
+func pow(args ...interface{}) (interface{}, error) {
+	if len(args) != 2 {
+		return nil, fmt.Errorf("pow requires exactly 2 arguments")
+	}
+	base, err := toFloat64(args[0])
+	if err != nil {
+		return nil, err
+	}
+	exponent, err := toFloat64(args[1])
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep integer powers exact when the result fits in int64
+	if base == math.Trunc(base) && exponent == math.Trunc(exponent) && exponent >= 0 && exponent <= 64 && math.Abs(base) < math.MaxInt64 {
+		if result, ok := powInt(int64(base), int64(exponent)); ok {
+			return exactInt(result), nil
+		}
+	}
+	return math.Pow(base, exponent), nil
+}
+
+func abs(args ...interface{}) (interface{}, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("abs requires exactly 1 argument")
+	}
+	val, err := toFloat64(args[0])
+	if err != nil {
+		return nil, err
+	}
+	return math.Abs(val), nil
+}
+
+func ceil(args ...interface{}) (interface{}, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("ceil requires exactly 1 argument")
+	}
+	val, err := toFloat64(args[0])
+	if err != nil {
+		return nil, err
+	}
+	return math.Ceil(val), nil
+}
+
+func floor(args ...interface{}) (interface{}, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("floor requires exactly 1 argument")
+	}
+	val, err := toFloat64(args[0])
+	if err != nil {
+		return nil, err
+	}
+	return math.Floor(val), nil
+}
+
+func round(args ...interface{}) (interface{}, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("round requires exactly 1 argument")
+	}
+	val, err := toFloat64(args[0])
+	if err != nil {
+		return nil, err
+	}
+	return math.Round(val), nil
+}
+
+func mod(args ...interface{}) (interface{}, error) {
+	if len(args) != 2 {
+		return nil, fmt.Errorf("mod requires exactly 2 arguments")
+	}
+	a, err := toFloat64(args[0])
+	if err != nil {
+		return nil, err
+	}
+	b, err := toFloat64(args[1])
+	if err != nil {
+		return nil, err
+	}
+	if b == 0 {
+		return nil, fmt.Errorf("mod by zero")
+	}
+	return math.Mod(a, b), nil
+}
+
+func factorial(args ...interface{}) (interface{}, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("factorial requires exactly 1 argument")
+	}
+	n, err := toInt64(args[0])
+	if err != nil {
+		return nil, fmt.Errorf("factorial requires an integer: %w", err)
+	}
+	if n < 0 {
+		return nil, fmt.Errorf("factorial of negative number %d", n)
+	}
+	if n > 20 {
+		// Beyond int64 range; fall back to the gamma function
+		return math.Gamma(float64(n) + 1), nil
+	}
+	result := int64(1)
+	for i := int64(2); i <= n; i++ {
+		result *= i
+	}
+	return exactInt(result), nil
+}
+
+func gcd(args ...interface{}) (interface{}, error) {
+	if len(args) != 2 {
+		return nil, fmt.Errorf("gcd requires exactly 2 arguments")
+	}
+	a, err := toInt64(args[0])
+	if err != nil {
+		return nil, fmt.Errorf("gcd requires integers: %w", err)
+	}
+	b, err := toInt64(args[1])
+	if err != nil {
+		return nil, fmt.Errorf("gcd requires integers: %w", err)
+	}
+	return exactInt(gcdInt(a, b)), nil
+}
+
+func lcm(args ...interface{}) (interface{}, error) {
+	if len(args) != 2 {
+		return nil, fmt.Errorf("lcm requires exactly 2 arguments")
+	}
+	a, err := toInt64(args[0])
+	if err != nil {
+		return nil, fmt.Errorf("lcm requires integers: %w", err)
+	}
+	b, err := toInt64(args[1])
+	if err != nil {
+		return nil, fmt.Errorf("lcm requires integers: %w", err)
+	}
+	if a == 0 || b == 0 {
+		return exactInt(0), nil
+	}
+	a, b = abs64(a), abs64(b)
+	q := a / gcdInt(a, b)
+	// Fall back to float64 when the product overflows int64
+	if a < 0 || b < 0 || q > math.MaxInt64/b {
+		return math.Abs(float64(q) * float64(b)), nil
+	}
+	return exactInt(q * b), nil
+}
+
+// gcdInt computes the non-negative greatest common divisor
+func gcdInt(a, b int64) int64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	if a < 0 {
+		return -a
+	}
+	return a
+}
+
+// powInt computes base^exponent exactly, reporting false on overflow
+func powInt(base, exponent int64) (int64, bool) {
+	result := int64(1)
+	for i := int64(0); i < exponent; i++ {
+		if base != 0 && (result > math.MaxInt64/abs64(base) || result < math.MinInt64/abs64(base)) {
+			return 0, false
+		}
+		result *= base
+	}
+	return result, true
+}
+
+// abs64 returns the absolute value of an int64
+func abs64(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// maxExactFloat is the largest integer magnitude float64 represents exactly
+const maxExactFloat = 1 << 53
+
+// exactInt returns an integer result as float64 when that is lossless, and as
+// int64 otherwise to keep it exact; DefaultFunctions converts the latter to float64
+func exactInt(v int64) interface{} {
+	if v <= maxExactFloat && v >= -maxExactFloat {
+		return float64(v)
+	}
+	return v
+}
+
+// toInt64 converts an integral value to int64
+func toInt64(val interface{}) (int64, error) {
+	if v, ok := val.(int64); ok {
+		return v, nil
+	}
+	f, err := toFloat64(val)
+	if err != nil {
+		return 0, err
+	}
+	if f != math.Trunc(f) || math.IsInf(f, 0) || f > math.MaxInt64 || f < math.MinInt64 {
+		return 0, fmt.Errorf("%v is not an integer", val)
+	}
+	return int64(f), nil
+}
+
+func max(args ...interface{}) (interface{}, error) {
+	if len(args) < 1 {
+		return nil, fmt.Errorf("max requires at least 1 argument")
+	}
+	maxVal, err := toFloat64(args[0])
+	if err != nil {
+		return nil, err
+	}
+	for i := 1; i < len(args); i++ {
+		val, err := toFloat64(args[i])
+		if err != nil {
+			return nil, err
+		}
+		if val > maxVal {
+			maxVal = val
+		}
+	}
+	return maxVal, nil
+}
+
+func min(args ...interface{}) (interface{}, error) {
+	if len(args) < 1 {
+		return nil, fmt.Errorf("min requires at least 1 argument")
+	}
+	minVal, err := toFloat64(args[0])
+	if err != nil {
+		return nil, err
+	}
+	for i := 1; i < len(args); i++ {
+		val, err := toFloat64(args[i])
+		if err != nil {
+			return nil, err
+		}
+		if val < minVal {
+			minVal = val
+		}
+	}
+	return minVal, nil
+}
+
+// checkLogDomain rejects arguments for which a logarithm is undefined
+func checkLogDomain(val float64) error {
+	if val == 0 {
+		return fmt.Errorf("math domain error (log of zero)")
+	}
+	if val < 0 {
+		return fmt.Errorf("math domain error (log of negative)")
+	}
+	return nil
+}
+
+// toFloat64 converts an interface to float64
+func toFloat64(val interface{}) (float64, error) {
+	switch v := val.(type) {
+	case float64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	case int:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case int32:
+		return float64(v), nil
+	case string:
+		return strconv.ParseFloat(v, 64)
+	default:
+		return 0, fmt.Errorf("cannot convert %T to float64", val)
+	}
+}
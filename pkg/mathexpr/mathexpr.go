@@ -0,0 +1,143 @@
+// Package mathexpr provides the function set and preprocessing shared by the
+// govaluate-based expression evaluators, the calculator tool and CodeMathEnv.
+package mathexpr
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/Knetic/govaluate"
+)
+
+// DefaultFunctions returns the functions available in evaluated expressions. Each
+// call returns a new map, so callers may add their own functions. Results are
+// float64, which govaluate's operators require; ExactResult recovers integer
+// results too large for float64 to hold exactly.
+func DefaultFunctions() map[string]govaluate.ExpressionFunction {
+	functions := exactFunctions()
+	for _, name := range []string{"pow", "factorial", "gcd", "lcm"} {
+		functions[name] = floatResults(functions[name])
+	}
+	return functions
+}
+
+// exactFunctions returns the expression functions with integer results beyond
+// maxExactFloat returned as int64. govaluate rejects int64 operands, so they only
+// suit expressions whose result is a single function call.
+func exactFunctions() map[string]govaluate.ExpressionFunction {
+	return map[string]govaluate.ExpressionFunction{
+		"sqrt":      sqrt,
+		"sin":       sin,
+		"cos":       cos,
+		"tan":       tan,
+		"log":       log,
+		"ln":        ln,
+		"exp":       exp,
+		"pow":       pow,
+		"abs":       abs,
+		"ceil":      ceil,
+		"floor":     floor,
+		"round":     round,
+		"max":       max,
+		"min":       min,
+		"mod":       mod,
+		"factorial": factorial,
+		"gcd":       gcd,
+		"lcm":       lcm,
+	}
+}
+
+// floatResults wraps fn so that int64 results are returned as float64
+func floatResults(fn govaluate.ExpressionFunction) govaluate.ExpressionFunction {
+	return func(args ...interface{}) (interface{}, error) {
+		result, err := fn(args...)
+		if v, ok := result.(int64); ok {
+			return float64(v), err
+		}
+		return result, err
+	}
+}
+
+// ExactResult returns result as an exact int64 when it is an integer too large for
+// float64 to hold exactly and expr, evaluated with parameters, is a single integer
+// function call such as pow(3, 39) or factorial(20). Otherwise, including when the
+// function result takes part in further arithmetic, result is returned unchanged.
+func ExactResult(expr string, parameters map[string]interface{}, result interface{}) interface{} {
+	v, ok := result.(float64)
+	if !ok || v != math.Trunc(v) || math.Abs(v) <= maxExactFloat {
+		return result
+	}
+
+	expression, err := govaluate.NewEvaluableExpressionWithFunctions(expr, exactFunctions())
+	if err != nil {
+		return result
+	}
+	exact, err := expression.Evaluate(parameters)
+	if err != nil {
+		return result
+	}
+	if exact, ok := exact.(int64); ok {
+		return exact
+	}
+	return result
+}
+
+// Constants returns the named constants available in evaluated expressions
+func Constants() map[string]interface{} {
+	return map[string]interface{}{
+		"pi": math.Pi,
+		"e":  math.E,
+	}
+}
+
+var (
+	// modOperatorPattern matches "mod" used as an infix operator
+	modOperatorPattern = regexp.MustCompile(`\s+mod\s+`)
+
+	// numberFactorPattern matches a number directly followed by an identifier or
+	// "(". The leading group keeps digits inside names like log10 from matching.
+	// An "e" that starts an exponent (1e3, 2e-5) is not treated as an identifier,
+	// so scientific notation stays intact.
+	numberFactorPattern = regexp.MustCompile(`(^|[^A-Za-z0-9_.])((?:\d+\.?\d*|\.\d+)(?:[eE][-+]?\d+)?)([A-DF-Za-df-z_(]|[eE](?:[^0-9+\-]|$))`)
+
+	// parenFactorPattern matches ")" directly followed by an identifier or "("
+	parenFactorPattern = regexp.MustCompile(`\)([A-Za-z_(])`)
+)
+
+// Preprocess rewrites common mathematical notation into govaluate syntax: unicode
+// operators, infix "mod" and implicit multiplication such as 3pi or 2(x+1)
+func Preprocess(expr string) string {
+	// Replace common mathematical notation
+	expr = strings.ReplaceAll(expr, "π", "pi")
+	expr = strings.ReplaceAll(expr, "×", "*")
+	expr = strings.ReplaceAll(expr, "÷", "/")
+	expr = strings.ReplaceAll(expr, "²", "^2")
+	expr = strings.ReplaceAll(expr, "³", "^3")
+
+	// Infix modulo (e.g., 17 mod 5 -> 17 % 5)
+	expr = modOperatorPattern.ReplaceAllString(expr, " % ")
+
+	// Handle implicit multiplication (e.g., 3pi -> 3*pi, 2(x) -> 2*(x), (a)(b) -> (a)*(b))
+	expr = numberFactorPattern.ReplaceAllString(expr, "${1}${2}*${3}")
+	expr = parenFactorPattern.ReplaceAllString(expr, ")*${1}")
+
+	return expr
+}
+
+// CheckFinite reports an error for infinite and NaN results, which govaluate
+// produces for division by zero instead of failing
+func CheckFinite(result interface{}) error {
+	v, ok := result.(float64)
+	if !ok {
+		return nil
+	}
+	if math.IsNaN(v) {
+		return fmt.Errorf("math domain error (result is not a number)")
+	}
+	if math.IsInf(v, 0) {
+		return fmt.Errorf("division by zero or overflow")
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package mathexpr
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Knetic/govaluate"
+)
+
+func TestPreprocess(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{input: "3pi", expected: "3*pi"},
+		{input: "2(3+4)", expected: "2*(3+4)"},
+		{input: "2sqrt(9)", expected: "2*sqrt(9)"},
+		{input: "4sin(x)", expected: "4*sin(x)"},
+		{input: "(1+2)(3+4)", expected: "(1+2)*(3+4)"},
+		{input: "(1+2)x", expected: "(1+2)*x"},
+		{input: "0.5x + 2e", expected: "0.5*x + 2*e"},
+		{input: "1e3", expected: "1e3"},
+		{input: "2.5E-3", expected: "2.5E-3"},
+		{input: "log10(100)", expected: "log10(100)"},
+		{input: "sin(x)", expected: "sin(x)"},
+		{input: "x2 + 1", expected: "x2 + 1"},
+		{input: "17 mod 5", expected: "17 % 5"},
+		{input: "2π × 3 ÷ 4", expected: "2*pi * 3 / 4"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := Preprocess(tt.input); got != tt.expected {
+				t.Errorf("Preprocess(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDefaultFunctions(t *testing.T) {
+	tests := []struct {
+		expression string
+		expected   interface{}
+		wantErr    string
+	}{
+		{expression: "factorial(5)", expected: 120.0},
+		{expression: "pow(3, 39)", expected: int64(4052555153018976267)},
+		{expression: "pow(2, 60) + 1", expected: 1152921504606846976.0},
+		{expression: "factorial(20) + 1", expected: 2432902008176640000.0},
+		{expression: "factorial(19) * 2", expected: 243290200817664000.0},
+		{expression: "lcm(4000000000, 3000000001)", expected: 1.2000000004e19},
+		{expression: "lcm(4, 6) * 2", expected: 24.0},
+		{expression: "max(1, 7, 3) + min(4, 2)", expected: 9.0},
+		{expression: "2pi", expected: 2 * 3.141592653589793},
+		{expression: "1/0", wantErr: "division by zero"},
+		{expression: "sqrt(-4)", wantErr: "math domain error (sqrt of negative)"},
+		{expression: "log(0)", wantErr: "math domain error (log of zero)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			expression, err := govaluate.NewEvaluableExpressionWithFunctions(Preprocess(tt.expression), DefaultFunctions())
+			if err != nil {
+				t.Fatalf("Failed to parse %q: %v", tt.expression, err)
+			}
+			result, err := expression.Evaluate(Constants())
+			if err == nil {
+				err = CheckFinite(result)
+			}
+			if err == nil {
+				result = ExactResult(Preprocess(tt.expression), Constants(), result)
+			}
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Evaluate(%q) = %v, %v; want error containing %q", tt.expression, result, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Evaluate(%q) error = %v", tt.expression, err)
+			}
+			if result != tt.expected {
+				t.Errorf("Evaluate(%q) = %v (%T), want %v (%T)", tt.expression, result, result, tt.expected, tt.expected)
+			}
+		})
+	}
+}
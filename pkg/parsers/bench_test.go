@@ -0,0 +1,96 @@
+package parsers
+
+// Parser benchmarks. Run with:
+//
+//	go test -run xxx -bench . ./pkg/parsers
+//
+// Baseline (Intel Xeon, go1.23) before tag extraction stopped compiling a regex
+// per field on every call:
+//
+//	BenchmarkXMLParser_ParseXML       33303 ns/op   15065 B/op   133 allocs/op
+//	BenchmarkSmolaParser_ParseSmola   33108 ns/op   16002 B/op   149 allocs/op
+//	BenchmarkThinkParser_Parse          203 ns/op      32 B/op     1 allocs/op
+//
+// After:
+//
+//	BenchmarkXMLParser_ParseXML         903 ns/op     344 B/op     3 allocs/op
+//	BenchmarkSmolaParser_ParseSmola    4594 ns/op    1280 B/op    19 allocs/op
+//	BenchmarkThinkParser_Parse           83 ns/op       0 B/op     0 allocs/op
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+var benchXMLResponse = `<think>
+First I need to work out the total cost of the items.
+Each item costs 12 dollars and there are 7 of them, so 12 * 7 = 84.
+Then I add the shipping fee of 6 dollars, giving 90.
+</think>
+<tool>
+{"name": "calculate", "args": {"expression": "12 * 7 + 6"}}
+</tool>
+<answer>
+90
+</answer>`
+
+var benchThinkResponse = "<think>\n" + strings.Repeat("Let me reason about this step carefully.\n", 40) + "</think>\nThe final answer is 42."
+
+func BenchmarkXMLParser_ParseXML(b *testing.B) {
+	parser, err := NewXMLParser([]interface{}{"think", []string{"tool", "answer"}}, "answer")
+	if err != nil {
+		b.Fatalf("Failed to create parser: %v", err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := parser.ParseXML(benchXMLResponse, true); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSmolaParser_ParseSmola(b *testing.B) {
+	parser, err := NewSmolaParser([]interface{}{"think", "tool", "answer"})
+	if err != nil {
+		b.Fatalf("Failed to create parser: %v", err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := parser.ParseSmola(benchXMLResponse, true); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkThinkParser_Parse(b *testing.B) {
+	parser := NewThinkParser()
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := parser.Parse(ctx, benchThinkResponse); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// TestXMLParser_ParseXMLAllocs guards against reintroducing per-call regex compilation
+func TestXMLParser_ParseXMLAllocs(t *testing.T) {
+	parser, err := NewXMLParser([]interface{}{"think", []string{"tool", "answer"}}, "answer")
+	if err != nil {
+		t.Fatalf("Failed to create parser: %v", err)
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		parser.ParseXML(benchXMLResponse, true)
+	})
+	if allocs > 10 {
+		t.Errorf("ParseXML allocated %.0f times per call, want at most 10", allocs)
+	}
+}
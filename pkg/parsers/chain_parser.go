@@ -0,0 +1,106 @@
+package parsers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// ChainParser tries several parsers in order and returns the first result it
+// accepts, e.g. an XML parser with a last-line parser as fallback for responses
+// that ignore the tags. By default a result is accepted when it is non-empty
+// after trimming whitespace; SetAccept changes that.
+type ChainParser struct {
+	parsers []Parser
+	accept  func(parsed string) bool
+}
+
+// NewChainParser creates a parser that tries parsers in the given order
+func NewChainParser(parsers ...Parser) *ChainParser {
+	return &ChainParser{
+		parsers: parsers,
+		accept:  nonEmpty,
+	}
+}
+
+// nonEmpty is the default acceptance check of ChainParser
+func nonEmpty(parsed string) bool {
+	return strings.TrimSpace(parsed) != ""
+}
+
+// SetAccept sets the check that decides whether a parser's result ends the chain.
+// nil restores the default, which accepts any result that is not just whitespace.
+func (p *ChainParser) SetAccept(accept func(parsed string) bool) {
+	if accept == nil {
+		accept = nonEmpty
+	}
+	p.accept = accept
+}
+
+// Parse returns the first accepted result. A parser that returns an error counts
+// as a miss. If no result is accepted it returns an empty string, or the last
+// error when every parser failed.
+func (p *ChainParser) Parse(ctx context.Context, response string) (string, error) {
+	parsed, _, err := p.ParseWithTracking(ctx, response)
+	return parsed, err
+}
+
+// ParseWithTracking returns the first accepted result with the metadata of the
+// parser that produced it. "parser_type" is "chain", "matched_parser_type" holds
+// that parser's own type and "parser_index" its position, or -1 if none matched.
+func (p *ChainParser) ParseWithTracking(ctx context.Context, response string) (string, map[string]interface{}, error) {
+	var lastErr error
+	failures := 0
+	for i, parser := range p.parsers {
+		parsed, metadata, err := parser.ParseWithTracking(ctx, response)
+		if err != nil {
+			lastErr = err
+			failures++
+			continue
+		}
+		if !p.accept(parsed) {
+			continue
+		}
+
+		merged := make(map[string]interface{}, len(metadata)+3)
+		for k, v := range metadata {
+			merged[k] = v
+		}
+		merged["matched_parser_type"] = metadata["parser_type"]
+		merged["parser_type"] = "chain"
+		merged["parser_index"] = i
+		return parsed, merged, nil
+	}
+
+	if failures > 0 && failures == len(p.parsers) {
+		return "", nil, fmt.Errorf("all %d parsers failed: %w", failures, lastErr)
+	}
+
+	metadata := map[string]interface{}{
+		"parser_type":  "chain",
+		"parser_index": -1,
+	}
+	return "", metadata, nil
+}
+
+// FollowsFormat returns the best format score of the chained parsers
+func (p *ChainParser) FollowsFormat(text string) float64 {
+	best := 0.0
+	for _, parser := range p.parsers {
+		if score := parser.FollowsFormat(text); score > best {
+			best = score
+		}
+	}
+	return best
+}
+
+// GetFormatStr returns the format of the first parser that describes one, since
+// that is the format the chain prefers
+func (p *ChainParser) GetFormatStr() string {
+	for _, parser := range p.parsers {
+		if formatter, ok := parser.(Formatter); ok {
+			return formatter.GetFormatStr()
+		}
+	}
+	return ""
+}
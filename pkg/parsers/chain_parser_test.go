@@ -0,0 +1,73 @@
+package parsers
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+)
+
+func TestChainParser_FallsBack(t *testing.T) {
+	xmlParser, err := NewXMLParser([]interface{}{"think", "answer"}, "answer")
+	if err != nil {
+		t.Fatalf("NewXMLParser failed: %v", err)
+	}
+	chain := NewChainParser(xmlParser, NewLastLineParser())
+
+	tests := []struct {
+		name          string
+		response      string
+		expected      string
+		parserIndex   int
+		matchedParser string
+	}{
+		{"xml answer", "<think>\nadd\n</think>\n<answer>\n4\n</answer>", "4", 0, "xml"},
+		{"plain text falls back", "Two plus two\nis 4", "is 4", 1, "last_line"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, metadata, err := chain.ParseWithTracking(context.Background(), tt.response)
+			if err != nil {
+				t.Fatalf("ParseWithTracking failed: %v", err)
+			}
+			if parsed != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, parsed)
+			}
+			if metadata["parser_index"] != tt.parserIndex || metadata["matched_parser_type"] != tt.matchedParser {
+				t.Errorf("Expected parser %d (%s), got metadata %v", tt.parserIndex, tt.matchedParser, metadata)
+			}
+		})
+	}
+
+	// A stricter acceptance check skips results that are not numbers
+	chain.SetAccept(func(parsed string) bool {
+		_, err := strconv.ParseFloat(parsed, 64)
+		return err == nil
+	})
+	if parsed, _ := chain.Parse(context.Background(), "Two plus two\nis 4"); parsed != "" {
+		t.Errorf("Expected no accepted result, got %q", parsed)
+	}
+}
+
+// failingParser always returns an error
+type failingParser struct {
+	*BaseParser
+}
+
+func (p *failingParser) ParseWithTracking(ctx context.Context, response string) (string, map[string]interface{}, error) {
+	return "", nil, errors.New("broken")
+}
+
+func TestChainParser_AllFail(t *testing.T) {
+	chain := NewChainParser(&failingParser{NewBaseParser()}, &failingParser{NewBaseParser()})
+	if _, err := chain.Parse(context.Background(), "4"); err == nil {
+		t.Error("Expected an error when every parser fails")
+	}
+
+	// One failure is only a miss
+	chain = NewChainParser(&failingParser{NewBaseParser()}, NewBaseParser())
+	if parsed, err := chain.Parse(context.Background(), " 4 "); err != nil || parsed != "4" {
+		t.Errorf("Expected fallback to the base parser, got %q, %v", parsed, err)
+	}
+}
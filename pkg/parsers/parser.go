@@ -2,6 +2,7 @@ package parsers
 
 import (
 	"context"
+	"regexp"
 	"strings"
 )
 
@@ -12,6 +13,15 @@ type Parser interface {
 	
 	// ParseWithTracking extracts answer and tracks parsing metadata
 	ParseWithTracking(ctx context.Context, response string) (string, map[string]interface{}, error)
+	
+	// FollowsFormat scores how well text follows the expected format, from 0.0 to 1.0
+	FollowsFormat(text string) float64
+}
+
+// Formatter is optionally implemented by parsers that can describe their expected
+// format, for use in system prompts
+type Formatter interface {
+	GetFormatStr() string
 }
 
 // BaseParser provides a default implementation that returns the response as-is
@@ -43,6 +53,11 @@ func (p *BaseParser) ParseWithTracking(ctx context.Context, response string) (st
 	return parsed, metadata, nil
 }
 
+// FollowsFormat always returns 1.0 since any response is accepted
+func (p *BaseParser) FollowsFormat(text string) float64 {
+	return 1.0
+}
+
 // RegexParser parses responses using regular expressions
 type RegexParser struct {
 	pattern string
@@ -53,14 +68,41 @@ func NewRegexParser(pattern string) *RegexParser {
 	return &RegexParser{pattern: pattern}
 }
 
+// FollowsFormat returns 1.0 if text contains a match for the pattern.
+// An invalid pattern never matches.
+func (p *RegexParser) FollowsFormat(text string) float64 {
+	re, err := regexp.Compile(p.pattern)
+	if err != nil || !re.MatchString(text) {
+		return 0.0
+	}
+	return 1.0
+}
+
 // LastLineParser extracts the last non-empty line
-type LastLineParser struct{}
+type LastLineParser struct {
+	stripLabel         bool // Remove a leading "Answer:" or "Final answer:" label
+	stripTrailingPunct bool // Remove trailing "." and "!"
+}
+
+// answerLabelPattern matches a leading answer label such as "Final answer:" or
+// "The answer is"
+var answerLabelPattern = regexp.MustCompile(`(?i)^(?:the\s+)?(?:final\s+)?answer(?:\s*:|\s+is\b:?)\s*`)
 
 // NewLastLineParser creates a parser that returns the last non-empty line
 func NewLastLineParser() *LastLineParser {
 	return &LastLineParser{}
 }
 
+// NewLastLineParserWithOptions creates a last line parser that can remove a leading
+// answer label (case-insensitive) and trailing "." and "!", so "Final answer: 42."
+// parses as "42"
+func NewLastLineParserWithOptions(stripLabel bool, stripTrailingPunct bool) *LastLineParser {
+	return &LastLineParser{
+		stripLabel:         stripLabel,
+		stripTrailingPunct: stripTrailingPunct,
+	}
+}
+
 // Parse returns the last non-empty line
 func (p *LastLineParser) Parse(ctx context.Context, response string) (string, error) {
 	lines := strings.Split(response, "\n")
@@ -69,13 +111,24 @@ func (p *LastLineParser) Parse(ctx context.Context, response string) (string, er
 	for i := len(lines) - 1; i >= 0; i-- {
 		line := strings.TrimSpace(lines[i])
 		if line != "" {
-			return line, nil
+			return p.clean(line), nil
 		}
 	}
 	
 	return "", nil
 }
 
+// clean applies the configured label and punctuation stripping
+func (p *LastLineParser) clean(line string) string {
+	if p.stripLabel {
+		line = answerLabelPattern.ReplaceAllString(line, "")
+	}
+	if p.stripTrailingPunct {
+		line = strings.TrimRight(line, ".!")
+	}
+	return strings.TrimSpace(line)
+}
+
 // ParseWithTracking returns the last line with metadata
 func (p *LastLineParser) ParseWithTracking(ctx context.Context, response string) (string, map[string]interface{}, error) {
 	parsed, err := p.Parse(ctx, response)
@@ -91,4 +144,119 @@ func (p *LastLineParser) ParseWithTracking(ctx context.Context, response string)
 	}
 	
 	return parsed, metadata, nil
-}
\ No newline at end of file
+}
+
+// FollowsFormat returns 1.0 if text has a non-empty line
+func (p *LastLineParser) FollowsFormat(text string) float64 {
+	if strings.TrimSpace(text) == "" {
+		return 0.0
+	}
+	return 1.0
+}
+
+// BlockParser extracts a multi-line block between a start and end marker
+type BlockParser struct {
+	startMarker string
+	endMarker   string
+}
+
+// NewBlockParser creates a parser that returns the text between startMarker and endMarker.
+// An empty startMarker reads from the beginning of the response and an empty
+// endMarker reads to the end of the response. Internal newlines are preserved.
+func NewBlockParser(startMarker, endMarker string) *BlockParser {
+	return &BlockParser{
+		startMarker: startMarker,
+		endMarker:   endMarker,
+	}
+}
+
+// Parse returns the block following the last start marker, up to the next end marker
+func (p *BlockParser) Parse(ctx context.Context, response string) (string, error) {
+	block, _ := p.extract(response)
+	return block, nil
+}
+
+// ParseWithTracking returns the block with metadata
+func (p *BlockParser) ParseWithTracking(ctx context.Context, response string) (string, map[string]interface{}, error) {
+	parsed, found := p.extract(response)
+
+	metadata := map[string]interface{}{
+		"parser_type":   "block",
+		"block_found":   found,
+		"block_lines":   0,
+		"parsed_length": len(parsed),
+	}
+	if parsed != "" {
+		metadata["block_lines"] = strings.Count(parsed, "\n") + 1
+	}
+
+	return parsed, metadata, nil
+}
+
+// FollowsFormat returns 1.0 if text contains the start marker followed by the end
+// marker, ignoring markers that are empty
+func (p *BlockParser) FollowsFormat(text string) float64 {
+	if p.startMarker != "" {
+		idx := p.lastStart(text)
+		if idx == -1 {
+			return 0.0
+		}
+		text = text[idx+len(p.startMarker):]
+	}
+	if p.endMarker != "" && !strings.Contains(text, p.endMarker) {
+		return 0.0
+	}
+	return 1.0
+}
+
+// lastStart returns the index of the start marker of the last block in text, or -1.
+// When both markers are the same, as with ``` fences, markers pair up from the
+// front, so the last complete block starts at the second-to-last marker and an odd
+// marker out opens an unclosed block. Otherwise it is the last start marker.
+func (p *BlockParser) lastStart(text string) int {
+	if p.startMarker != p.endMarker {
+		return strings.LastIndex(text, p.startMarker)
+	}
+
+	markers := make([]int, 0)
+	for offset := 0; ; {
+		idx := strings.Index(text[offset:], p.startMarker)
+		if idx == -1 {
+			break
+		}
+		markers = append(markers, offset+idx)
+		offset += idx + len(p.startMarker)
+	}
+	switch {
+	case len(markers) == 0:
+		return -1
+	case len(markers)%2 == 1:
+		return markers[len(markers)-1]
+	default:
+		return markers[len(markers)-2]
+	}
+}
+
+// extract locates the block and reports whether the start marker was found
+func (p *BlockParser) extract(response string) (string, bool) {
+	text := response
+
+	// Start after the start marker of the last block
+	if p.startMarker != "" {
+		idx := p.lastStart(text)
+		if idx == -1 {
+			return "", false
+		}
+		text = text[idx+len(p.startMarker):]
+	}
+
+	// Stop at the first end marker after the start
+	if p.endMarker != "" {
+		if idx := strings.Index(text, p.endMarker); idx != -1 {
+			text = text[:idx]
+		}
+	}
+
+	// Trim surrounding blank lines and whitespace, keeping internal newlines
+	return strings.TrimSpace(text), true
+}
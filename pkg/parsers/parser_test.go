@@ -0,0 +1,177 @@
+package parsers
+
+import (
+	"context"
+	"testing"
+)
+
+func TestBlockParser_Parse(t *testing.T) {
+	tests := []struct {
+		name        string
+		startMarker string
+		endMarker   string
+		input       string
+		expected    string
+	}{
+		{
+			name:        "answer to end of message",
+			startMarker: "Answer:",
+			endMarker:   "",
+			input: `Let me write a short poem.
+Answer:
+Roses are red,
+Violets are blue,
+Go is fast.`,
+			expected: "Roses are red,\nViolets are blue,\nGo is fast.",
+		},
+		{
+			name:        "block between markers",
+			startMarker: "```go",
+			endMarker:   "```",
+			input:       "Here is the code:\n```go\nfunc add(a, b int) int {\n\treturn a + b\n}\n```\nThat should work.",
+			expected:    "func add(a, b int) int {\n\treturn a + b\n}",
+		},
+		{
+			name:        "uses last start marker",
+			startMarker: "Answer:",
+			endMarker:   "",
+			input:       "Answer: draft\nActually, let me reconsider.\nAnswer:\n- apples\n- pears",
+			expected:    "- apples\n- pears",
+		},
+		{
+			name:        "same start and end marker",
+			startMarker: "```",
+			endMarker:   "```",
+			input:       "First try:\n```\nx = 1\n```\nFixed:\n```\nx = 2\n```\nDone.",
+			expected:    "x = 2",
+		},
+		{
+			name:        "same marker unclosed last block",
+			startMarker: "```",
+			endMarker:   "```",
+			input:       "```\nx = 1\n```\n```\nx = 2",
+			expected:    "x = 2",
+		},
+		{
+			name:        "missing start marker",
+			startMarker: "Answer:",
+			endMarker:   "",
+			input:       "No marker here\nat all",
+			expected:    "",
+		},
+		{
+			name:        "empty markers return whole response",
+			startMarker: "",
+			endMarker:   "",
+			input:       "\nline one\nline two\n",
+			expected:    "line one\nline two",
+		},
+	}
+
+	ctx := context.Background()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := NewBlockParser(tt.startMarker, tt.endMarker)
+
+			got, err := parser.Parse(ctx, tt.input)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("Parse() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBlockParser_ParseWithTracking(t *testing.T) {
+	parser := NewBlockParser("Answer:", "")
+
+	parsed, metadata, err := parser.ParseWithTracking(context.Background(), "Answer:\n1. one\n2. two\n3. three")
+	if err != nil {
+		t.Fatalf("ParseWithTracking() error = %v", err)
+	}
+
+	if parsed != "1. one\n2. two\n3. three" {
+		t.Errorf("ParseWithTracking() = %q", parsed)
+	}
+	if metadata["block_found"] != true {
+		t.Errorf("Expected block_found to be true")
+	}
+	if metadata["block_lines"] != 3 {
+		t.Errorf("Expected 3 block lines, got %v", metadata["block_lines"])
+	}
+}
+
+func TestParser_FollowsFormat(t *testing.T) {
+	xmlParser, err := NewXMLParser([]interface{}{"think", "answer"}, "answer")
+	if err != nil {
+		t.Fatalf("NewXMLParser failed: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		parser   Parser
+		input    string
+		expected float64
+	}{
+		{name: "base accepts anything", parser: NewBaseParser(), input: "", expected: 1.0},
+		{name: "last line present", parser: NewLastLineParser(), input: "work\n42", expected: 1.0},
+		{name: "last line empty", parser: NewLastLineParser(), input: " \n ", expected: 0.0},
+		{name: "block markers present", parser: NewBlockParser("Answer:", "END"), input: "Answer: 42 END", expected: 1.0},
+		{name: "block end marker missing", parser: NewBlockParser("Answer:", "END"), input: "Answer: 42", expected: 0.0},
+		{name: "same-marker block closed", parser: NewBlockParser("```", "```"), input: "```\n42\n```\nDone.", expected: 1.0},
+		{name: "same-marker block unclosed", parser: NewBlockParser("```", "```"), input: "```\n41\n```\n```\n42", expected: 0.0},
+		{name: "think format", parser: NewThinkParser(), input: "<think>\nhmm\n</think>\n42", expected: 1.0},
+		{name: "think missing", parser: NewThinkParser(), input: "42", expected: 0.0},
+		{name: "xml complete", parser: xmlParser, input: "<think>\nhmm\n</think>\n<answer>\n42\n</answer>", expected: 1.0},
+		{name: "xml answer only", parser: xmlParser, input: "<answer>\n42\n</answer>", expected: 0.6},
+		{name: "xml missing", parser: xmlParser, input: "42", expected: 0.2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.parser.FollowsFormat(tt.input)
+			if diff := got - tt.expected; diff > 1e-9 || diff < -1e-9 {
+				t.Errorf("FollowsFormat(%q) = %v, want %v", tt.input, got, tt.expected)
+			}
+		})
+	}
+
+	regex := NewRegexParser(`\d+`)
+	if regex.FollowsFormat("answer 42") != 1.0 || regex.FollowsFormat("none") != 0.0 {
+		t.Error("RegexParser.FollowsFormat did not follow the pattern")
+	}
+}
+
+func TestLastLineParser_WithOptions(t *testing.T) {
+	tests := []struct {
+		name               string
+		stripLabel         bool
+		stripTrailingPunct bool
+		input              string
+		expected           string
+	}{
+		{name: "label and punctuation", stripLabel: true, stripTrailingPunct: true, input: "Let me think.\nFinal answer: 42.", expected: "42"},
+		{name: "sentence label", stripLabel: true, stripTrailingPunct: true, input: "The answer is 42!", expected: "42"},
+		{name: "case-insensitive label", stripLabel: true, input: "ANSWER: Paris", expected: "Paris"},
+		{name: "label only", stripLabel: true, input: "Final answer: 42.", expected: "42."},
+		{name: "punctuation only", stripTrailingPunct: true, input: "Final answer: 3.14.", expected: "Final answer: 3.14"},
+		{name: "no label present", stripLabel: true, stripTrailingPunct: true, input: "answers vary", expected: "answers vary"},
+		{name: "defaults unchanged", input: "Final answer: 42.", expected: "Final answer: 42."},
+	}
+
+	ctx := context.Background()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := NewLastLineParserWithOptions(tt.stripLabel, tt.stripTrailingPunct)
+			got, err := parser.Parse(ctx, tt.input)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("Parse(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"regexp"
 	"strings"
 )
 
@@ -83,16 +82,7 @@ func (p *SmolaParser) ParseSmola(text string, strip bool) (*ParsedSmola, error)
 	for _, field := range p.fields {
 		// Check each alternative tag name
 		for _, alt := range field.Alternatives {
-			// Create regex pattern for the tag
-			pattern := fmt.Sprintf(`<%s>\s*(.*?)\s*</%s>`, alt, alt)
-			re, err := regexp.Compile("(?s)" + pattern) // (?s) makes . match newlines
-			if err != nil {
-				return nil, fmt.Errorf("failed to compile regex: %w", err)
-			}
-
-			matches := re.FindStringSubmatch(text)
-			if len(matches) > 1 {
-				content := matches[1]
+			if content, ok := extractTag(text, alt); ok {
 				if strip {
 					content = strings.TrimSpace(content)
 				}
@@ -112,6 +102,38 @@ func (p *SmolaParser) ParseSmola(text string, strip bool) (*ParsedSmola, error)
 	return result, nil
 }
 
+// ParseSmolaAll returns the content of every occurrence of each field tag, keyed by
+// tag name and in the order they appear, so a message with several <tool> blocks
+// keeps all of them. The second map holds the decoded JSON of each tool block, with
+// nil for blocks that are not valid JSON objects, index-aligned with the contents.
+func (p *SmolaParser) ParseSmolaAll(text string) (map[string][]string, map[string][]interface{}) {
+	contents := make(map[string][]string)
+	toolJSON := make(map[string][]interface{})
+
+	for _, field := range p.fields {
+		for _, alt := range field.Alternatives {
+			matches := extractAllTags(text, alt)
+			if len(matches) == 0 {
+				continue
+			}
+			contents[alt] = matches
+
+			if alt == "tool" {
+				parsed := make([]interface{}, len(matches))
+				for i, content := range matches {
+					var toolData map[string]interface{}
+					if err := json.Unmarshal([]byte(content), &toolData); err == nil {
+						parsed[i] = toolData
+					}
+				}
+				toolJSON[alt] = parsed
+			}
+		}
+	}
+
+	return contents, toolJSON
+}
+
 // ParseWithTracking returns parsed content with metadata
 func (p *SmolaParser) ParseWithTracking(ctx context.Context, response string) (string, map[string]interface{}, error) {
 	parsed, err := p.ParseSmola(response, true)
@@ -159,63 +181,10 @@ func (p *SmolaParser) GetFormatStr() string {
 	return strings.Join(parts, "\n")
 }
 
-// Format creates an XML string from provided values
+// Format creates an XML string from provided values. Maps and slices, such as
+// tool calls, are encoded as JSON.
 func (p *SmolaParser) Format(values map[string]interface{}) (string, error) {
-	var parts []string
-	
-	for _, field := range p.fields {
-		var value string
-		found := false
-
-		// Check canonical name first
-		if val, ok := values[field.Canonical]; ok {
-			found = true
-			switch v := val.(type) {
-			case string:
-				value = v
-			case map[string]interface{}, []interface{}:
-				// For tool fields, marshal to JSON
-				jsonBytes, err := json.Marshal(v)
-				if err != nil {
-					return "", fmt.Errorf("failed to marshal %s to JSON: %w", field.Canonical, err)
-				}
-				value = string(jsonBytes)
-			default:
-				value = fmt.Sprintf("%v", v)
-			}
-		} else {
-			// Check alternatives
-			for _, alt := range field.Alternatives {
-				if val, ok := values[alt]; ok {
-					found = true
-					switch v := val.(type) {
-					case string:
-						value = v
-					case map[string]interface{}, []interface{}:
-						jsonBytes, err := json.Marshal(v)
-						if err != nil {
-							return "", fmt.Errorf("failed to marshal %s to JSON: %w", alt, err)
-						}
-						value = string(jsonBytes)
-					default:
-						value = fmt.Sprintf("%v", v)
-					}
-					break
-				}
-			}
-		}
-
-		if !found {
-			return "", fmt.Errorf("missing value for field '%s' (allowed: %v)", 
-				field.Canonical, field.Alternatives)
-		}
-
-		// Use canonical name for formatting
-		parts = append(parts, fmt.Sprintf("<%s>\n%s\n</%s>", 
-			field.Canonical, value, field.Canonical))
-	}
-
-	return strings.Join(parts, "\n"), nil
+	return formatFields(p.fields, values)
 }
 
 // GetFields returns the canonical field names in order
@@ -236,60 +205,5 @@ func (p *SmolaParser) FollowsFormat(text string) float64 {
 		return 0.0
 	}
 
-	score := 0.0
-	expectedFieldCount := len(p.fields)
-	presentFieldSets := make(map[int]bool)
-	hasCorrectSpacing := true
-
-	// Check which fields are present
-	for i, field := range p.fields {
-		fieldSetPresent := false
-		for _, alt := range field.Alternatives {
-			if val, ok := parsed.Fields[alt]; ok && val != "" {
-				fieldSetPresent = true
-				
-				// Check spacing
-				if valNoStrip, ok := parsedNoStrip.Fields[alt]; !ok || valNoStrip == "" {
-					hasCorrectSpacing = false
-				}
-			}
-		}
-		if fieldSetPresent {
-			presentFieldSets[i] = true
-		}
-	}
-
-	// Calculate score components
-	if len(presentFieldSets) > 0 {
-		fieldSetRatio := float64(len(presentFieldSets)) / float64(expectedFieldCount)
-		score += 0.4 * fieldSetRatio
-	}
-
-	if hasCorrectSpacing {
-		score += 0.2
-	}
-
-	// Check if starts with first field
-	trimmed := strings.TrimSpace(text)
-	if len(p.fields) > 0 {
-		for _, alt := range p.fields[0].Alternatives {
-			if strings.HasPrefix(trimmed, fmt.Sprintf("<%s>", alt)) {
-				score += 0.2
-				break
-			}
-		}
-	}
-
-	// Check if ends with last field
-	if len(p.fields) > 0 {
-		lastField := p.fields[len(p.fields)-1]
-		for _, alt := range lastField.Alternatives {
-			if strings.HasSuffix(trimmed, fmt.Sprintf("</%s>", alt)) {
-				score += 0.2
-				break
-			}
-		}
-	}
-
-	return score
-}
\ No newline at end of file
+	return fieldFormatScore(p.fields, text, parsed.Fields, parsedNoStrip.Fields)
+}
@@ -0,0 +1,45 @@
+package parsers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSmolaParser_ParseSmolaAll(t *testing.T) {
+	parser, err := NewSmolaParser([]interface{}{"think", []string{"tool", "answer"}})
+	if err != nil {
+		t.Fatalf("NewSmolaParser failed: %v", err)
+	}
+
+	text := "<think>\nplan\n</think>\n" +
+		"<tool>\n{\"name\": \"first\", \"args\": {}}\n</tool>\n" +
+		"<tool>not json</tool>\n" +
+		"<tool>{\"name\": \"second\", \"args\": {}}</tool>"
+
+	contents, toolJSON := parser.ParseSmolaAll(text)
+
+	wantTools := []string{`{"name": "first", "args": {}}`, "not json", `{"name": "second", "args": {}}`}
+	if !reflect.DeepEqual(contents["tool"], wantTools) {
+		t.Errorf("tool contents = %q, want %q", contents["tool"], wantTools)
+	}
+	if !reflect.DeepEqual(contents["think"], []string{"plan"}) {
+		t.Errorf("think contents = %q, want [plan]", contents["think"])
+	}
+	if _, ok := contents["answer"]; ok {
+		t.Errorf("Expected no answer entry, got %q", contents["answer"])
+	}
+
+	parsed := toolJSON["tool"]
+	if len(parsed) != 3 {
+		t.Fatalf("Expected 3 parsed tool entries, got %d", len(parsed))
+	}
+	if first, ok := parsed[0].(map[string]interface{}); !ok || first["name"] != "first" {
+		t.Errorf("Expected first tool call, got %v", parsed[0])
+	}
+	if parsed[1] != nil {
+		t.Errorf("Expected nil for invalid JSON, got %v", parsed[1])
+	}
+	if second, ok := parsed[2].(map[string]interface{}); !ok || second["name"] != "second" {
+		t.Errorf("Expected second tool call, got %v", parsed[2])
+	}
+}
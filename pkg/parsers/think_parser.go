@@ -29,12 +29,9 @@ func NewThinkParserWithExtractor(extractFn func(string) string) *ThinkParser {
 func (p *ThinkParser) Parse(ctx context.Context, response string) (string, error) {
 	text := response
 	
-	// If </think> exists, take everything after it
-	if strings.Contains(text, "</think>") {
-		parts := strings.Split(text, "</think>")
-		if len(parts) > 1 {
-			text = strings.TrimSpace(parts[len(parts)-1])
-		}
+	// If </think> exists, take everything after the last one
+	if idx := strings.LastIndex(text, "</think>"); idx != -1 {
+		text = text[idx+len("</think>"):]
 	}
 	
 	// Apply extraction function
@@ -58,8 +55,8 @@ func (p *ThinkParser) ParseWithTracking(ctx context.Context, response string) (s
 	return parsed, metadata, nil
 }
 
-// FollowsFormat checks if text follows the think format
-func (p *ThinkParser) FollowsFormat(text string) bool {
+// FollowsFormat returns 1.0 if text follows the think format, 0.0 otherwise
+func (p *ThinkParser) FollowsFormat(text string) float64 {
 	trimmed := strings.TrimSpace(text)
 	
 	// Check format requirements:
@@ -68,23 +65,23 @@ func (p *ThinkParser) FollowsFormat(text string) bool {
 	// 3. Exactly one </think> tag
 	// 4. Has content after </think>
 	if !strings.HasPrefix(trimmed, "<think>") {
-		return false
+		return 0.0
 	}
 	
 	if strings.Count(text, "<think>") != 1 {
-		return false
+		return 0.0
 	}
 	
 	if strings.Count(text, "</think>") != 1 {
-		return false
+		return 0.0
 	}
 	
 	parts := strings.Split(text, "</think>")
 	if len(parts) < 2 || len(strings.TrimSpace(parts[1])) == 0 {
-		return false
+		return 0.0
 	}
 	
-	return true
+	return 1.0
 }
 
 // GetFormatStr returns the expected format
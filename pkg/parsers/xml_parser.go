@@ -2,8 +2,9 @@ package parsers
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
-	"regexp"
+	"sort"
 	"strings"
 )
 
@@ -15,8 +16,10 @@ type XMLField struct {
 
 // XMLParser parses XML-formatted responses
 type XMLParser struct {
-	fields      []XMLField
-	answerField string
+	fields        []XMLField
+	answerField   string
+	allowUnclosed bool // Accept a final tag left open, e.g. by a stop sequence
+	preferLast    bool // Parse returns the last answer rather than the first
 }
 
 // ParsedXML represents the result of XML parsing
@@ -65,6 +68,10 @@ func NewXMLParser(fields []interface{}, answerField string) (*XMLParser, error)
 
 // Parse extracts XML fields from the response
 func (p *XMLParser) Parse(ctx context.Context, response string) (string, error) {
+	if p.preferLast {
+		return p.ParseLast(ctx, response)
+	}
+
 	parsed, err := p.ParseXML(response, true)
 	if err != nil {
 		return "", err
@@ -79,6 +86,33 @@ func (p *XMLParser) Parse(ctx context.Context, response string) (string, error)
 	return "", nil
 }
 
+// ParseLast returns the last occurrence of the answer field, for responses that
+// restate the answer, e.g. a draft followed by a corrected one. With
+// SetAllowUnclosed, a final unclosed answer tag counts as the last occurrence.
+// It returns an empty string if the answer field is missing.
+func (p *XMLParser) ParseLast(ctx context.Context, response string) (string, error) {
+	return p.lastAnswer(response), nil
+}
+
+// SetPreferLastAnswer controls whether Parse and ParseWithTracking return the last
+// occurrence of the answer field instead of the first, as ParseLast does
+func (p *XMLParser) SetPreferLastAnswer(preferLast bool) {
+	p.preferLast = preferLast
+}
+
+// lastAnswer returns the content of the last answer field tag in text
+func (p *XMLParser) lastAnswer(text string) string {
+	if p.allowUnclosed {
+		if content, ok := p.extractUnclosedLastTag(text, p.answerField); ok {
+			return content
+		}
+	}
+	if matches := extractAllTags(text, p.answerField); len(matches) > 0 {
+		return matches[len(matches)-1]
+	}
+	return ""
+}
+
 // ParseXML parses XML and returns structured data
 func (p *XMLParser) ParseXML(text string, strip bool) (*ParsedXML, error) {
 	result := &ParsedXML{
@@ -88,16 +122,11 @@ func (p *XMLParser) ParseXML(text string, strip bool) (*ParsedXML, error) {
 	for _, field := range p.fields {
 		// Check each alternative tag name
 		for _, alt := range field.Alternatives {
-			// Create regex pattern for the tag
-			pattern := fmt.Sprintf(`<%s>\s*(.*?)\s*</%s>`, alt, alt)
-			re, err := regexp.Compile("(?s)" + pattern) // (?s) makes . match newlines
-			if err != nil {
-				return nil, fmt.Errorf("failed to compile regex: %w", err)
+			content, ok := extractTag(text, alt)
+			if !ok && p.allowUnclosed {
+				content, ok = p.extractUnclosedLastTag(text, alt)
 			}
-
-			matches := re.FindStringSubmatch(text)
-			if len(matches) > 1 {
-				content := matches[1]
+			if ok {
 				if strip {
 					content = strings.TrimSpace(content)
 				}
@@ -109,6 +138,87 @@ func (p *XMLParser) ParseXML(text string, strip bool) (*ParsedXML, error) {
 	return result, nil
 }
 
+// SetAllowUnclosed controls whether ParseXML accepts a final field tag without its
+// closing tag, reading its content to the end of the text. This is needed when
+// generation stops at a stop sequence such as "</answer>", which the server leaves
+// out of the response. Only the last opened field tag can be unclosed.
+func (p *XMLParser) SetAllowUnclosed(allow bool) {
+	p.allowUnclosed = allow
+}
+
+// AllowsUnclosed reports whether ParseXML accepts an unclosed final field tag
+func (p *XMLParser) AllowsUnclosed() bool {
+	return p.allowUnclosed
+}
+
+// extractUnclosedLastTag returns the content after <tag> when it is the last field
+// tag opened in text and is never closed
+func (p *XMLParser) extractUnclosedLastTag(text, tag string) (string, bool) {
+	openTag := "<" + tag + ">"
+	start := strings.LastIndex(text, openTag)
+	if start == -1 {
+		return "", false
+	}
+	content := text[start+len(openTag):]
+	if strings.Contains(content, "</"+tag+">") {
+		return "", false
+	}
+
+	// Another field opened later means this tag is not the final one
+	for _, field := range p.fields {
+		for _, alt := range field.Alternatives {
+			if strings.Contains(content, "<"+alt+">") {
+				return "", false
+			}
+		}
+	}
+
+	return strings.Trim(content, tagSpace), true
+}
+
+// tagSpace is the whitespace trimmed around tag content, matching the \s regex class
+const tagSpace = " \t\n\f\r"
+
+// extractTag returns the content of the first <tag>...</tag> pair in text with
+// surrounding whitespace removed. It is equivalent to the regular expression
+// (?s)<tag>\s*(.*?)\s*</tag> but avoids compiling a pattern per call.
+func extractTag(text, tag string) (string, bool) {
+	openTag := "<" + tag + ">"
+	start := strings.Index(text, openTag)
+	if start == -1 {
+		return "", false
+	}
+	start += len(openTag)
+
+	end := strings.Index(text[start:], "</"+tag+">")
+	if end == -1 {
+		return "", false
+	}
+
+	return strings.Trim(text[start:start+end], tagSpace), true
+}
+
+// extractAllTags returns the trimmed content of every <tag>...</tag> pair in text, in order
+func extractAllTags(text, tag string) []string {
+	openTag, closeTag := "<"+tag+">", "</"+tag+">"
+	matches := make([]string, 0)
+
+	for {
+		start := strings.Index(text, openTag)
+		if start == -1 {
+			return matches
+		}
+		text = text[start+len(openTag):]
+
+		end := strings.Index(text, closeTag)
+		if end == -1 {
+			return matches
+		}
+		matches = append(matches, strings.Trim(text[:end], tagSpace))
+		text = text[end+len(closeTag):]
+	}
+}
+
 // ParseWithTracking returns parsed content with metadata
 func (p *XMLParser) ParseWithTracking(ctx context.Context, response string) (string, map[string]interface{}, error) {
 	parsed, err := p.ParseXML(response, true)
@@ -117,19 +227,84 @@ func (p *XMLParser) ParseWithTracking(ctx context.Context, response string) (str
 	}
 
 	answer := ""
-	if val, ok := parsed.Fields[p.answerField]; ok {
+	if p.preferLast {
+		answer = p.lastAnswer(response)
+	} else if val, ok := parsed.Fields[p.answerField]; ok {
 		answer = val
 	}
 
 	metadata := map[string]interface{}{
-		"parser_type":  "xml",
-		"fields_found": len(parsed.Fields),
-		"all_fields":   parsed.Fields,
+		"parser_type":      "xml",
+		"fields_found":     len(parsed.Fields),
+		"all_fields":       parsed.Fields,
+		"structure_errors": p.ValidateStructure(response),
 	}
 
 	return answer, metadata, nil
 }
 
+// ValidateStructure reports structural problems with the known field tags in text:
+// unclosed or unopened tags, fields that appear more than once and fields that
+// appear out of their declared order. It returns an empty list for well-formed text.
+// ParseXML stays lenient; use this to tell malformed output from a missing field.
+func (p *XMLParser) ValidateStructure(text string) []string {
+	problems := make([]string, 0)
+
+	// Opening tags of known fields in the order they appear
+	type occurrence struct {
+		pos   int
+		field int
+		tag   string
+	}
+	occurrences := make([]occurrence, 0)
+
+	for i, field := range p.fields {
+		count := 0
+		for _, alt := range field.Alternatives {
+			opens := tagPositions(text, "<"+alt+">")
+			closes := tagPositions(text, "</"+alt+">")
+			if len(opens) > len(closes) {
+				problems = append(problems, fmt.Sprintf("unclosed tag <%s>", alt))
+			} else if len(closes) > len(opens) {
+				problems = append(problems, fmt.Sprintf("closing tag </%s> without opening tag", alt))
+			}
+
+			count += len(opens)
+			for _, pos := range opens {
+				occurrences = append(occurrences, occurrence{pos: pos, field: i, tag: alt})
+			}
+		}
+		if count > 1 {
+			problems = append(problems, fmt.Sprintf("duplicate field <%s> appears %d times", field.Canonical, count))
+		}
+	}
+
+	sort.Slice(occurrences, func(i, j int) bool {
+		return occurrences[i].pos < occurrences[j].pos
+	})
+	for i := 1; i < len(occurrences); i++ {
+		prev, cur := occurrences[i-1], occurrences[i]
+		if cur.field < prev.field {
+			problems = append(problems, fmt.Sprintf("tag <%s> appears after <%s>, out of declared order", cur.tag, prev.tag))
+		}
+	}
+
+	return problems
+}
+
+// tagPositions returns the offsets of every occurrence of tag in text
+func tagPositions(text, tag string) []int {
+	positions := make([]int, 0)
+	for offset := 0; ; {
+		idx := strings.Index(text[offset:], tag)
+		if idx == -1 {
+			return positions
+		}
+		positions = append(positions, offset+idx)
+		offset += idx + len(tag)
+	}
+}
+
 // GetFormatStr returns a string describing the expected XML format
 func (p *XMLParser) GetFormatStr() string {
 	var parts []string
@@ -146,32 +321,51 @@ func (p *XMLParser) GetFormatStr() string {
 
 // Format creates an XML string from provided values
 func (p *XMLParser) Format(values map[string]string) (string, error) {
+	converted := make(map[string]interface{}, len(values))
+	for k, v := range values {
+		converted[k] = v
+	}
+	return p.FormatValues(converted)
+}
+
+// FormatValues creates an XML string from values of any type, like SmolaParser.Format.
+// Maps and slices are encoded as JSON and other values are formatted with %v.
+func (p *XMLParser) FormatValues(values map[string]interface{}) (string, error) {
+	return formatFields(p.fields, values)
+}
+
+// formatFields renders each field under its canonical tag, taking the value from the
+// canonical name or the first alternative present in values
+func formatFields(fields []XMLField, values map[string]interface{}) (string, error) {
 	var parts []string
 	
-	for _, field := range p.fields {
-		value := ""
-		found := false
+	for _, field := range fields {
+		var val interface{}
+		name := ""
 
 		// Check canonical name first
-		if val, ok := values[field.Canonical]; ok {
-			value = val
-			found = true
+		if v, ok := values[field.Canonical]; ok {
+			val, name = v, field.Canonical
 		} else {
 			// Check alternatives
 			for _, alt := range field.Alternatives {
-				if val, ok := values[alt]; ok {
-					value = val
-					found = true
+				if v, ok := values[alt]; ok {
+					val, name = v, alt
 					break
 				}
 			}
 		}
 
-		if !found {
+		if name == "" {
 			return "", fmt.Errorf("missing value for field '%s' (allowed: %v)", 
 				field.Canonical, field.Alternatives)
 		}
 
+		value, err := formatFieldValue(val)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal %s to JSON: %w", name, err)
+		}
+
 		// Use canonical name for formatting
 		parts = append(parts, fmt.Sprintf("<%s>\n%s\n</%s>", 
 			field.Canonical, value, field.Canonical))
@@ -180,6 +374,22 @@ func (p *XMLParser) Format(values map[string]string) (string, error) {
 	return strings.Join(parts, "\n"), nil
 }
 
+// formatFieldValue converts a field value to text, encoding maps and slices as JSON
+func formatFieldValue(val interface{}) (string, error) {
+	switch v := val.(type) {
+	case string:
+		return v, nil
+	case map[string]interface{}, []interface{}:
+		jsonBytes, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		return string(jsonBytes), nil
+	default:
+		return fmt.Sprintf("%v", v), nil
+	}
+}
+
 // GetFields returns the canonical field names in order
 func (p *XMLParser) GetFields() []string {
 	fields := make([]string, len(p.fields))
@@ -202,4 +412,77 @@ func (p *XMLParser) HasField(name string) bool {
 		}
 	}
 	return false
+}
+// FollowsFormat scores how well text follows the expected XML format
+func (p *XMLParser) FollowsFormat(text string) float64 {
+	parsed, _ := p.ParseXML(text, true)
+	parsedNoStrip, _ := p.ParseXML(text, false)
+
+	if parsed == nil || parsedNoStrip == nil {
+		return 0.0
+	}
+
+	return fieldFormatScore(p.fields, text, parsed.Fields, parsedNoStrip.Fields)
+}
+
+// fieldFormatScore scores text against a list of tagged fields: 0.4 for the share of
+// fields present, 0.2 for content surviving without stripping, 0.2 for starting with
+// the first field and 0.2 for ending with the last field
+func fieldFormatScore(fields []XMLField, text string, parsed, parsedNoStrip map[string]string) float64 {
+	score := 0.0
+	expectedFieldCount := len(fields)
+	presentFieldSets := make(map[int]bool)
+	hasCorrectSpacing := true
+
+	// Check which fields are present
+	for i, field := range fields {
+		fieldSetPresent := false
+		for _, alt := range field.Alternatives {
+			if val, ok := parsed[alt]; ok && val != "" {
+				fieldSetPresent = true
+				
+				// Check spacing
+				if valNoStrip, ok := parsedNoStrip[alt]; !ok || valNoStrip == "" {
+					hasCorrectSpacing = false
+				}
+			}
+		}
+		if fieldSetPresent {
+			presentFieldSets[i] = true
+		}
+	}
+
+	// Calculate score components
+	if len(presentFieldSets) > 0 {
+		fieldSetRatio := float64(len(presentFieldSets)) / float64(expectedFieldCount)
+		score += 0.4 * fieldSetRatio
+	}
+
+	if hasCorrectSpacing {
+		score += 0.2
+	}
+
+	// Check if starts with first field
+	trimmed := strings.TrimSpace(text)
+	if len(fields) > 0 {
+		for _, alt := range fields[0].Alternatives {
+			if strings.HasPrefix(trimmed, fmt.Sprintf("<%s>", alt)) {
+				score += 0.2
+				break
+			}
+		}
+	}
+
+	// Check if ends with last field
+	if len(fields) > 0 {
+		lastField := fields[len(fields)-1]
+		for _, alt := range lastField.Alternatives {
+			if strings.HasSuffix(trimmed, fmt.Sprintf("</%s>", alt)) {
+				score += 0.2
+				break
+			}
+		}
+	}
+
+	return score
 }
\ No newline at end of file
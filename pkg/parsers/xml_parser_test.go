@@ -2,6 +2,7 @@ package parsers
 
 import (
 	"context"
+	"reflect"
 	"testing"
 )
 
@@ -115,6 +116,45 @@ Let me solve this step by step.
 	}
 }
 
+func TestXMLParser_FormatValues(t *testing.T) {
+	parser, err := NewXMLParser([]interface{}{"think", "tool", "answer"}, "answer")
+	if err != nil {
+		t.Fatalf("Failed to create parser: %v", err)
+	}
+
+	values := map[string]interface{}{
+		"think": "I should use the calculator.",
+		"tool": map[string]interface{}{
+			"name": "calculate",
+			"args": map[string]interface{}{"expression": "6 * 7"},
+		},
+		"answer": 42,
+	}
+
+	formatted, err := parser.FormatValues(values)
+	if err != nil {
+		t.Fatalf("FormatValues() error = %v", err)
+	}
+
+	expected := `<think>
+I should use the calculator.
+</think>
+<tool>
+{"args":{"expression":"6 * 7"},"name":"calculate"}
+</tool>
+<answer>
+42
+</answer>`
+
+	if formatted != expected {
+		t.Errorf("FormatValues() = %v, want %v", formatted, expected)
+	}
+
+	if _, err := parser.FormatValues(map[string]interface{}{"think": "missing the rest"}); err == nil {
+		t.Error("FormatValues() expected error for missing fields")
+	}
+}
+
 func TestXMLParser_GetFormatStr(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -224,4 +264,186 @@ func TestXMLParser_Alternatives(t *testing.T) {
 			t.Errorf("Parse(%s) = %v, want %v", input.xml, got, input.expected)
 		}
 	}
-}
\ No newline at end of file
+}
+func TestXMLParser_ValidateStructure(t *testing.T) {
+	parser, err := NewXMLParser([]interface{}{"think", []string{"tool", "answer"}}, "answer")
+	if err != nil {
+		t.Fatalf("NewXMLParser failed: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{
+			name:  "well formed",
+			input: "<think>\nadd\n</think>\n<answer>\n42\n</answer>",
+			want:  []string{},
+		},
+		{
+			name:  "unclosed tag",
+			input: "<think>\nadd\n</think>\n<answer>42",
+			want:  []string{"unclosed tag <answer>"},
+		},
+		{
+			name:  "wrong order",
+			input: "<answer>\n42\n</answer>\n<think>\nadd\n</think>",
+			want:  []string{"tag <think> appears after <answer>, out of declared order"},
+		},
+		{
+			name:  "duplicate field",
+			input: "<think>\na\n</think>\n<think>\nb\n</think>\n<answer>\n42\n</answer>",
+			want:  []string{"duplicate field <think> appears 2 times"},
+		},
+		{
+			name:  "unopened tag",
+			input: "<think>\nadd\n</think>\n42</answer>",
+			want:  []string{"closing tag </answer> without opening tag"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parser.ValidateStructure(tt.input)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ValidateStructure() = %q, want %q", got, tt.want)
+			}
+
+			// The lenient parse is unchanged and the problems surface in the metadata
+			_, metadata, err := parser.ParseWithTracking(context.Background(), tt.input)
+			if err != nil {
+				t.Fatalf("ParseWithTracking failed: %v", err)
+			}
+			if !reflect.DeepEqual(metadata["structure_errors"], tt.want) {
+				t.Errorf("structure_errors = %v, want %q", metadata["structure_errors"], tt.want)
+			}
+		})
+	}
+}
+
+func TestXMLParser_AllowUnclosed(t *testing.T) {
+	parser, err := NewXMLParser([]interface{}{"think", "answer"}, "answer")
+	if err != nil {
+		t.Fatalf("NewXMLParser failed: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		allowUnclosed bool
+		input         string
+		want          map[string]string
+	}{
+		{
+			name:          "unclosed final tag accepted",
+			allowUnclosed: true,
+			input:         "<think>\nadd\n</think>\n<answer>\n42\n",
+			want:          map[string]string{"think": "add", "answer": "42"},
+		},
+		{
+			name:          "unclosed tag before another field rejected",
+			allowUnclosed: true,
+			input:         "<think>\nadd\n<answer>\n42\n</answer>",
+			want:          map[string]string{"answer": "42"},
+		},
+		{
+			name:          "strict by default",
+			allowUnclosed: false,
+			input:         "<think>\nadd\n</think>\n<answer>\n42\n",
+			want:          map[string]string{"think": "add"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser.SetAllowUnclosed(tt.allowUnclosed)
+			parsed, err := parser.ParseXML(tt.input, true)
+			if err != nil {
+				t.Fatalf("ParseXML failed: %v", err)
+			}
+			if !reflect.DeepEqual(parsed.Fields, tt.want) {
+				t.Errorf("ParseXML() fields = %q, want %q", parsed.Fields, tt.want)
+			}
+		})
+	}
+}
+
+func TestXMLParser_LastAnswer(t *testing.T) {
+	parser, err := NewXMLParser([]interface{}{"think", "answer"}, "answer")
+	if err != nil {
+		t.Fatalf("NewXMLParser failed: %v", err)
+	}
+	ctx := context.Background()
+
+	tests := []struct {
+		name          string
+		preferLast    bool
+		allowUnclosed bool
+		input         string
+		want          string
+	}{
+		{
+			name:  "first answer by default",
+			input: "<answer>\n41\n</answer>\n<think>\ncheck again\n</think>\n<answer>\n42\n</answer>",
+			want:  "41",
+		},
+		{
+			name:       "last answer when preferred",
+			preferLast: true,
+			input:      "<answer>\n41\n</answer>\n<think>\ncheck again\n</think>\n<answer>\n42\n</answer>",
+			want:       "42",
+		},
+		{
+			name:       "single answer",
+			preferLast: true,
+			input:      "<think>\nadd\n</think>\n<answer>\n42\n</answer>",
+			want:       "42",
+		},
+		{
+			name:          "unclosed final answer",
+			preferLast:    true,
+			allowUnclosed: true,
+			input:         "<answer>\n41\n</answer>\n<answer>\n42\n",
+			want:          "42",
+		},
+		{
+			name:       "missing answer",
+			preferLast: true,
+			input:      "<think>\nadd\n</think>",
+			want:       "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser.SetPreferLastAnswer(tt.preferLast)
+			parser.SetAllowUnclosed(tt.allowUnclosed)
+
+			got, err := parser.Parse(ctx, tt.input)
+			if err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Parse() = %q, want %q", got, tt.want)
+			}
+
+			tracked, _, err := parser.ParseWithTracking(ctx, tt.input)
+			if err != nil {
+				t.Fatalf("ParseWithTracking failed: %v", err)
+			}
+			if tracked != tt.want {
+				t.Errorf("ParseWithTracking() = %q, want %q", tracked, tt.want)
+			}
+
+			if tt.preferLast {
+				last, err := parser.ParseLast(ctx, tt.input)
+				if err != nil {
+					t.Fatalf("ParseLast failed: %v", err)
+				}
+				if last != tt.want {
+					t.Errorf("ParseLast() = %q, want %q", last, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -38,7 +38,7 @@ The code environment will execute your Python code and provide the output.`
 const DefaultToolPromptTemplate = `You are a helpful assistant with access to tools.
 
 Available tools:
-%s
+{{.tool_descriptions}}
 
 To use a tool, format your request as:
 <think>
@@ -60,7 +60,7 @@ After receiving the tool result, you can either call another tool or provide you
 const DefaultSmolaPromptTemplate = `You are a helpful assistant that uses tools to solve problems.
 
 You have access to the following tools:
-%s
+{{.tool_descriptions}}
 
 You must use the tools by outputting a specific XML format:
 <tool>
@@ -75,7 +75,7 @@ Always think step-by-step before using tools or providing answers.`
 const MathSmolaPromptTemplate = `You are a mathematical problem solver with access to tools.
 
 Available tools:
-%s
+{{.tool_descriptions}}
 
 For each problem:
 1. Analyze what needs to be calculated
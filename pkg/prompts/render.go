@@ -0,0 +1,43 @@
+package prompts
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// variablePattern matches simple variable references such as {{.tool_descriptions}}
+var variablePattern = regexp.MustCompile(`\{\{-?\s*\.(\w+)\s*-?\}\}`)
+
+// Render fills a prompt template written with Go template syntax, such as
+// "Available tools:\n{{.tool_descriptions}}", from vars. Every variable the
+// template references must be present in vars; missing ones are reported
+// together in the error. Unused vars are ignored.
+func Render(tmpl string, vars map[string]string) (string, error) {
+	missing := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, match := range variablePattern.FindAllStringSubmatch(tmpl, -1) {
+		name := match[1]
+		if _, ok := vars[name]; !ok && !seen[name] {
+			missing = append(missing, name)
+		}
+		seen[name] = true
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return "", fmt.Errorf("missing prompt variables: %s", strings.Join(missing, ", "))
+	}
+
+	parsed, err := template.New("prompt").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("invalid prompt template: %w", err)
+	}
+
+	var b strings.Builder
+	if err := parsed.Execute(&b, vars); err != nil {
+		return "", fmt.Errorf("failed to render prompt: %w", err)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,62 @@
+package prompts
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	tmpl := "Tools:\n{{.tool_descriptions}}\n\nFormat:\n{{ .format }}"
+
+	tests := []struct {
+		name     string
+		vars     map[string]string
+		expected string
+		wantErr  string
+	}{
+		{
+			name:     "all present",
+			vars:     map[string]string{"tool_descriptions": "calculate: Evaluate math", "format": "<answer>...</answer>", "unused": "x"},
+			expected: "Tools:\ncalculate: Evaluate math\n\nFormat:\n<answer>...</answer>",
+		},
+		{
+			name:    "missing variable",
+			vars:    map[string]string{"format": "<answer>...</answer>"},
+			wantErr: "missing prompt variables: tool_descriptions",
+		},
+		{
+			name:    "all missing",
+			vars:    nil,
+			wantErr: "missing prompt variables: format, tool_descriptions",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Render(tmpl, tt.vars)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Expected error %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Render failed: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestRender_LeavesPlainTextAlone(t *testing.T) {
+	// Values are inserted verbatim, without HTML escaping
+	result, err := Render("Use {{.tag}} tags. 100% sure.", map[string]string{"tag": "<answer>"})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if result != "Use <answer> tags. 100% sure." {
+		t.Errorf("Unexpected result %q", result)
+	}
+}
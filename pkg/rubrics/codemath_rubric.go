@@ -2,6 +2,7 @@ package rubrics
 
 import (
 	"context"
+	"fmt"
 	"strings"
 
 	"github.com/rizome-dev/go-verifiers/pkg/parsers"
@@ -35,11 +36,15 @@ func NewCodeMathRubric() (*CodeMathRubric, error) {
 
 	// Add code execution reward function
 	codeExecutionFunc := func(ctx context.Context, response, groundTruth string) (float64, error) {
+		if raw, ok := RawResponse(ctx); ok {
+			response = raw
+		}
 		return rubric.evaluateCodeExecution(response)
 	}
 
 	// Update metrics - replace format metric with code execution
 	rubric.metrics = make(map[string]types.RewardFunc)
+	rubric.metricNames = nil
 	rubric.rewardFuncs = nil
 	rubric.rewardWeights = nil
 
@@ -51,7 +56,10 @@ func NewCodeMathRubric() (*CodeMathRubric, error) {
 			parsed = parsedXML.Fields["answer"]
 		}
 
-		// Compare answers using math comparison
+		// Compare answers using math comparison, optionally ignoring units
+		if rubric.stripUnits {
+			parsed, groundTruth = utils.StripUnits(parsed), utils.StripUnits(groundTruth)
+		}
 		if utils.CompareMathAnswers(parsed, groundTruth) {
 			return 1.0, nil
 		}
@@ -132,31 +140,46 @@ func (r *CodeMathRubric) evaluateCodeExecution(response string) (float64, error)
 
 // ComputeRewardWithState computes reward with access to execution state
 func (r *CodeMathRubric) ComputeRewardWithState(ctx context.Context, parsed string, groundTruth string, state map[string]interface{}) (float64, error) {
-	// Get base score
-	baseScore, err := r.ComputeReward(ctx, parsed, groundTruth)
-	if err != nil {
-		return 0.0, err
-	}
+	score, _, err := r.ComputeRewardWithStateBreakdown(ctx, parsed, groundTruth, state)
+	return score, err
+}
+
+// ComputeRewardWithStateBreakdown is ComputeRewardWithState, also returning the raw
+// score of each metric. Every metric runs once, and recorded executions replace the
+// code_execution heuristic in the breakdown as well as in the reward.
+func (r *CodeMathRubric) ComputeRewardWithStateBreakdown(ctx context.Context, parsed string, groundTruth string, state map[string]interface{}) (float64, map[string]float64, error) {
+	score, breakdown, err := r.ComputeRewardWithBreakdown(ctx, parsed, groundTruth)
 
 	// If we have code execution history in state, use it for more accurate scoring
-	if executions, ok := state["code_executions"].([]map[string]interface{}); ok && len(executions) > 0 {
-		successCount := 0
-		totalCount := len(executions)
-
-		for _, exec := range executions {
-			if success, ok := exec["success"].(bool); ok && success {
-				successCount++
-			}
-		}
+	executionScore, ok := CodeExecutionScore(state)
+	if !ok || err != nil {
+		return score, breakdown, err
+	}
+	answerScore, ok := breakdown["correct_answer"]
+	if !ok {
+		return 0.0, nil, fmt.Errorf("metric correct_answer failed")
+	}
+	breakdown["code_execution"] = executionScore
+
+	// Replace code execution score with actual execution results
+	// Assuming weights: correct_answer=0.7, code_execution=0.3
+	return answerScore*0.7 + executionScore*0.3, breakdown, nil
+}
 
-		// Replace code execution score with actual execution results
-		executionScore := float64(successCount) / float64(totalCount)
-		
-		// Recalculate weighted score
-		// Assuming weights: correct_answer=0.7, code_execution=0.3
-		answerScore := baseScore / (0.7 + 0.3) * 0.7 // Extract answer component
-		return answerScore + executionScore*0.3, nil
+// CodeExecutionScore returns the fraction of successful executions in state["code_executions"].
+// The second return value is false when no executions were recorded.
+func CodeExecutionScore(state map[string]interface{}) (float64, bool) {
+	executions, ok := state["code_executions"].([]map[string]interface{})
+	if !ok || len(executions) == 0 {
+		return 0.0, false
+	}
+
+	successCount := 0
+	for _, exec := range executions {
+		if success, ok := exec["success"].(bool); ok && success {
+			successCount++
+		}
 	}
 
-	return baseScore, nil
+	return float64(successCount) / float64(len(executions)), true
 }
\ No newline at end of file
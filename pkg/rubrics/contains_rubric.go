@@ -0,0 +1,57 @@
+package rubrics
+
+import (
+	"context"
+	"strings"
+	"unicode"
+
+	"github.com/rizome-dev/go-verifiers/pkg/types"
+)
+
+// ContainsAnswerRubric scores 1.0 when the ground truth, or one of the aliases,
+// appears as a whole word or phrase anywhere in the parsed response
+type ContainsAnswerRubric struct {
+	*BaseRubric
+	aliases []string
+}
+
+// NewContainsAnswerRubric creates a rubric that accepts the ground truth or any of
+// the aliases. Matching ignores case and punctuation, so "The capital is Paris."
+// matches "Paris" while "Parisian" does not.
+func NewContainsAnswerRubric(aliases []string) *ContainsAnswerRubric {
+	rubric := &ContainsAnswerRubric{
+		BaseRubric: NewBaseRubric(),
+		aliases:    aliases,
+	}
+
+	// Replace the default exact match with a whole-phrase containment check
+	containsFunc := func(ctx context.Context, parsed, groundTruth string) (float64, error) {
+		return rubric.scoreContains(parsed, groundTruth), nil
+	}
+
+	rubric.rewardFuncs = []types.RewardFunc{containsFunc}
+	rubric.rewardWeights = []float64{1.0}
+
+	return rubric
+}
+
+// scoreContains checks each acceptable answer against the response
+func (r *ContainsAnswerRubric) scoreContains(parsed, groundTruth string) float64 {
+	response := " " + normalizeWords(parsed) + " "
+	for _, answer := range append([]string{groundTruth}, r.aliases...) {
+		phrase := normalizeWords(answer)
+		if phrase != "" && strings.Contains(response, " "+phrase+" ") {
+			return 1.0
+		}
+	}
+	return 0.0
+}
+
+// normalizeWords lowercases text and turns every run of punctuation and whitespace
+// into a single space, so phrases can be matched on word boundaries
+func normalizeWords(text string) string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(fields, " ")
+}
@@ -0,0 +1,38 @@
+package rubrics
+
+import (
+	"context"
+	"testing"
+)
+
+func TestContainsAnswerRubric_ComputeReward(t *testing.T) {
+	tests := []struct {
+		name        string
+		aliases     []string
+		parsed      string
+		groundTruth string
+		expected    float64
+	}{
+		{name: "answer in sentence", parsed: "The capital is Paris.", groundTruth: "Paris", expected: 1.0},
+		{name: "case and punctuation", parsed: "it's PARIS!", groundTruth: "paris.", expected: 1.0},
+		{name: "multi-word phrase", parsed: "That would be New York City, I think", groundTruth: "New York", expected: 1.0},
+		{name: "no partial word match", parsed: "The cuisine is Parisian.", groundTruth: "Paris", expected: 0.0},
+		{name: "missing answer", parsed: "The capital is Lyon.", groundTruth: "Paris", expected: 0.0},
+		{name: "alias", aliases: []string{"USA", "United States"}, parsed: "It is the United States.", groundTruth: "America", expected: 1.0},
+		{name: "empty ground truth", parsed: "anything", groundTruth: "...", expected: 0.0},
+	}
+
+	ctx := context.Background()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rubric := NewContainsAnswerRubric(tt.aliases)
+			got, err := rubric.ComputeReward(ctx, tt.parsed, tt.groundTruth)
+			if err != nil {
+				t.Fatalf("ComputeReward() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("ComputeReward(%q, %q) = %v, want %v", tt.parsed, tt.groundTruth, got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package rubrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/rizome-dev/go-verifiers/pkg/types"
+)
+
+// JudgeAggregation determines how verdicts from multiple judges are combined
+type JudgeAggregation string
+
+const (
+	// JudgeMajority returns 1.0 when more than half of the responding judges say yes
+	JudgeMajority JudgeAggregation = "majority"
+	// JudgeMean returns the average verdict across responding judges
+	JudgeMean JudgeAggregation = "mean"
+	// JudgeMin returns the lowest verdict, so every responding judge must agree
+	JudgeMin JudgeAggregation = "min"
+)
+
+// EnsembleJudgeRubric queries several judges and aggregates their verdicts
+type EnsembleJudgeRubric struct {
+	*BaseRubric
+	judges      []*JudgeRubric
+	aggregation JudgeAggregation
+}
+
+// NewEnsembleJudgeRubric creates a rubric that aggregates the given judges
+func NewEnsembleJudgeRubric(judges []*JudgeRubric, aggregation JudgeAggregation) *EnsembleJudgeRubric {
+	if aggregation == "" {
+		aggregation = JudgeMajority
+	}
+
+	rubric := &EnsembleJudgeRubric{
+		BaseRubric:  NewBaseRubric(),
+		judges:      judges,
+		aggregation: aggregation,
+	}
+
+	// Replace the default exact match with the ensemble verdict
+	ensembleFunc := func(ctx context.Context, parsed, groundTruth string) (float64, error) {
+		return rubric.ComputeReward(ctx, parsed, groundTruth)
+	}
+
+	rubric.rewardFuncs = []types.RewardFunc{ensembleFunc}
+	rubric.rewardWeights = []float64{1.0}
+
+	return rubric
+}
+
+// NewEnsembleJudgeRubricFromModels creates an ensemble with one judge per model on a shared client
+func NewEnsembleJudgeRubricFromModels(judgeClient types.Client, judgeModels []string, aggregation JudgeAggregation) *EnsembleJudgeRubric {
+	judges := make([]*JudgeRubric, 0, len(judgeModels))
+	for _, model := range judgeModels {
+		judges = append(judges, NewJudgeRubric(judgeClient, model))
+	}
+	return NewEnsembleJudgeRubric(judges, aggregation)
+}
+
+// SetSystemPrompt updates the system prompt of every judge in the ensemble
+func (r *EnsembleJudgeRubric) SetSystemPrompt(prompt string) {
+	for _, judge := range r.judges {
+		judge.SetSystemPrompt(prompt)
+	}
+}
+
+// ComputeReward fans out to all judges and combines their verdicts.
+// Failing judges are ignored; an error is returned only if every judge fails.
+func (r *EnsembleJudgeRubric) ComputeReward(ctx context.Context, parsed string, groundTruth string) (float64, error) {
+	scores, errs := r.collectVerdicts(ctx, parsed, groundTruth)
+
+	verdicts := make([]float64, 0, len(scores))
+	for i, score := range scores {
+		if errs[i] == nil {
+			verdicts = append(verdicts, score)
+		}
+	}
+
+	if len(verdicts) == 0 {
+		return 0.0, allJudgesFailed(errs)
+	}
+
+	return r.aggregate(verdicts), nil
+}
+
+// GetIndividualScores returns each judge's verdict in judge order.
+// Judges that failed are reported as NaN; an error is returned only if every judge fails.
+func (r *EnsembleJudgeRubric) GetIndividualScores(ctx context.Context, response string, groundTruth string) ([]float64, error) {
+	scores, errs := r.collectVerdicts(ctx, response, groundTruth)
+
+	failed := 0
+	for i, err := range errs {
+		if err != nil {
+			scores[i] = math.NaN()
+			failed++
+		}
+	}
+
+	if failed == len(errs) {
+		return scores, allJudgesFailed(errs)
+	}
+
+	return scores, nil
+}
+
+// collectVerdicts queries all judges concurrently
+func (r *EnsembleJudgeRubric) collectVerdicts(ctx context.Context, response, groundTruth string) ([]float64, []error) {
+	scores := make([]float64, len(r.judges))
+	errs := make([]error, len(r.judges))
+
+	var wg sync.WaitGroup
+	for i, judge := range r.judges {
+		wg.Add(1)
+		go func(index int, judge *JudgeRubric) {
+			defer wg.Done()
+			scores[index], errs[index] = judge.judge(ctx, response, groundTruth)
+		}(i, judge)
+	}
+	wg.Wait()
+
+	return scores, errs
+}
+
+// aggregate combines successful verdicts according to the configured strategy
+func (r *EnsembleJudgeRubric) aggregate(verdicts []float64) float64 {
+	switch r.aggregation {
+	case JudgeMean:
+		total := 0.0
+		for _, v := range verdicts {
+			total += v
+		}
+		return total / float64(len(verdicts))
+	case JudgeMin:
+		minVal := verdicts[0]
+		for _, v := range verdicts[1:] {
+			if v < minVal {
+				minVal = v
+			}
+		}
+		return minVal
+	default:
+		// Majority vote; ties count as incorrect
+		yes := 0
+		for _, v := range verdicts {
+			if v >= 0.5 {
+				yes++
+			}
+		}
+		if yes*2 > len(verdicts) {
+			return 1.0
+		}
+		return 0.0
+	}
+}
+
+// allJudgesFailed builds the error returned when no judge produced a verdict
+func allJudgesFailed(errs []error) error {
+	if len(errs) == 0 {
+		return fmt.Errorf("ensemble has no judges")
+	}
+	return fmt.Errorf("all %d judges failed: %w", len(errs), errors.Join(errs...))
+}
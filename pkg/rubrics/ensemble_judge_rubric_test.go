@@ -0,0 +1,107 @@
+package rubrics
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/rizome-dev/go-verifiers/pkg/types"
+)
+
+// verdictJudge answers every judge request with a fixed verdict or error
+type verdictJudge struct {
+	verdict string
+	err     error
+}
+
+func (j *verdictJudge) CreateChatCompletion(ctx context.Context, model string, messages []types.Message, args types.SamplingArgs) (string, error) {
+	return j.verdict, j.err
+}
+
+func (j *verdictJudge) CreateCompletion(ctx context.Context, model string, prompt string, args types.SamplingArgs) (string, error) {
+	return j.verdict, j.err
+}
+
+// newVerdictEnsemble builds an ensemble with one stub judge per verdict; "error"
+// makes that judge fail
+func newVerdictEnsemble(verdicts []string, aggregation JudgeAggregation) *EnsembleJudgeRubric {
+	judges := make([]*JudgeRubric, 0, len(verdicts))
+	for _, verdict := range verdicts {
+		client := &verdictJudge{verdict: verdict}
+		if verdict == "error" {
+			client.err = errors.New("judge unavailable")
+		}
+		judges = append(judges, NewJudgeRubric(client, "judge-model"))
+	}
+	return NewEnsembleJudgeRubric(judges, aggregation)
+}
+
+func TestEnsembleJudgeRubric_Aggregation(t *testing.T) {
+	tests := []struct {
+		name        string
+		verdicts    []string
+		aggregation JudgeAggregation
+		want        float64
+	}{
+		{name: "majority yes", verdicts: []string{"Yes", "Yes", "No"}, aggregation: JudgeMajority, want: 1.0},
+		{name: "majority no", verdicts: []string{"Yes", "No", "No"}, aggregation: JudgeMajority, want: 0.0},
+		{name: "majority tie counts as incorrect", verdicts: []string{"Yes", "No"}, aggregation: JudgeMajority, want: 0.0},
+		{name: "default is majority", verdicts: []string{"Yes", "Yes", "No"}, want: 1.0},
+		{name: "mean", verdicts: []string{"Yes", "Yes", "No"}, aggregation: JudgeMean, want: 2.0 / 3.0},
+		{name: "min", verdicts: []string{"Yes", "Yes", "No"}, aggregation: JudgeMin, want: 0.0},
+		{name: "min all agree", verdicts: []string{"Yes", "Yes"}, aggregation: JudgeMin, want: 1.0},
+		{name: "partial failure is ignored", verdicts: []string{"Yes", "error", "Yes"}, aggregation: JudgeMin, want: 1.0},
+		{name: "partial failure mean", verdicts: []string{"Yes", "error", "No"}, aggregation: JudgeMean, want: 0.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rubric := newVerdictEnsemble(tt.verdicts, tt.aggregation)
+			score, err := rubric.ComputeReward(context.Background(), "4", "4")
+			if err != nil {
+				t.Fatalf("ComputeReward failed: %v", err)
+			}
+			if math.Abs(score-tt.want) > 1e-9 {
+				t.Errorf("ComputeReward() = %v, want %v", score, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnsembleJudgeRubric_AllJudgesFail(t *testing.T) {
+	rubric := newVerdictEnsemble([]string{"error", "error"}, JudgeMean)
+
+	if _, err := rubric.ComputeReward(context.Background(), "4", "4"); err == nil || !strings.Contains(err.Error(), "all 2 judges failed") {
+		t.Errorf("Expected an error when every judge fails, got %v", err)
+	}
+
+	scores, err := rubric.GetIndividualScores(context.Background(), "4", "4")
+	if err == nil {
+		t.Errorf("Expected GetIndividualScores to fail when every judge fails")
+	}
+	if len(scores) != 2 || !math.IsNaN(scores[0]) || !math.IsNaN(scores[1]) {
+		t.Errorf("Expected NaN for every failed judge, got %v", scores)
+	}
+
+	empty := NewEnsembleJudgeRubric(nil, JudgeMajority)
+	if _, err := empty.ComputeReward(context.Background(), "4", "4"); err == nil {
+		t.Errorf("Expected an error for an ensemble without judges")
+	}
+}
+
+func TestEnsembleJudgeRubric_GetIndividualScores(t *testing.T) {
+	rubric := newVerdictEnsemble([]string{"Yes", "error", "No"}, JudgeMajority)
+
+	scores, err := rubric.GetIndividualScores(context.Background(), "4", "4")
+	if err != nil {
+		t.Fatalf("GetIndividualScores failed: %v", err)
+	}
+	if len(scores) != 3 {
+		t.Fatalf("Expected a score per judge, got %v", scores)
+	}
+	if scores[0] != 1.0 || !math.IsNaN(scores[1]) || scores[2] != 0.0 {
+		t.Errorf("GetIndividualScores() = %v, want [1 NaN 0] in judge order", scores)
+	}
+}
@@ -0,0 +1,101 @@
+package rubrics
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rizome-dev/go-verifiers/pkg/parsers"
+	"github.com/rizome-dev/go-verifiers/pkg/types"
+)
+
+// GatedRubric only awards correctness to the extent that the response follows the
+// expected format. The reward is
+//
+//	formatWeight*format + (1-formatWeight)*correctness*format
+//
+// so a correct but malformed response scores close to zero.
+type GatedRubric struct {
+	*BaseRubric
+	formatParser    parsers.Parser
+	correctnessFunc types.RewardFunc
+	formatWeight    float64
+}
+
+// NewGatedRubric creates a rubric that scales correctnessFunc by the parser's
+// FollowsFormat score. formatWeight is clamped to [0, 1].
+func NewGatedRubric(formatParser parsers.Parser, correctnessFunc types.RewardFunc, formatWeight float64) *GatedRubric {
+	if formatWeight < 0 {
+		formatWeight = 0
+	}
+	if formatWeight > 1 {
+		formatWeight = 1
+	}
+
+	rubric := &GatedRubric{
+		BaseRubric:      NewBaseRubric(),
+		formatParser:    formatParser,
+		correctnessFunc: correctnessFunc,
+		formatWeight:    formatWeight,
+	}
+
+	// Replace the default exact match with the gated reward
+	gatedFunc := func(ctx context.Context, parsed, groundTruth string) (float64, error) {
+		format, correctness, err := rubric.scores(ctx, parsed, groundTruth)
+		if err != nil {
+			return 0.0, err
+		}
+		return rubric.gate(format, correctness), nil
+	}
+
+	rubric.rewardFuncs = []types.RewardFunc{gatedFunc}
+	rubric.rewardWeights = []float64{1.0}
+
+	return rubric
+}
+
+// gate combines the format and correctness scores into the reward
+func (r *GatedRubric) gate(format, correctness float64) float64 {
+	return r.formatWeight*format + (1-r.formatWeight)*correctness*format
+}
+
+// ComputeRewardWithBreakdown computes the gated reward and the ungated format and
+// correctness scores from a single evaluation of each
+func (r *GatedRubric) ComputeRewardWithBreakdown(ctx context.Context, parsed string, groundTruth string) (float64, map[string]float64, error) {
+	format, correctness, err := r.scores(ctx, parsed, groundTruth)
+	if err != nil {
+		return 0.0, nil, err
+	}
+	breakdown := map[string]float64{
+		"format":      format,
+		"correctness": correctness,
+	}
+	return r.gate(format, correctness), breakdown, nil
+}
+
+// ComputeRewardBreakdown reports the ungated format and correctness scores
+func (r *GatedRubric) ComputeRewardBreakdown(ctx context.Context, parsed string, groundTruth string) (map[string]float64, error) {
+	format, correctness, err := r.scores(ctx, parsed, groundTruth)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]float64{
+		"format":      format,
+		"correctness": correctness,
+	}, nil
+}
+
+// scores computes the format score, preferring the unparsed response attached with
+// WithRawResponse, and the correctness score of the parsed response
+func (r *GatedRubric) scores(ctx context.Context, parsed, groundTruth string) (float64, float64, error) {
+	response := parsed
+	if raw, ok := RawResponse(ctx); ok {
+		response = raw
+	}
+	format := r.formatParser.FollowsFormat(response)
+
+	correctness, err := r.correctnessFunc(ctx, parsed, groundTruth)
+	if err != nil {
+		return 0.0, 0.0, fmt.Errorf("correctness: %w", err)
+	}
+	return format, correctness, nil
+}
@@ -0,0 +1,67 @@
+package rubrics
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/rizome-dev/go-verifiers/pkg/parsers"
+)
+
+func TestGatedRubric_ComputeReward(t *testing.T) {
+	parser, err := parsers.NewXMLParser([]interface{}{"think", "answer"}, "answer")
+	if err != nil {
+		t.Fatalf("Failed to create parser: %v", err)
+	}
+	exactMatch := func(ctx context.Context, parsed, groundTruth string) (float64, error) {
+		if strings.TrimSpace(parsed) == groundTruth {
+			return 1.0, nil
+		}
+		return 0.0, nil
+	}
+	rubric := NewGatedRubric(parser, exactMatch, 0.2)
+
+	tests := []struct {
+		name     string
+		raw      string
+		parsed   string
+		minScore float64
+		maxScore float64
+	}{
+		{
+			name:     "well-formatted and correct",
+			raw:      "<think>\n6 times 7\n</think>\n<answer>\n42\n</answer>",
+			parsed:   "42",
+			minScore: 0.9,
+			maxScore: 1.0,
+		},
+		{
+			name:     "well-formatted but wrong",
+			raw:      "<think>\n6 times 7\n</think>\n<answer>\n41\n</answer>",
+			parsed:   "41",
+			minScore: 0.15,
+			maxScore: 0.2,
+		},
+		{
+			// FollowsFormat gives an untagged response only its 0.2 spacing credit
+			name:     "malformed but correct",
+			raw:      "42",
+			parsed:   "42",
+			minScore: 0.0,
+			maxScore: 0.2 + 1e-9,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := WithRawResponse(context.Background(), tt.raw)
+			score, err := rubric.ComputeReward(ctx, tt.parsed, "42")
+			if err != nil {
+				t.Fatalf("ComputeReward failed: %v", err)
+			}
+			if score < tt.minScore || score > tt.maxScore {
+				t.Errorf("ComputeReward() = %v, want between %v and %v", score, tt.minScore, tt.maxScore)
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/rizome-dev/go-verifiers/pkg/parsers"
 	"github.com/rizome-dev/go-verifiers/pkg/types"
 )
 
@@ -14,6 +15,9 @@ type JudgeRubric struct {
 	judgeClient types.Client
 	judgeModel  string
 	systemPrompt string
+
+	answerParser     parsers.Parser
+	includeReasoning bool
 }
 
 // NewJudgeRubric creates a new LLM-based judge rubric
@@ -45,6 +49,43 @@ func (r *JudgeRubric) SetSystemPrompt(prompt string) {
 	r.systemPrompt = prompt
 }
 
+// SetAnswerParser makes the judge evaluate only the answer that parser extracts
+// from the full response, attached with WithRawResponse when available, so long
+// reasoning does not distract it. Responses without an answer are judged whole.
+// nil restores judging the whole response.
+func (r *JudgeRubric) SetAnswerParser(parser parsers.Parser) {
+	r.answerParser = parser
+}
+
+// SetIncludeReasoning controls whether the full response is shown to the judge as
+// context alongside the extracted answer. It only applies with an answer parser.
+func (r *JudgeRubric) SetIncludeReasoning(include bool) {
+	r.includeReasoning = include
+}
+
+// responseSection renders the part of the judge prompt that shows the model's
+// output: the whole response, or the parsed answer and optionally the response
+func (r *JudgeRubric) responseSection(ctx context.Context, modelResponse string) string {
+	if r.answerParser == nil {
+		return "Model Response: " + modelResponse
+	}
+
+	response := modelResponse
+	if raw, ok := RawResponse(ctx); ok {
+		response = raw
+	}
+	answer, err := r.answerParser.Parse(ctx, response)
+	if err != nil || strings.TrimSpace(answer) == "" {
+		return "Model Response: " + response
+	}
+
+	section := "Model Answer: " + strings.TrimSpace(answer)
+	if r.includeReasoning {
+		section += "\n\nFull Model Response (for context only; judge the answer above): " + response
+	}
+	return section
+}
+
 // judge uses the LLM to evaluate correctness
 func (r *JudgeRubric) judge(ctx context.Context, modelResponse, groundTruth string) (float64, error) {
 	// Format the judge prompt
@@ -52,9 +93,9 @@ func (r *JudgeRubric) judge(ctx context.Context, modelResponse, groundTruth stri
 
 Ground Truth Answer: %s
 
-Model Response: %s
+%s
 
-Is the model's response correct? Reply with only "Yes" or "No".`, groundTruth, modelResponse)
+Is the model's response correct? Reply with only "Yes" or "No".`, groundTruth, r.responseSection(ctx, modelResponse))
 
 	// Create messages for the judge
 	messages := []types.Message{
@@ -99,7 +140,7 @@ func (r *JudgeRubric) JudgeWithReasoning(ctx context.Context, modelResponse, gro
 
 Ground Truth Answer: %s
 
-Model Response: %s
+%s
 
 Provide your evaluation in the following format:
 <reasoning>
@@ -107,7 +148,7 @@ Explain why the response is correct or incorrect
 </reasoning>
 <judgment>
 Yes or No
-</judgment>`, groundTruth, modelResponse)
+</judgment>`, groundTruth, r.responseSection(ctx, modelResponse))
 
 	// Create messages for the judge
 	messages := []types.Message{
@@ -0,0 +1,74 @@
+package rubrics
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/rizome-dev/go-verifiers/pkg/parsers"
+	"github.com/rizome-dev/go-verifiers/pkg/types"
+)
+
+// recordingJudge answers "Yes" and records the last prompt it was asked
+type recordingJudge struct {
+	prompt string
+}
+
+func (j *recordingJudge) CreateChatCompletion(ctx context.Context, model string, messages []types.Message, args types.SamplingArgs) (string, error) {
+	j.prompt = messages[len(messages)-1].Content
+	return "Yes", nil
+}
+
+func (j *recordingJudge) CreateCompletion(ctx context.Context, model string, prompt string, args types.SamplingArgs) (string, error) {
+	return "Yes", nil
+}
+
+func TestJudgeRubric_AnswerParser(t *testing.T) {
+	parser, err := parsers.NewXMLParser([]interface{}{"think", "answer"}, "answer")
+	if err != nil {
+		t.Fatalf("NewXMLParser failed: %v", err)
+	}
+	reasoning := "First I considered 5, then 3, and after checking the sum twice I am confident."
+	response := "<think>\n" + reasoning + "\n</think>\n<answer>\n4\n</answer>"
+
+	tests := []struct {
+		name             string
+		parser           parsers.Parser
+		includeReasoning bool
+		contains         []string
+		excludes         []string
+	}{
+		{name: "whole response", contains: []string{"Model Response: " + response}},
+		{name: "parsed answer", parser: parser, contains: []string{"Model Answer: 4"}, excludes: []string{reasoning}},
+		{name: "parsed answer with reasoning", parser: parser, includeReasoning: true, contains: []string{"Model Answer: 4", reasoning}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			judge := &recordingJudge{}
+			rubric := NewJudgeRubric(judge, "judge-model")
+			rubric.SetAnswerParser(tt.parser)
+			rubric.SetIncludeReasoning(tt.includeReasoning)
+
+			// Environments attach the raw response for rubrics
+			ctx := WithRawResponse(context.Background(), response)
+			score, err := rubric.ComputeReward(ctx, response, "4")
+			if err != nil {
+				t.Fatalf("ComputeReward failed: %v", err)
+			}
+			if score != 1.0 {
+				t.Errorf("Expected score 1.0, got %v", score)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(judge.prompt, want) {
+					t.Errorf("Expected judge prompt to contain %q, got %q", want, judge.prompt)
+				}
+			}
+			for _, unwanted := range tt.excludes {
+				if strings.Contains(judge.prompt, unwanted) {
+					t.Errorf("Expected judge prompt without %q, got %q", unwanted, judge.prompt)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package rubrics
+
+import (
+	"context"
+	"unicode/utf8"
+
+	"github.com/rizome-dev/go-verifiers/pkg/types"
+)
+
+// LengthPenalty creates a reward function that returns 1.0 for responses of up to
+// softChars characters, decays linearly to 0.0 at hardChars, and returns 0.0 beyond.
+// It measures the raw model response when the environment provides one via
+// WithRawResponse, and the string it is given otherwise. If hardChars is not greater
+// than softChars, any response longer than softChars scores 0.0.
+func LengthPenalty(softChars, hardChars int) types.RewardFunc {
+	return func(ctx context.Context, parsed, groundTruth string) (float64, error) {
+		response, ok := RawResponse(ctx)
+		if !ok {
+			response = parsed
+		}
+		return lengthScore(utf8.RuneCountInString(response), softChars, hardChars), nil
+	}
+}
+
+// lengthScore maps a length onto the soft/hard limit schedule
+func lengthScore(length, softChars, hardChars int) float64 {
+	if length <= softChars {
+		return 1.0
+	}
+	if length >= hardChars {
+		return 0.0
+	}
+	return 1.0 - float64(length-softChars)/float64(hardChars-softChars)
+}
+
+// LengthRubric penalizes responses that exceed a character budget
+type LengthRubric struct {
+	*BaseRubric
+}
+
+// NewLengthRubric creates a rubric scoring 1.0 up to softChars, decaying linearly to 0.0 at hardChars.
+// To combine it with other metrics, add LengthPenalty to a MultiMetricRubric with a small weight.
+func NewLengthRubric(softChars, hardChars int) *LengthRubric {
+	rubric := &LengthRubric{
+		BaseRubric: NewBaseRubric(),
+	}
+
+	// Replace the default exact match with the length penalty
+	rubric.rewardFuncs = []types.RewardFunc{LengthPenalty(softChars, hardChars)}
+	rubric.rewardWeights = []float64{1.0}
+
+	return rubric
+}
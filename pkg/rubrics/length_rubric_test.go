@@ -0,0 +1,53 @@
+package rubrics
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestLengthRubric_ComputeReward(t *testing.T) {
+	tests := []struct {
+		name     string
+		length   int
+		expected float64
+	}{
+		{name: "below soft limit", length: 50, expected: 1.0},
+		{name: "at soft limit", length: 100, expected: 1.0},
+		{name: "between limits", length: 150, expected: 0.5},
+		{name: "at hard limit", length: 200, expected: 0.0},
+		{name: "above hard limit", length: 500, expected: 0.0},
+	}
+
+	rubric := NewLengthRubric(100, 200)
+	ctx := context.Background()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := rubric.ComputeReward(ctx, strings.Repeat("a", tt.length), "")
+			if err != nil {
+				t.Fatalf("ComputeReward() error = %v", err)
+			}
+			if math.Abs(got-tt.expected) > 1e-9 {
+				t.Errorf("ComputeReward() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLengthPenalty_UsesRawResponse(t *testing.T) {
+	rubric := NewMultiMetricRubric()
+	rubric.AddMetric("length", LengthPenalty(10, 20), 0.1)
+
+	fn, _ := rubric.GetMetric("length")
+
+	// The parsed answer is short but the raw response rambles
+	ctx := WithRawResponse(context.Background(), strings.Repeat("thinking ", 10)+"42")
+	got, err := fn(ctx, "42", "42")
+	if err != nil {
+		t.Fatalf("LengthPenalty error = %v", err)
+	}
+	if got != 0.0 {
+		t.Errorf("Expected raw response to be penalized, got %v", got)
+	}
+}
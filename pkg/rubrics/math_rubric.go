@@ -10,7 +10,8 @@ import (
 // MathRubric evaluates mathematical responses
 type MathRubric struct {
 	*MultiMetricRubric
-	parser *parsers.XMLParser
+	parser     *parsers.XMLParser
+	stripUnits bool
 }
 
 // NewMathRubric creates a new math rubric
@@ -34,7 +35,10 @@ func NewMathRubric() (*MathRubric, error) {
 			parsed = parsedXML.Fields["answer"]
 		}
 
-		// Compare answers using math comparison
+		// Compare answers using math comparison, optionally ignoring units
+		if rubric.stripUnits {
+			parsed, groundTruth = utils.StripUnits(parsed), utils.StripUnits(groundTruth)
+		}
 		if utils.CompareMathAnswers(parsed, groundTruth) {
 			return 1.0, nil
 		}
@@ -76,6 +80,13 @@ func NewMathRubric() (*MathRubric, error) {
 	return rubric, nil
 }
 
+// SetStripUnits controls whether currency symbols and a trailing unit word are
+// removed from both answers before comparison, so "42 meters" matches "42".
+// Leave it off for tasks where the unit is part of the answer.
+func (r *MathRubric) SetStripUnits(strip bool) {
+	r.stripUnits = strip
+}
+
 // GetParser returns the XML parser used by this rubric
 func (r *MathRubric) GetParser() *parsers.XMLParser {
 	return r.parser
@@ -87,4 +98,28 @@ func (r *MathRubric) ComputeReward(ctx context.Context, parsed string, groundTru
 	groundTruth = utils.ExtractBoxedAnswer(groundTruth)
 	
 	return r.MultiMetricRubric.ComputeReward(ctx, parsed, groundTruth)
+}
+
+// ComputeRewardDetail reports whether the response has an <answer>, whether that
+// answer is correct, and the format score. A bare response without tags counts as
+// no answer here, even though correct_answer still compares it.
+func (r *MathRubric) ComputeRewardDetail(ctx context.Context, parsed string, groundTruth string) (RewardDetail, error) {
+	groundTruth = utils.ExtractBoxedAnswer(groundTruth)
+
+	return r.rewardDetail(ctx, parsed, groundTruth, hasAnswerField(r.parser, parsed))
+}
+
+// ComputeRewardWithBreakdown computes the reward and per-metric scores for math
+// problems in one pass
+func (r *MathRubric) ComputeRewardWithBreakdown(ctx context.Context, parsed string, groundTruth string) (float64, map[string]float64, error) {
+	groundTruth = utils.ExtractBoxedAnswer(groundTruth)
+
+	return r.MultiMetricRubric.ComputeRewardWithBreakdown(ctx, parsed, groundTruth)
+}
+
+// ComputeRewardBreakdown returns per-metric scores for math problems
+func (r *MathRubric) ComputeRewardBreakdown(ctx context.Context, parsed string, groundTruth string) (map[string]float64, error) {
+	groundTruth = utils.ExtractBoxedAnswer(groundTruth)
+
+	return r.MultiMetricRubric.ComputeRewardBreakdown(ctx, parsed, groundTruth)
 }
\ No newline at end of file
@@ -0,0 +1,101 @@
+package rubrics
+
+import (
+	"context"
+	"testing"
+)
+
+func TestMathRubric_StripUnits(t *testing.T) {
+	tests := []struct {
+		name        string
+		stripUnits  bool
+		answer      string
+		groundTruth string
+		expected    float64
+	}{
+		{name: "thousands separator", stripUnits: true, answer: "12000", groundTruth: "12,000", expected: 1.0},
+		{name: "trailing unit", stripUnits: true, answer: "42", groundTruth: "42 meters", expected: 1.0},
+		{name: "unit on answer", stripUnits: true, answer: "42 m", groundTruth: "42", expected: 1.0},
+		{name: "currency", stripUnits: true, answer: "3.5", groundTruth: "$3.50", expected: 1.0},
+		{name: "different number with unit", stripUnits: true, answer: "41", groundTruth: "42 meters", expected: 0.0},
+		{name: "units kept when disabled", stripUnits: false, answer: "42", groundTruth: "42 meters", expected: 0.0},
+	}
+
+	ctx := context.Background()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rubric, err := NewMathRubric()
+			if err != nil {
+				t.Fatalf("NewMathRubric failed: %v", err)
+			}
+			rubric.SetStripUnits(tt.stripUnits)
+
+			breakdown, err := rubric.ComputeRewardBreakdown(ctx, "<think>\nwork\n</think>\n<answer>\n"+tt.answer+"\n</answer>", tt.groundTruth)
+			if err != nil {
+				t.Fatalf("ComputeRewardBreakdown() error = %v", err)
+			}
+			if got := breakdown["correct_answer"]; got != tt.expected {
+				t.Errorf("correct_answer(%q, %q) = %v, want %v", tt.answer, tt.groundTruth, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCodeMathRubric_StripUnits(t *testing.T) {
+	rubric, err := NewCodeMathRubric()
+	if err != nil {
+		t.Fatalf("NewCodeMathRubric failed: %v", err)
+	}
+	ctx := context.Background()
+	response := "<reasoning>\nwork\n</reasoning>\n<answer>\n42\n</answer>"
+
+	breakdown, err := rubric.ComputeRewardBreakdown(ctx, response, "42 meters")
+	if err != nil {
+		t.Fatalf("ComputeRewardBreakdown() error = %v", err)
+	}
+	if got := breakdown["correct_answer"]; got != 0.0 {
+		t.Errorf("correct_answer with units kept = %v, want 0", got)
+	}
+
+	rubric.SetStripUnits(true)
+	breakdown, err = rubric.ComputeRewardBreakdown(ctx, response, "42 meters")
+	if err != nil {
+		t.Fatalf("ComputeRewardBreakdown() error = %v", err)
+	}
+	if got := breakdown["correct_answer"]; got != 1.0 {
+		t.Errorf("correct_answer with units stripped = %v, want 1", got)
+	}
+}
+
+func TestMathRubric_ComputeRewardDetail(t *testing.T) {
+	rubric, err := NewMathRubric()
+	if err != nil {
+		t.Fatalf("NewMathRubric failed: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		response    string
+		wantPresent bool
+		wantCorrect bool
+	}{
+		{"no answer", "<think>\n2 + 2 is 4\n</think>", false, false},
+		{"wrong answer", "<think>\n2 + 2 is 5\n</think>\n<answer>\n5\n</answer>", true, false},
+		{"correct answer", "<think>\n2 + 2 is 4\n</think>\n<answer>\n4\n</answer>", true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			detail, err := rubric.ComputeRewardDetail(context.Background(), tt.response, "\\boxed{4}")
+			if err != nil {
+				t.Fatalf("ComputeRewardDetail failed: %v", err)
+			}
+			if detail.AnswerPresent != tt.wantPresent || detail.AnswerCorrect != tt.wantCorrect {
+				t.Errorf("Got present=%v correct=%v, want present=%v correct=%v", detail.AnswerPresent, detail.AnswerCorrect, tt.wantPresent, tt.wantCorrect)
+			}
+			if detail.FormatScore <= 0 {
+				t.Errorf("Expected format credit for the think tag, got %v", detail.FormatScore)
+			}
+		})
+	}
+}
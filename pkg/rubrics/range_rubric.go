@@ -0,0 +1,216 @@
+package rubrics
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/rizome-dev/go-verifiers/pkg/types"
+	"github.com/rizome-dev/go-verifiers/pkg/utils"
+)
+
+// NumericRange is an interval with optional, independently inclusive bounds
+type NumericRange struct {
+	Min          float64
+	Max          float64
+	MinInclusive bool
+	MaxInclusive bool
+}
+
+// Contains reports whether value lies within the range
+func (r NumericRange) Contains(value float64) bool {
+	if value < r.Min || (value == r.Min && !r.MinInclusive) {
+		return false
+	}
+	if value > r.Max || (value == r.Max && !r.MaxInclusive) {
+		return false
+	}
+	return true
+}
+
+// String renders the range in interval notation
+func (r NumericRange) String() string {
+	left, right := "(", ")"
+	if r.MinInclusive {
+		left = "["
+	}
+	if r.MaxInclusive {
+		right = "]"
+	}
+	return fmt.Sprintf("%s%s, %s%s", left, formatBound(r.Min), formatBound(r.Max), right)
+}
+
+const rangeNum = `([-+]?(?:\d+\.?\d*|\.\d+)(?:[eE][-+]?\d+)?)`
+
+var (
+	intervalPattern   = regexp.MustCompile(`^([\[(])\s*` + rangeNum + `\s*,\s*` + rangeNum + `\s*([\])])$`)
+	betweenPattern    = regexp.MustCompile(`(?i)^(?:between|from)\s+` + rangeNum + `\s+(?:and|to)\s+` + rangeNum + `$`)
+	toPattern         = regexp.MustCompile(`(?i)^` + rangeNum + `\s*(?:to|\.\.)\s*` + rangeNum + `$`)
+	chainedPattern    = regexp.MustCompile(`^` + rangeNum + `\s*(<=|<|≤)\s*[a-zA-Z_]\w*\s*(<=|<|≤)\s*` + rangeNum + `$`)
+	inequalityPattern = regexp.MustCompile(`^(?:[a-zA-Z_]\w*\s*)?(<=|>=|<|>|≤|≥|=)\s*` + rangeNum + `$`)
+	phrasePattern     = regexp.MustCompile(`(?i)^(at least|no less than|at most|no more than|more than|greater than|over|less than|fewer than|under)\s+` + rangeNum + `$`)
+	pointPattern      = regexp.MustCompile(`^` + rangeNum + `$`)
+)
+
+// ParseRange parses a range or inequality such as "[10, 20)", "between 10 and 20",
+// "x > 5", "5 <= x < 10" or "at least 3". A plain number parses to a single-point range.
+func ParseRange(text string) (NumericRange, error) {
+	text = strings.TrimSpace(text)
+
+	if m := intervalPattern.FindStringSubmatch(text); m != nil {
+		return newBoundedRange(m[2], m[3], m[1] == "[", m[4] == "]")
+	}
+
+	if m := betweenPattern.FindStringSubmatch(text); m != nil {
+		return newBoundedRange(m[1], m[2], true, true)
+	}
+
+	if m := toPattern.FindStringSubmatch(text); m != nil {
+		return newBoundedRange(m[1], m[2], true, true)
+	}
+
+	if m := chainedPattern.FindStringSubmatch(text); m != nil {
+		return newBoundedRange(m[1], m[4], m[2] != "<", m[3] != "<")
+	}
+
+	if m := inequalityPattern.FindStringSubmatch(text); m != nil {
+		return newOneSidedRange(m[1], m[2])
+	}
+
+	if m := phrasePattern.FindStringSubmatch(text); m != nil {
+		ops := map[string]string{
+			"at least":     ">=",
+			"no less than": ">=",
+			"at most":      "<=",
+			"no more than": "<=",
+			"more than":    ">",
+			"greater than": ">",
+			"over":         ">",
+			"less than":    "<",
+			"fewer than":   "<",
+			"under":        "<",
+		}
+		return newOneSidedRange(ops[strings.ToLower(m[1])], m[2])
+	}
+
+	if m := pointPattern.FindStringSubmatch(text); m != nil {
+		return newOneSidedRange("=", m[1])
+	}
+
+	return NumericRange{}, fmt.Errorf("could not parse range from %q", text)
+}
+
+// newBoundedRange builds a two-sided range, ordering the bounds if needed
+func newBoundedRange(lowStr, highStr string, lowInclusive, highInclusive bool) (NumericRange, error) {
+	low, err := strconv.ParseFloat(lowStr, 64)
+	if err != nil {
+		return NumericRange{}, fmt.Errorf("invalid lower bound %q: %w", lowStr, err)
+	}
+	high, err := strconv.ParseFloat(highStr, 64)
+	if err != nil {
+		return NumericRange{}, fmt.Errorf("invalid upper bound %q: %w", highStr, err)
+	}
+	if low > high {
+		low, high = high, low
+		lowInclusive, highInclusive = highInclusive, lowInclusive
+	}
+	return NumericRange{Min: low, Max: high, MinInclusive: lowInclusive, MaxInclusive: highInclusive}, nil
+}
+
+// newOneSidedRange builds a range from a single comparison operator
+func newOneSidedRange(op, boundStr string) (NumericRange, error) {
+	bound, err := strconv.ParseFloat(boundStr, 64)
+	if err != nil {
+		return NumericRange{}, fmt.Errorf("invalid bound %q: %w", boundStr, err)
+	}
+
+	r := NumericRange{Min: math.Inf(-1), Max: math.Inf(1), MinInclusive: true, MaxInclusive: true}
+	switch op {
+	case ">":
+		r.Min, r.MinInclusive = bound, false
+	case ">=", "≥":
+		r.Min = bound
+	case "<":
+		r.Max, r.MaxInclusive = bound, false
+	case "<=", "≤":
+		r.Max = bound
+	case "=":
+		r.Min, r.Max = bound, bound
+	default:
+		return NumericRange{}, fmt.Errorf("unsupported operator %q", op)
+	}
+	return r, nil
+}
+
+// formatBound formats a bound, rendering infinities readably
+func formatBound(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "inf"
+	case math.IsInf(v, -1):
+		return "-inf"
+	default:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+}
+
+// RangeRubric scores numeric answers against a range or inequality in the ground truth
+type RangeRubric struct {
+	*BaseRubric
+}
+
+// NewRangeRubric creates a rubric that returns 1.0 when the answer falls inside
+// the range described by the ground truth and 0.0 otherwise
+func NewRangeRubric() *RangeRubric {
+	rubric := &RangeRubric{
+		BaseRubric: NewBaseRubric(),
+	}
+
+	// Replace the default exact match with a range check
+	rangeFunc := func(ctx context.Context, parsed, groundTruth string) (float64, error) {
+		return rubric.scoreRange(parsed, groundTruth)
+	}
+
+	rubric.rewardFuncs = []types.RewardFunc{rangeFunc}
+	rubric.rewardWeights = []float64{1.0}
+
+	return rubric
+}
+
+// scoreRange checks whether the parsed numeric answer satisfies the ground truth range
+func (r *RangeRubric) scoreRange(parsed, groundTruth string) (float64, error) {
+	bounds, err := ParseRange(groundTruth)
+	if err != nil {
+		return 0.0, err
+	}
+
+	value, ok := extractNumericAnswer(parsed)
+	if !ok {
+		return 0.0, nil
+	}
+
+	if bounds.Contains(value) {
+		return 1.0, nil
+	}
+	return 0.0, nil
+}
+
+// extractNumericAnswer reads a number from the answer, falling back to the first number in the text
+func extractNumericAnswer(text string) (float64, bool) {
+	if value, err := strconv.ParseFloat(utils.NormalizeNumber(text), 64); err == nil {
+		return value, true
+	}
+
+	first := utils.ExtractFirstNumber(strings.ReplaceAll(text, ",", ""))
+	if first == "" {
+		return 0, false
+	}
+	value, err := strconv.ParseFloat(first, 64)
+	if err != nil {
+		return 0, false
+	}
+	return value, true
+}
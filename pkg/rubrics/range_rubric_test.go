@@ -0,0 +1,49 @@
+package rubrics
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRangeRubric_ComputeReward(t *testing.T) {
+	tests := []struct {
+		name        string
+		parsed      string
+		groundTruth string
+		expected    float64
+	}{
+		{name: "between inclusive lower", parsed: "10", groundTruth: "between 10 and 20", expected: 1.0},
+		{name: "between inclusive upper", parsed: "20", groundTruth: "between 10 and 20", expected: 1.0},
+		{name: "between inside", parsed: "about 15 meters", groundTruth: "between 10 and 20", expected: 1.0},
+		{name: "between out of range", parsed: "21", groundTruth: "between 10 and 20", expected: 0.0},
+		{name: "closed interval", parsed: "1,000", groundTruth: "[500, 1000]", expected: 1.0},
+		{name: "half-open excludes upper", parsed: "20", groundTruth: "[10, 20)", expected: 0.0},
+		{name: "open excludes lower", parsed: "10", groundTruth: "(10, 20)", expected: 0.0},
+		{name: "strict greater than", parsed: "5", groundTruth: "x > 5", expected: 0.0},
+		{name: "greater than satisfied", parsed: "5.01", groundTruth: "x > 5", expected: 1.0},
+		{name: "less or equal", parsed: "-3", groundTruth: "<= -3", expected: 1.0},
+		{name: "chained inequality", parsed: "7", groundTruth: "5 <= x < 7", expected: 0.0},
+		{name: "at least phrase", parsed: "3", groundTruth: "at least 3", expected: 1.0},
+		{name: "no number in answer", parsed: "I don't know", groundTruth: "between 1 and 2", expected: 0.0},
+	}
+
+	rubric := NewRangeRubric()
+	ctx := context.Background()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := rubric.ComputeReward(ctx, tt.parsed, tt.groundTruth)
+			if err != nil {
+				t.Fatalf("ComputeReward() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("ComputeReward(%q, %q) = %v, want %v", tt.parsed, tt.groundTruth, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseRange_Invalid(t *testing.T) {
+	if _, err := ParseRange("somewhere around ten"); err == nil {
+		t.Errorf("Expected error for unparseable range")
+	}
+}
@@ -0,0 +1,73 @@
+package rubrics
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/rizome-dev/go-verifiers/pkg/parsers"
+	"github.com/rizome-dev/go-verifiers/pkg/types"
+)
+
+// NewRegexMatchReward creates a reward function returning 1.0 when the parsed answer
+// matches pattern and 0.0 otherwise. With fullMatch the whole trimmed answer must
+// match; otherwise a match anywhere in the answer is enough. The pattern is compiled
+// once here, and an invalid pattern is reported as an error.
+func NewRegexMatchReward(pattern string, fullMatch bool) (types.RewardFunc, error) {
+	if fullMatch {
+		pattern = `^(?:` + pattern + `)$`
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern: %w", err)
+	}
+
+	return func(ctx context.Context, parsed, groundTruth string) (float64, error) {
+		if fullMatch {
+			parsed = strings.TrimSpace(parsed)
+		}
+		if re.MatchString(parsed) {
+			return 1.0, nil
+		}
+		return 0.0, nil
+	}, nil
+}
+
+// NewContainsReward creates a reward function for keyword checks. It returns 1.0 when
+// the parsed answer contains every substring (requireAll) or any substring, and 0.0
+// otherwise. Matching is case-sensitive.
+func NewContainsReward(substrings []string, requireAll bool) types.RewardFunc {
+	keywords := make([]string, len(substrings))
+	copy(keywords, substrings)
+
+	return func(ctx context.Context, parsed, groundTruth string) (float64, error) {
+		for _, keyword := range keywords {
+			found := strings.Contains(parsed, keyword)
+			if requireAll && !found {
+				return 0.0, nil
+			}
+			if !requireAll && found {
+				return 1.0, nil
+			}
+		}
+
+		if requireAll {
+			return 1.0, nil
+		}
+		return 0.0, nil
+	}
+}
+
+// NewFormatReward creates a reward function that scores the response with the
+// parser's FollowsFormat. The unparsed response attached with WithRawResponse is
+// preferred, since parsing usually strips the formatting being checked.
+func NewFormatReward(parser parsers.Parser) types.RewardFunc {
+	return func(ctx context.Context, response, groundTruth string) (float64, error) {
+		if raw, ok := RawResponse(ctx); ok {
+			response = raw
+		}
+		return parser.FollowsFormat(response), nil
+	}
+}
@@ -0,0 +1,84 @@
+package rubrics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rizome-dev/go-verifiers/pkg/parsers"
+)
+
+func TestNewRegexMatchReward(t *testing.T) {
+	tests := []struct {
+		name      string
+		pattern   string
+		fullMatch bool
+		parsed    string
+		expected  float64
+	}{
+		{name: "full match date", pattern: `\d{4}-\d{2}-\d{2}`, fullMatch: true, parsed: " 2024-01-31\n", expected: 1.0},
+		{name: "full match rejects extra text", pattern: `\d{4}-\d{2}-\d{2}`, fullMatch: true, parsed: "On 2024-01-31", expected: 0.0},
+		{name: "partial match", pattern: `\d{4}-\d{2}-\d{2}`, fullMatch: false, parsed: "On 2024-01-31", expected: 1.0},
+		{name: "alternation is anchored as a whole", pattern: `yes|no`, fullMatch: true, parsed: "yesterday", expected: 0.0},
+	}
+
+	ctx := context.Background()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fn, err := NewRegexMatchReward(tt.pattern, tt.fullMatch)
+			if err != nil {
+				t.Fatalf("NewRegexMatchReward() error = %v", err)
+			}
+			got, _ := fn(ctx, tt.parsed, "")
+			if got != tt.expected {
+				t.Errorf("reward(%q) = %v, want %v", tt.parsed, got, tt.expected)
+			}
+		})
+	}
+
+	if _, err := NewRegexMatchReward(`(unclosed`, false); err == nil {
+		t.Errorf("Expected error for invalid pattern")
+	}
+}
+
+func TestNewContainsReward(t *testing.T) {
+	ctx := context.Background()
+	keywords := []string{"Paris", "France"}
+
+	all := NewContainsReward(keywords, true)
+	anyOf := NewContainsReward(keywords, false)
+
+	if got, _ := all(ctx, "Paris is the capital of France", ""); got != 1.0 {
+		t.Errorf("requireAll with both keywords = %v, want 1.0", got)
+	}
+	if got, _ := all(ctx, "Paris", ""); got != 0.0 {
+		t.Errorf("requireAll with one keyword = %v, want 0.0", got)
+	}
+	if got, _ := anyOf(ctx, "Paris", ""); got != 1.0 {
+		t.Errorf("any with one keyword = %v, want 1.0", got)
+	}
+	if got, _ := anyOf(ctx, "London", ""); got != 0.0 {
+		t.Errorf("any with no keywords = %v, want 0.0", got)
+	}
+
+	// Usable directly as a metric
+	rubric := NewMultiMetricRubric()
+	rubric.AddMetric("mentions_capital", all, 0.5)
+}
+
+func TestNewFormatReward(t *testing.T) {
+	reward := NewFormatReward(parsers.NewThinkParser())
+	raw := "<think>\nhmm\n</think>\n42"
+
+	ctx := context.Background()
+	if got, _ := reward(ctx, raw, "42"); got != 1.0 {
+		t.Errorf("formatted response = %v, want 1.0", got)
+	}
+	if got, _ := reward(ctx, "42", "42"); got != 0.0 {
+		t.Errorf("unformatted response = %v, want 0.0", got)
+	}
+
+	// The raw response is scored rather than the parsed answer
+	if got, _ := reward(WithRawResponse(ctx, raw), "42", "42"); got != 1.0 {
+		t.Errorf("parsed answer with raw response in context = %v, want 1.0", got)
+	}
+}
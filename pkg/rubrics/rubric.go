@@ -2,8 +2,12 @@ package rubrics
 
 import (
 	"context"
+	"fmt"
+	"sort"
 	"strings"
+	"sync"
 
+	"github.com/rizome-dev/go-verifiers/pkg/parsers"
 	"github.com/rizome-dev/go-verifiers/pkg/types"
 )
 
@@ -15,10 +19,64 @@ type Rubric interface {
 	// GetRewardWeights returns the weights for each reward function
 	GetRewardWeights() []float64
 	
+	// GetRewardFuncNames returns the name of each reward function, so the i-th name
+	// belongs to the i-th function and weight
+	GetRewardFuncNames() []string
+	
 	// ComputeReward computes the total reward given parsed response and ground truth
 	ComputeReward(ctx context.Context, parsed string, groundTruth string) (float64, error)
 }
 
+// BreakdownRubric is implemented by rubrics that can report per-metric scores
+type BreakdownRubric interface {
+	Rubric
+
+	// ComputeRewardBreakdown returns the raw score of each named metric
+	ComputeRewardBreakdown(ctx context.Context, parsed string, groundTruth string) (map[string]float64, error)
+}
+
+// BreakdownScorer is implemented by rubrics that compute the reward and its
+// per-metric breakdown in one pass, scoring each metric once
+type BreakdownScorer interface {
+	Rubric
+
+	// ComputeRewardWithBreakdown returns the reward together with the raw score of
+	// each named metric
+	ComputeRewardWithBreakdown(ctx context.Context, parsed string, groundTruth string) (float64, map[string]float64, error)
+}
+
+// RewardDetail separates a missing answer from a wrong one
+type RewardDetail struct {
+	AnswerPresent bool    // The response contains a non-empty answer field
+	AnswerCorrect bool    // The answer matches the ground truth; false when no answer is present
+	FormatScore   float64 // Score of the rubric's format metric
+}
+
+// DetailRubric is implemented by rubrics that can report a RewardDetail, so error
+// analysis can tell "didn't follow the format" from "followed it, wrong answer"
+type DetailRubric interface {
+	Rubric
+
+	// ComputeRewardDetail reports whether an answer was given, whether it is
+	// correct, and how well the response follows the format
+	ComputeRewardDetail(ctx context.Context, parsed string, groundTruth string) (RewardDetail, error)
+}
+
+// rawResponseKey is the context key for the unparsed model response
+type rawResponseKey struct{}
+
+// WithRawResponse attaches the unparsed model response to the context passed to
+// reward functions, so metrics such as LengthPenalty can inspect the full output
+func WithRawResponse(ctx context.Context, response string) context.Context {
+	return context.WithValue(ctx, rawResponseKey{}, response)
+}
+
+// RawResponse returns the unparsed model response attached by WithRawResponse
+func RawResponse(ctx context.Context) (string, bool) {
+	response, ok := ctx.Value(rawResponseKey{}).(string)
+	return response, ok
+}
+
 // BaseRubric provides a default exact match implementation
 type BaseRubric struct {
 	rewardFuncs   []types.RewardFunc
@@ -53,6 +111,19 @@ func (r *BaseRubric) GetRewardWeights() []float64 {
 	return r.rewardWeights
 }
 
+// GetRewardFuncNames returns "reward" for a single reward function, and
+// "reward_0", "reward_1", ... when there are several
+func (r *BaseRubric) GetRewardFuncNames() []string {
+	if len(r.rewardFuncs) == 1 {
+		return []string{"reward"}
+	}
+	names := make([]string, len(r.rewardFuncs))
+	for i := range names {
+		names[i] = fmt.Sprintf("reward_%d", i)
+	}
+	return names
+}
+
 // ComputeReward computes the weighted sum of all reward functions
 func (r *BaseRubric) ComputeReward(ctx context.Context, parsed string, groundTruth string) (float64, error) {
 	if len(r.rewardFuncs) == 0 {
@@ -87,24 +158,228 @@ func (r *BaseRubric) ComputeReward(ctx context.Context, parsed string, groundTru
 // MultiMetricRubric supports multiple evaluation metrics
 type MultiMetricRubric struct {
 	BaseRubric
-	metrics map[string]types.RewardFunc
+	metrics     map[string]types.RewardFunc
+	metricNames []string // Name of each reward function, index-aligned with rewardFuncs
+
+	mu            sync.Mutex
+	failedMetrics []string // Metrics that failed in the most recent ComputeReward call
 }
 
-// NewMultiMetricRubric creates a rubric with multiple metrics
+// NewMultiMetricRubric creates a rubric with multiple metrics.
+// It starts without metrics; only those added with AddMetric are scored.
 func NewMultiMetricRubric() *MultiMetricRubric {
 	return &MultiMetricRubric{
-		BaseRubric: *NewBaseRubric(),
-		metrics:    make(map[string]types.RewardFunc),
+		BaseRubric: BaseRubric{
+			rewardFuncs:   []types.RewardFunc{},
+			rewardWeights: []float64{},
+		},
+		metrics: make(map[string]types.RewardFunc),
 	}
 }
 
 // AddMetric adds a named metric to the rubric
 func (r *MultiMetricRubric) AddMetric(name string, fn types.RewardFunc, weight float64) {
 	r.metrics[name] = fn
+	r.metricNames = append(r.metricNames, name)
 	r.rewardFuncs = append(r.rewardFuncs, fn)
 	r.rewardWeights = append(r.rewardWeights, weight)
 }
 
+// GetRewardFuncNames returns the metric names in the order they were added
+func (r *MultiMetricRubric) GetRewardFuncNames() []string {
+	names := make([]string, len(r.rewardFuncs))
+	for i := range names {
+		names[i] = r.metricName(i)
+	}
+	return names
+}
+
+// metricName returns the name of the i-th reward function
+func (r *MultiMetricRubric) metricName(i int) string {
+	if i < len(r.metricNames) {
+		return r.metricNames[i]
+	}
+	return fmt.Sprintf("metric_%d", i)
+}
+
+// ComputeReward computes the weighted mean of the metrics that succeed. Unlike
+// BaseRubric, a failing metric does not fail the whole score: it is left out and
+// the remaining weights are renormalized. An error is returned only when every
+// metric fails. LastFailedMetrics reports which metrics were left out.
+func (r *MultiMetricRubric) ComputeReward(ctx context.Context, parsed string, groundTruth string) (float64, error) {
+	score, _, err := r.ComputeRewardWithBreakdown(ctx, parsed, groundTruth)
+	return score, err
+}
+
+// ComputeRewardWithBreakdown is ComputeReward, also returning the breakdown of
+// ComputeRewardBreakdown from the same metric scores, so each metric runs once
+func (r *MultiMetricRubric) ComputeRewardWithBreakdown(ctx context.Context, parsed string, groundTruth string) (float64, map[string]float64, error) {
+	score, breakdown, failed, err := r.score(ctx, parsed, groundTruth)
+
+	names := make([]string, 0, len(failed))
+	for name := range failed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	r.mu.Lock()
+	r.failedMetrics = names
+	r.mu.Unlock()
+
+	return score, breakdown, err
+}
+
+// ComputeRewardWithErrors is ComputeReward, also returning the error of each failed
+// metric by name. Use it instead of LastFailedMetrics when scoring concurrently.
+func (r *MultiMetricRubric) ComputeRewardWithErrors(ctx context.Context, parsed string, groundTruth string) (float64, map[string]error, error) {
+	score, _, failed, err := r.score(ctx, parsed, groundTruth)
+	return score, failed, err
+}
+
+// score runs every metric once and returns the weighted mean of those that succeed,
+// their breakdown by name and the error of each failed metric
+func (r *MultiMetricRubric) score(ctx context.Context, parsed string, groundTruth string) (float64, map[string]float64, map[string]error, error) {
+	failed := make(map[string]error)
+	totalReward := 0.0
+	totalWeight := 0.0
+	var firstErr error
+
+	// Metrics added under one name are combined like Weights combines their weights
+	nameReward := make(map[string]float64)
+	nameWeight := make(map[string]float64)
+	nameSum := make(map[string]float64)
+	nameCount := make(map[string]int)
+
+	for i, fn := range r.rewardFuncs {
+		weight := 1.0
+		if i < len(r.rewardWeights) {
+			weight = r.rewardWeights[i]
+		}
+		name := r.metricName(i)
+
+		reward, err := fn(ctx, parsed, groundTruth)
+		if err != nil {
+			failed[name] = err
+			if firstErr == nil {
+				firstErr = fmt.Errorf("metric %s: %w", name, err)
+			}
+			continue
+		}
+
+		totalReward += reward * weight
+		totalWeight += weight
+
+		nameReward[name] += reward * weight
+		nameWeight[name] += weight
+		nameSum[name] += reward
+		nameCount[name]++
+	}
+
+	if len(r.rewardFuncs) > 0 && len(failed) == len(r.rewardFuncs) {
+		return 0.0, nil, failed, fmt.Errorf("all %d metrics failed, first: %w", len(failed), firstErr)
+	}
+
+	breakdown := make(map[string]float64, len(nameCount))
+	for name, count := range nameCount {
+		if nameWeight[name] != 0 {
+			breakdown[name] = nameReward[name] / nameWeight[name]
+		} else {
+			breakdown[name] = nameSum[name] / float64(count)
+		}
+	}
+
+	if totalWeight > 0 {
+		return totalReward / totalWeight, breakdown, failed, nil
+	}
+	return 0.0, breakdown, failed, nil
+}
+
+// LastFailedMetrics returns the names of the metrics that failed in the most recent
+// ComputeReward call, sorted. With concurrent calls it reflects whichever finished last.
+func (r *MultiMetricRubric) LastFailedMetrics() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string{}, r.failedMetrics...)
+}
+
+// NormalizeWeights scales the metric weights to sum to 1. Scores are unchanged,
+// since ComputeReward already divides by the total weight. It does nothing when the
+// weights sum to zero.
+func (r *MultiMetricRubric) NormalizeWeights() {
+	total := 0.0
+	for _, w := range r.rewardWeights {
+		total += w
+	}
+	if total == 0 {
+		return
+	}
+	for i := range r.rewardWeights {
+		r.rewardWeights[i] /= total
+	}
+}
+
+// Weights returns the weight of each named metric. A name added more than once
+// reports the combined weight of its metrics.
+func (r *MultiMetricRubric) Weights() map[string]float64 {
+	weights := make(map[string]float64, len(r.metricNames))
+	for i, name := range r.metricNames {
+		if i < len(r.rewardWeights) {
+			weights[name] += r.rewardWeights[i]
+		}
+	}
+	return weights
+}
+
+// ComputeRewardBreakdown returns the raw, unweighted score of each named metric.
+// Metrics added under the same name report the weighted mean of their scores, so
+// the breakdown weighted by Weights gives the reward. Like ComputeReward, it leaves
+// failed metrics out and returns an error only when every metric fails.
+func (r *MultiMetricRubric) ComputeRewardBreakdown(ctx context.Context, parsed string, groundTruth string) (map[string]float64, error) {
+	_, breakdown, _, err := r.score(ctx, parsed, groundTruth)
+	if err != nil {
+		return nil, err
+	}
+	return breakdown, nil
+}
+
+// rewardDetail builds a RewardDetail from the rubric's correct_answer and format
+// metrics, given whether an answer is present
+func (r *MultiMetricRubric) rewardDetail(ctx context.Context, parsed string, groundTruth string, answerPresent bool) (RewardDetail, error) {
+	detail := RewardDetail{AnswerPresent: answerPresent}
+
+	if fn, ok := r.metrics["format"]; ok {
+		score, err := fn(ctx, parsed, groundTruth)
+		if err != nil {
+			return RewardDetail{}, fmt.Errorf("metric format: %w", err)
+		}
+		detail.FormatScore = score
+	}
+
+	if fn, ok := r.metrics["correct_answer"]; ok && answerPresent {
+		score, err := fn(ctx, parsed, groundTruth)
+		if err != nil {
+			return RewardDetail{}, fmt.Errorf("metric correct_answer: %w", err)
+		}
+		detail.AnswerCorrect = score >= 1.0
+	}
+
+	return detail, nil
+}
+
+// hasAnswerField reports whether any of the texts has a non-empty answer field
+func hasAnswerField(parser *parsers.XMLParser, texts ...string) bool {
+	if parser == nil {
+		return false
+	}
+	for _, text := range texts {
+		parsed, err := parser.ParseXML(text, true)
+		if err == nil && strings.TrimSpace(parsed.Fields["answer"]) != "" {
+			return true
+		}
+	}
+	return false
+}
+
 // GetMetric returns a specific metric by name
 func (r *MultiMetricRubric) GetMetric(name string) (types.RewardFunc, bool) {
 	fn, ok := r.metrics[name]
@@ -3,15 +3,42 @@ package rubrics
 import (
 	"context"
 	"fmt"
+	"math"
+	"sort"
+	"sync/atomic"
 
 	"github.com/rizome-dev/go-verifiers/pkg/types"
 )
 
+// AggregationStrategy determines how RubricGroup.ComputeReward combines the scores
+// of its sub-rubrics
+type AggregationStrategy string
+
+const (
+	// WeightedMean averages the scores, weighting each rubric by the sum of its
+	// reward weights (1.0 for a rubric without weights). It is the default.
+	WeightedMean AggregationStrategy = "weighted_mean"
+	// Min returns the lowest score, so every rubric must pass. Weights are ignored.
+	Min AggregationStrategy = "min"
+	// Max returns the highest score, so any passing rubric suffices. Weights are ignored.
+	Max AggregationStrategy = "max"
+	// Product multiplies the scores, so a single zero fails the whole group.
+	// Weights are ignored.
+	Product AggregationStrategy = "product"
+)
+
 // RubricGroup aggregates multiple rubrics into one
 type RubricGroup struct {
 	rubrics      []Rubric
 	rubricNames  []string
 	mergeWeights bool // Whether to merge weights for same-named functions
+	aggregation  AggregationStrategy
+
+	// FailFast makes ComputeReward return the first sub-rubric error instead of
+	// leaving the failed rubric out of the aggregate
+	FailFast bool
+
+	lastErrorCount atomic.Int64
 }
 
 // NewRubricGroup creates a new rubric group
@@ -20,100 +47,152 @@ func NewRubricGroup(rubrics map[string]Rubric, mergeWeights bool) *RubricGroup {
 		rubrics:      make([]Rubric, 0, len(rubrics)),
 		rubricNames:  make([]string, 0, len(rubrics)),
 		mergeWeights: mergeWeights,
+		aggregation:  WeightedMean,
 	}
 
 	// Maintain consistent ordering
-	for name, rubric := range rubrics {
+	for name := range rubrics {
 		group.rubricNames = append(group.rubricNames, name)
-		group.rubrics = append(group.rubrics, rubric)
+	}
+	sort.Strings(group.rubricNames)
+	for _, name := range group.rubricNames {
+		group.rubrics = append(group.rubrics, rubrics[name])
 	}
 
 	return group
 }
 
-// GetRewardFuncs returns combined reward functions from all rubrics
-func (r *RubricGroup) GetRewardFuncs() []types.RewardFunc {
-	funcs := make([]types.RewardFunc, 0)
-
-	if r.mergeWeights {
-		// Merge functions with the same name
-		funcMap := make(map[string][]types.RewardFunc)
-		
-		for i, rubric := range r.rubrics {
-			rubricFuncs := rubric.GetRewardFuncs()
-			for j, fn := range rubricFuncs {
-				// Create a unique key for each function
-				// In practice, we'd need a way to identify function names
-				key := fmt.Sprintf("func_%d_%d", i, j)
-				funcMap[key] = append(funcMap[key], fn)
-			}
-		}
-
-		// Create merged functions
-		for _, fns := range funcMap {
-			if len(fns) == 1 {
-				funcs = append(funcs, fns[0])
-			} else {
-				// Create a merged function that runs all and averages
-				mergedFunc := r.createMergedFunc(fns)
-				funcs = append(funcs, mergedFunc)
-			}
-		}
-	} else {
-		// Simply concatenate all functions
-		for _, rubric := range r.rubrics {
-			funcs = append(funcs, rubric.GetRewardFuncs()...)
-		}
+// SetAggregation sets how ComputeReward combines sub-rubric scores. An empty
+// strategy restores WeightedMean.
+func (r *RubricGroup) SetAggregation(strategy AggregationStrategy) {
+	if strategy == "" {
+		strategy = WeightedMean
 	}
+	r.aggregation = strategy
+}
 
+// GetRewardFuncs returns combined reward functions from all rubrics.
+// The i-th function always corresponds to the i-th weight from GetRewardWeights.
+func (r *RubricGroup) GetRewardFuncs() []types.RewardFunc {
+	funcs, _, _ := r.collectRewardFuncs()
 	return funcs
 }
 
-// GetRewardWeights returns combined weights from all rubrics
+// GetRewardWeights returns combined weights from all rubrics.
+// The i-th weight always corresponds to the i-th function from GetRewardFuncs.
 func (r *RubricGroup) GetRewardWeights() []float64 {
-	weights := make([]float64, 0)
+	_, weights, _ := r.collectRewardFuncs()
+	return weights
+}
 
-	if r.mergeWeights {
-		// When merging, weights should match the merged functions
-		// For simplicity, we'll average weights for merged functions
-		funcCount := len(r.GetRewardFuncs())
-		totalWeight := 0.0
-		
-		for _, rubric := range r.rubrics {
-			rubricWeights := rubric.GetRewardWeights()
-			for _, w := range rubricWeights {
-				totalWeight += w
+// GetRewardFuncNames returns combined function names from all rubrics, each
+// prefixed with its rubric's name, as in "math/correct_answer". Functions merged
+// across rubrics keep their bare name.
+// The i-th name always corresponds to the i-th function from GetRewardFuncs.
+func (r *RubricGroup) GetRewardFuncNames() []string {
+	_, _, names := r.collectRewardFuncs()
+	return names
+}
+
+// rewardGroup holds the functions and weights that share a merge key
+type rewardGroup struct {
+	name    string // Name of the first function in the group, prefixed with its rubric
+	metric  string // Unprefixed function name
+	funcs   []types.RewardFunc
+	weights []float64
+}
+
+// collectRewardFuncs builds functions and weights in a single deterministic pass.
+// Rubrics are visited in group order and functions in rubric order. When merging,
+// functions with the same name, such as the "format" metric of two rubrics, are
+// combined into one function that averages their results, weighted by the average
+// of their weights, at the position of the first. A merged function is named
+// without a rubric prefix, since it belongs to several rubrics.
+func (r *RubricGroup) collectRewardFuncs() ([]types.RewardFunc, []float64, []string) {
+	keys := make([]string, 0)
+	groups := make(map[string]*rewardGroup)
+
+	for i, rubric := range r.rubrics {
+		rubricFuncs := rubric.GetRewardFuncs()
+		rubricWeights := rubric.GetRewardWeights()
+		rubricFuncNames := rubric.GetRewardFuncNames()
+
+		for j, fn := range rubricFuncs {
+			// Missing weights default to 1.0, matching BaseRubric.ComputeReward
+			weight := 1.0
+			if j < len(rubricWeights) {
+				weight = rubricWeights[j]
 			}
-		}
 
-		// Distribute weight evenly among merged functions
-		if funcCount > 0 {
-			avgWeight := totalWeight / float64(funcCount)
-			for i := 0; i < funcCount; i++ {
-				weights = append(weights, avgWeight)
+			name := fmt.Sprintf("reward_%d", j)
+			if j < len(rubricFuncNames) {
+				name = rubricFuncNames[j]
 			}
+
+			key := r.rewardFuncKey(i, j, name)
+			group, ok := groups[key]
+			if !ok {
+				group = &rewardGroup{name: r.rubricNames[i] + "/" + name, metric: name}
+				groups[key] = group
+				keys = append(keys, key)
+			}
+			group.funcs = append(group.funcs, fn)
+			group.weights = append(group.weights, weight)
 		}
-	} else {
-		// Simply concatenate all weights
-		for _, rubric := range r.rubrics {
-			weights = append(weights, rubric.GetRewardWeights()...)
+	}
+
+	funcs := make([]types.RewardFunc, 0, len(keys))
+	weights := make([]float64, 0, len(keys))
+	names := make([]string, 0, len(keys))
+	for _, key := range keys {
+		group := groups[key]
+		if len(group.funcs) == 1 {
+			names = append(names, group.name)
+			funcs = append(funcs, group.funcs[0])
+			weights = append(weights, group.weights[0])
+			continue
+		}
+		names = append(names, group.metric)
+
+		// Create a merged function that runs all and averages
+		totalWeight := 0.0
+		for _, w := range group.weights {
+			totalWeight += w
 		}
+		funcs = append(funcs, r.createMergedFunc(group.funcs))
+		weights = append(weights, totalWeight/float64(len(group.weights)))
 	}
 
-	return weights
+	return funcs, weights, names
 }
 
-// ComputeReward runs all rubrics and combines their scores
+// rewardFuncKey identifies the j-th function of the i-th rubric, named name, for
+// merging. With mergeWeights the key is the name, so same-named functions share it;
+// otherwise every function has its own key.
+func (r *RubricGroup) rewardFuncKey(i, j int, name string) string {
+	if r.mergeWeights {
+		return name
+	}
+	return fmt.Sprintf("%s/%d", r.rubricNames[i], j)
+}
+
+// ComputeReward runs all rubrics and combines their scores with the aggregation
+// strategy, a weighted mean unless SetAggregation chose another. A rubric that fails is left out of the aggregate, or, with FailFast, ends the
+// computation with its error. Either way the failures are counted in LastErrorCount.
 func (r *RubricGroup) ComputeReward(ctx context.Context, parsed string, groundTruth string) (float64, error) {
-	totalScore := 0.0
-	totalWeight := 0.0
+	scores := make([]float64, 0, len(r.rubrics))
+	weights := make([]float64, 0, len(r.rubrics))
+	failed := 0
 
 	// Run each rubric
-	for _, rubric := range r.rubrics {
+	for i, rubric := range r.rubrics {
 		score, err := rubric.ComputeReward(ctx, parsed, groundTruth)
 		if err != nil {
-			// Log error but continue with other rubrics
-			// In practice, we might want to handle this differently
+			failed++
+			if r.FailFast {
+				r.lastErrorCount.Store(int64(failed))
+				return 0.0, fmt.Errorf("rubric %s: %w", r.rubricNames[i], err)
+			}
 			continue
 		}
 
@@ -129,15 +208,60 @@ func (r *RubricGroup) ComputeReward(ctx context.Context, parsed string, groundTr
 			rubricWeight = 1.0
 		}
 
-		totalScore += score * rubricWeight
-		totalWeight += rubricWeight
+		scores = append(scores, score)
+		weights = append(weights, rubricWeight)
 	}
+	r.lastErrorCount.Store(int64(failed))
 
-	if totalWeight > 0 {
-		return totalScore / totalWeight, nil
+	return r.aggregate(scores, weights)
+}
+
+// aggregate combines sub-rubric scores with the group's strategy. Without any
+// scores the result is 0.
+func (r *RubricGroup) aggregate(scores, weights []float64) (float64, error) {
+	if len(scores) == 0 {
+		return 0.0, nil
+	}
+
+	switch r.aggregation {
+	case WeightedMean, "":
+		totalScore := 0.0
+		totalWeight := 0.0
+		for i, score := range scores {
+			totalScore += score * weights[i]
+			totalWeight += weights[i]
+		}
+		if totalWeight > 0 {
+			return totalScore / totalWeight, nil
+		}
+		return 0.0, nil
+	case Min:
+		result := scores[0]
+		for _, score := range scores[1:] {
+			result = math.Min(result, score)
+		}
+		return result, nil
+	case Max:
+		result := scores[0]
+		for _, score := range scores[1:] {
+			result = math.Max(result, score)
+		}
+		return result, nil
+	case Product:
+		result := 1.0
+		for _, score := range scores {
+			result *= score
+		}
+		return result, nil
+	default:
+		return 0.0, fmt.Errorf("unknown aggregation strategy %q", r.aggregation)
 	}
+}
 
-	return 0.0, nil
+// LastErrorCount returns how many sub-rubrics failed in the most recent
+// ComputeReward call. With concurrent calls it reflects whichever finished last.
+func (r *RubricGroup) LastErrorCount() int {
+	return int(r.lastErrorCount.Load())
 }
 
 // createMergedFunc creates a function that runs multiple functions and averages their results
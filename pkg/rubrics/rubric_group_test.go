@@ -0,0 +1,229 @@
+package rubrics
+
+import (
+	"context"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/rizome-dev/go-verifiers/pkg/types"
+)
+
+// staticRubric returns fixed scores from each reward function
+type staticRubric struct {
+	*BaseRubric
+}
+
+func newStaticRubric(scores []float64, weights []float64) *staticRubric {
+	rubric := &staticRubric{BaseRubric: NewBaseRubric()}
+	rubric.rewardFuncs = make([]types.RewardFunc, len(scores))
+	for i, score := range scores {
+		rubric.rewardFuncs[i] = func(ctx context.Context, parsed, groundTruth string) (float64, error) {
+			return score, nil
+		}
+	}
+	rubric.rewardWeights = weights
+	return rubric
+}
+
+func TestRubricGroup_RewardFuncWeightPairing(t *testing.T) {
+	ctx := context.Background()
+
+	for _, merge := range []bool{false, true} {
+		// Each function returns a tenth of its weight so pairing can be checked
+		group := NewRubricGroup(map[string]Rubric{
+			"a": newStaticRubric([]float64{0.1, 0.2}, []float64{1.0, 2.0}),
+			"b": newStaticRubric([]float64{0.3}, []float64{3.0}),
+		}, merge)
+
+		var firstOrder []float64
+		for call := 0; call < 10; call++ {
+			funcs := group.GetRewardFuncs()
+			weights := group.GetRewardWeights()
+
+			if len(funcs) != len(weights) {
+				t.Fatalf("merge=%v: got %d funcs and %d weights", merge, len(funcs), len(weights))
+			}
+			if len(funcs) != 3 {
+				t.Fatalf("merge=%v: expected 3 funcs, got %d", merge, len(funcs))
+			}
+
+			order := make([]float64, len(funcs))
+			for i, fn := range funcs {
+				score, err := fn(ctx, "", "")
+				if err != nil {
+					t.Fatalf("reward func error: %v", err)
+				}
+				if diff := weights[i] - score*10; diff > 1e-9 || diff < -1e-9 {
+					t.Errorf("merge=%v: func %d returned %.1f but weight is %.1f", merge, i, score, weights[i])
+				}
+				order[i] = score
+			}
+
+			if firstOrder == nil {
+				firstOrder = order
+				continue
+			}
+			for i := range order {
+				if order[i] != firstOrder[i] {
+					t.Fatalf("merge=%v: ordering changed between calls: %v vs %v", merge, firstOrder, order)
+				}
+			}
+		}
+	}
+}
+
+func TestRubricGroup_ErrorHandling(t *testing.T) {
+	judgeDown := errors.New("judge unavailable")
+	failing := NewBaseRubric()
+	failing.rewardFuncs = []types.RewardFunc{func(ctx context.Context, parsed, groundTruth string) (float64, error) {
+		return 0.0, judgeDown
+	}}
+
+	tests := []struct {
+		name      string
+		failFast  bool
+		wantErr   bool
+		wantScore float64
+	}{
+		{name: "tolerant", failFast: false, wantScore: 0.5},
+		{name: "fail fast", failFast: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			group := NewRubricGroup(map[string]Rubric{
+				"a_exact": newStaticRubric([]float64{1.0}, []float64{1.0}),
+				"b_judge": failing,
+				"c_other": newStaticRubric([]float64{0.0}, []float64{1.0}),
+			}, false)
+			group.FailFast = tt.failFast
+
+			score, err := group.ComputeReward(context.Background(), "x", "x")
+			if tt.wantErr {
+				if !errors.Is(err, judgeDown) {
+					t.Errorf("Expected the judge error, got %v", err)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("ComputeReward failed: %v", err)
+				}
+				if score != tt.wantScore {
+					t.Errorf("score = %v, want %v", score, tt.wantScore)
+				}
+			}
+			if got := group.LastErrorCount(); got != 1 {
+				t.Errorf("LastErrorCount() = %d, want 1", got)
+			}
+
+			// A successful call resets the count
+			group.rubrics[1] = newStaticRubric([]float64{1.0}, []float64{1.0})
+			if _, err := group.ComputeReward(context.Background(), "x", "x"); err != nil {
+				t.Fatalf("ComputeReward failed: %v", err)
+			}
+			if got := group.LastErrorCount(); got != 0 {
+				t.Errorf("LastErrorCount() after recovery = %d, want 0", got)
+			}
+		})
+	}
+}
+
+func TestRubricGroup_MergeWeights(t *testing.T) {
+	constant := func(score float64) types.RewardFunc {
+		return func(ctx context.Context, parsed, groundTruth string) (float64, error) {
+			return score, nil
+		}
+	}
+	a := NewMultiMetricRubric()
+	a.AddMetric("correct", constant(1.0), 0.8)
+	a.AddMetric("format", constant(1.0), 0.2)
+	b := NewMultiMetricRubric()
+	b.AddMetric("format", constant(0.0), 0.4)
+	b.AddMetric("length", constant(0.5), 0.6)
+
+	tests := []struct {
+		name        string
+		merge       bool
+		wantNames   []string
+		wantWeights []float64
+		wantScores  []float64
+	}{
+		{
+			name:        "separate",
+			wantNames:   []string{"a/correct", "a/format", "b/format", "b/length"},
+			wantWeights: []float64{0.8, 0.2, 0.4, 0.6},
+			wantScores:  []float64{1.0, 1.0, 0.0, 0.5},
+		},
+		{
+			name:        "merged",
+			merge:       true,
+			wantNames:   []string{"a/correct", "format", "b/length"},
+			wantWeights: []float64{0.8, 0.3, 0.6},
+			wantScores:  []float64{1.0, 0.5, 0.5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			group := NewRubricGroup(map[string]Rubric{"a": a, "b": b}, tt.merge)
+
+			if got := group.GetRewardFuncNames(); !reflect.DeepEqual(got, tt.wantNames) {
+				t.Errorf("GetRewardFuncNames() = %v, want %v", got, tt.wantNames)
+			}
+			weights := group.GetRewardWeights()
+			funcs := group.GetRewardFuncs()
+			if len(funcs) != len(tt.wantScores) || len(weights) != len(tt.wantWeights) {
+				t.Fatalf("Got %d funcs and %d weights, want %d", len(funcs), len(weights), len(tt.wantScores))
+			}
+			for i, fn := range funcs {
+				if math.Abs(weights[i]-tt.wantWeights[i]) > 1e-9 {
+					t.Errorf("Weight %d = %v, want %v", i, weights[i], tt.wantWeights[i])
+				}
+				score, err := fn(context.Background(), "", "")
+				if err != nil {
+					t.Fatalf("Reward func %d failed: %v", i, err)
+				}
+				if math.Abs(score-tt.wantScores[i]) > 1e-9 {
+					t.Errorf("Reward func %d = %v, want %v", i, score, tt.wantScores[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRubricGroup_Aggregation(t *testing.T) {
+	group := NewRubricGroup(map[string]Rubric{
+		"a": newStaticRubric([]float64{0.5}, []float64{1.0}),
+		"b": newStaticRubric([]float64{0.8}, []float64{3.0}),
+	}, false)
+
+	tests := []struct {
+		strategy AggregationStrategy
+		expected float64
+	}{
+		{"", 0.725}, // Weighted mean by default: (0.5*1 + 0.8*3) / 4
+		{WeightedMean, 0.725},
+		{Min, 0.5},
+		{Max, 0.8},
+		{Product, 0.4},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.strategy), func(t *testing.T) {
+			group.SetAggregation(tt.strategy)
+			score, err := group.ComputeReward(context.Background(), "x", "x")
+			if err != nil {
+				t.Fatalf("ComputeReward failed: %v", err)
+			}
+			if math.Abs(score-tt.expected) > 1e-9 {
+				t.Errorf("ComputeReward() = %v, want %v", score, tt.expected)
+			}
+		})
+	}
+
+	group.SetAggregation("median")
+	if _, err := group.ComputeReward(context.Background(), "x", "x"); err == nil {
+		t.Error("Expected an error for an unknown strategy")
+	}
+}
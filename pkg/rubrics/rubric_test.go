@@ -0,0 +1,284 @@
+package rubrics
+
+import (
+	"context"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/rizome-dev/go-verifiers/pkg/parsers"
+	"github.com/rizome-dev/go-verifiers/pkg/types"
+)
+
+func TestMultiMetricRubric_WeightsAndNormalization(t *testing.T) {
+	rubric := NewMultiMetricRubric()
+	if len(rubric.GetRewardFuncs()) != 0 || len(rubric.GetRewardWeights()) != 0 {
+		t.Fatalf("Expected a new rubric to have no metrics, got %d funcs", len(rubric.GetRewardFuncs()))
+	}
+
+	constant := func(score float64) types.RewardFunc {
+		return func(ctx context.Context, parsed, groundTruth string) (float64, error) {
+			return score, nil
+		}
+	}
+	rubric.AddMetric("correct", constant(1.0), 3.0)
+	rubric.AddMetric("format", constant(0.0), 1.0)
+
+	if want := map[string]float64{"correct": 3.0, "format": 1.0}; !reflect.DeepEqual(rubric.Weights(), want) {
+		t.Errorf("Weights() = %v, want %v", rubric.Weights(), want)
+	}
+
+	before, _ := rubric.ComputeReward(context.Background(), "", "")
+	rubric.NormalizeWeights()
+	after, _ := rubric.ComputeReward(context.Background(), "", "")
+
+	if want := map[string]float64{"correct": 0.75, "format": 0.25}; !reflect.DeepEqual(rubric.Weights(), want) {
+		t.Errorf("Weights() after NormalizeWeights = %v, want %v", rubric.Weights(), want)
+	}
+	if before != 0.75 || after != before {
+		t.Errorf("Expected score 0.75 before and after normalizing, got %v and %v", before, after)
+	}
+}
+
+func TestMultiMetricRubrics_ScoreOnlyTheirMetrics(t *testing.T) {
+	mathRubric, err := NewMathRubric()
+	if err != nil {
+		t.Fatalf("NewMathRubric failed: %v", err)
+	}
+
+	parser, err := parsers.NewXMLParser([]interface{}{"think", []string{"tool", "answer"}}, "answer")
+	if err != nil {
+		t.Fatalf("NewXMLParser failed: %v", err)
+	}
+	envParser, err := parsers.NewXMLParser([]interface{}{"result"}, "result")
+	if err != nil {
+		t.Fatalf("NewXMLParser failed: %v", err)
+	}
+	toolRubric, err := NewToolRubric(nil, parser, envParser)
+	if err != nil {
+		t.Fatalf("NewToolRubric failed: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		rubric      *MultiMetricRubric
+		wantWeights map[string]float64
+		response    string
+		wantScore   float64
+	}{
+		{
+			name:        "math",
+			rubric:      mathRubric.MultiMetricRubric,
+			wantWeights: map[string]float64{"correct_answer": 0.8, "format": 0.2},
+			response:    "<think>\n2 + 2\n</think>\n<answer>\n4\n</answer>",
+			wantScore:   1.0,
+		},
+		{
+			name:        "tool",
+			rubric:      toolRubric.MultiMetricRubric,
+			wantWeights: map[string]float64{"correct_answer": 0.6, "format": 0.2, "tool_usage": 0.2},
+			response:    "<answer>4</answer>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rubric.Weights(); !reflect.DeepEqual(got, tt.wantWeights) {
+				t.Errorf("Weights() = %v, want %v", got, tt.wantWeights)
+			}
+			if len(tt.rubric.GetRewardFuncs()) != len(tt.wantWeights) {
+				t.Errorf("Expected %d reward funcs, got %d", len(tt.wantWeights), len(tt.rubric.GetRewardFuncs()))
+			}
+
+			// The score is the weighted mean of the breakdown, with no hidden metric
+			breakdown, err := tt.rubric.ComputeRewardBreakdown(context.Background(), tt.response, "4")
+			if err != nil {
+				t.Fatalf("ComputeRewardBreakdown failed: %v", err)
+			}
+			want, total := 0.0, 0.0
+			for name, weight := range tt.wantWeights {
+				want += breakdown[name] * weight
+				total += weight
+			}
+			want /= total
+
+			score, err := tt.rubric.ComputeReward(context.Background(), tt.response, "4")
+			if err != nil {
+				t.Fatalf("ComputeReward failed: %v", err)
+			}
+			if math.Abs(score-want) > 1e-9 {
+				t.Errorf("ComputeReward() = %v, want weighted mean of metrics %v", score, want)
+			}
+			if tt.wantScore != 0 && math.Abs(score-tt.wantScore) > 1e-9 {
+				t.Errorf("ComputeReward() = %v, want %v", score, tt.wantScore)
+			}
+		})
+	}
+}
+
+func TestMultiMetricRubric_IsolatesFailingMetrics(t *testing.T) {
+	judgeDown := errors.New("judge unavailable")
+	constant := func(score float64, err error) types.RewardFunc {
+		return func(ctx context.Context, parsed, groundTruth string) (float64, error) {
+			return score, err
+		}
+	}
+
+	rubric := NewMultiMetricRubric()
+	rubric.AddMetric("correct", constant(1.0, nil), 0.6)
+	rubric.AddMetric("judge", constant(0.0, judgeDown), 0.3)
+	rubric.AddMetric("format", constant(0.5, nil), 0.2)
+
+	score, err := rubric.ComputeReward(context.Background(), "", "")
+	if err != nil {
+		t.Fatalf("ComputeReward failed: %v", err)
+	}
+	// Weighted over the surviving metrics only: (0.6*1.0 + 0.2*0.5) / 0.8
+	if want := 0.875; math.Abs(score-want) > 1e-9 {
+		t.Errorf("ComputeReward() = %v, want %v", score, want)
+	}
+	if got := rubric.LastFailedMetrics(); !reflect.DeepEqual(got, []string{"judge"}) {
+		t.Errorf("LastFailedMetrics() = %v, want [judge]", got)
+	}
+
+	_, failed, _ := rubric.ComputeRewardWithErrors(context.Background(), "", "")
+	if !errors.Is(failed["judge"], judgeDown) || len(failed) != 1 {
+		t.Errorf("ComputeRewardWithErrors() failed = %v, want only the judge error", failed)
+	}
+
+	// BaseRubric stays strict
+	strict := NewBaseRubric()
+	strict.rewardFuncs = []types.RewardFunc{constant(1.0, nil), constant(0.0, judgeDown)}
+	strict.rewardWeights = []float64{0.5, 0.5}
+	if _, err := strict.ComputeReward(context.Background(), "", ""); !errors.Is(err, judgeDown) {
+		t.Errorf("Expected BaseRubric to return the metric error, got %v", err)
+	}
+
+	// Every metric failing is an error
+	allFailing := NewMultiMetricRubric()
+	allFailing.AddMetric("judge", constant(0.0, judgeDown), 1.0)
+	if _, err := allFailing.ComputeReward(context.Background(), "", ""); !errors.Is(err, judgeDown) {
+		t.Errorf("Expected an error when every metric fails, got %v", err)
+	}
+}
+
+func TestMultiMetricRubric_ComputeRewardWithBreakdown(t *testing.T) {
+	calls := make(map[string]int)
+	counted := func(name string, score float64, err error) types.RewardFunc {
+		return func(ctx context.Context, parsed, groundTruth string) (float64, error) {
+			calls[name]++
+			return score, err
+		}
+	}
+
+	rubric := NewMultiMetricRubric()
+	rubric.AddMetric("correct", counted("correct", 1.0, nil), 0.5)
+	rubric.AddMetric("judge", counted("judge", 0.0, errors.New("judge unavailable")), 0.3)
+	rubric.AddMetric("format", counted("format_a", 1.0, nil), 0.1)
+	rubric.AddMetric("format", counted("format_b", 0.0, nil), 0.3)
+
+	score, breakdown, err := rubric.ComputeRewardWithBreakdown(context.Background(), "", "")
+	if err != nil {
+		t.Fatalf("ComputeRewardWithBreakdown failed: %v", err)
+	}
+
+	// Each metric runs once for both the reward and the breakdown
+	wantCalls := map[string]int{"correct": 1, "judge": 1, "format_a": 1, "format_b": 1}
+	if !reflect.DeepEqual(calls, wantCalls) {
+		t.Errorf("Metric calls = %v, want %v", calls, wantCalls)
+	}
+
+	// The failed judge is left out; both format metrics combine by weight
+	wantBreakdown := map[string]float64{"correct": 1.0, "format": 0.25}
+	if len(breakdown) != len(wantBreakdown) {
+		t.Fatalf("Breakdown = %v, want %v", breakdown, wantBreakdown)
+	}
+	for name, want := range wantBreakdown {
+		if math.Abs(breakdown[name]-want) > 1e-9 {
+			t.Errorf("Breakdown[%s] = %v, want %v", name, breakdown[name], want)
+		}
+	}
+
+	// (0.5*1.0 + 0.1*1.0 + 0.3*0.0) / 0.9
+	if want := 0.6 / 0.9; math.Abs(score-want) > 1e-9 {
+		t.Errorf("ComputeRewardWithBreakdown() score = %v, want %v", score, want)
+	}
+	if got := rubric.LastFailedMetrics(); !reflect.DeepEqual(got, []string{"judge"}) {
+		t.Errorf("LastFailedMetrics() = %v, want [judge]", got)
+	}
+
+	// The breakdown alone also tolerates the failed judge
+	if _, err := rubric.ComputeRewardBreakdown(context.Background(), "", ""); err != nil {
+		t.Errorf("ComputeRewardBreakdown failed on a partial failure: %v", err)
+	}
+}
+
+func TestRubric_RewardFuncNamesAlign(t *testing.T) {
+	mathRubric, err := NewMathRubric()
+	if err != nil {
+		t.Fatalf("NewMathRubric failed: %v", err)
+	}
+	codeMathRubric, err := NewCodeMathRubric()
+	if err != nil {
+		t.Fatalf("NewCodeMathRubric failed: %v", err)
+	}
+	parser, err := parsers.NewXMLParser([]interface{}{"think", []string{"tool", "answer"}}, "answer")
+	if err != nil {
+		t.Fatalf("NewXMLParser failed: %v", err)
+	}
+	envParser, err := parsers.NewXMLParser([]interface{}{"result"}, "result")
+	if err != nil {
+		t.Fatalf("NewXMLParser failed: %v", err)
+	}
+	toolRubric, err := NewToolRubricWithEfficiency(nil, parser, envParser)
+	if err != nil {
+		t.Fatalf("NewToolRubricWithEfficiency failed: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		rubric      Rubric
+		wantNames   []string
+		wantWeights []float64
+	}{
+		{"base", NewBaseRubric(), []string{"reward"}, []float64{1.0}},
+		{"math", mathRubric, []string{"correct_answer", "format"}, []float64{0.8, 0.2}},
+		{"code math", codeMathRubric, []string{"correct_answer", "code_execution"}, []float64{0.7, 0.3}},
+		{"tool", toolRubric, []string{"correct_answer", "format", "tool_usage", "tool_efficiency"}, []float64{0.6, 0.2, 0.2, 0.2}},
+		{
+			"group",
+			NewRubricGroup(map[string]Rubric{"math": mathRubric, "exact": NewBaseRubric()}, false),
+			[]string{"exact/reward", "math/correct_answer", "math/format"},
+			[]float64{1.0, 0.8, 0.2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			names := tt.rubric.GetRewardFuncNames()
+			if len(tt.rubric.GetRewardFuncs()) != len(names) {
+				t.Fatalf("Got %d funcs but %d names", len(tt.rubric.GetRewardFuncs()), len(names))
+			}
+			if !reflect.DeepEqual(names, tt.wantNames) {
+				t.Errorf("Names = %v, want %v", names, tt.wantNames)
+			}
+			if !reflect.DeepEqual(tt.rubric.GetRewardWeights(), tt.wantWeights) {
+				t.Errorf("Weights = %v, want %v", tt.rubric.GetRewardWeights(), tt.wantWeights)
+			}
+		})
+	}
+
+	// The i-th name scores the i-th function: with a correct answer and no code,
+	// only correct_answer pays out
+	funcs := codeMathRubric.GetRewardFuncs()
+	for i, name := range codeMathRubric.GetRewardFuncNames() {
+		score, err := funcs[i](context.Background(), "<answer>4</answer>", "4")
+		if err != nil {
+			t.Fatalf("%s failed: %v", name, err)
+		}
+		if (name == "correct_answer") != (score == 1.0) {
+			t.Errorf("%s scored %v", name, score)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package rubrics
+
+import (
+	"context"
+	"strings"
+
+	"github.com/rizome-dev/go-verifiers/pkg/types"
+)
+
+// SetMatchRubric compares list answers such as "2, 3, 5" regardless of order.
+// Both answers are split on the delimiter and each item is normalized: case and
+// surrounding whitespace and periods are ignored, and brackets around the whole
+// list, as in "[2, 3, 5]" or "{2, 3, 5}", are dropped. Items are compared as
+// multisets, so duplicates count, unless SetIgnoreDuplicates compares them as sets.
+type SetMatchRubric struct {
+	*BaseRubric
+	delimiter        string
+	partial          bool
+	ignoreDuplicates bool
+}
+
+// NewSetMatchRubric creates a rubric that splits answers on delimiter ("," when
+// empty). It scores 1.0 when the items match; otherwise partial selects the
+// Jaccard similarity of the items instead of 0.0.
+func NewSetMatchRubric(delimiter string, partial bool) *SetMatchRubric {
+	if delimiter == "" {
+		delimiter = ","
+	}
+	rubric := &SetMatchRubric{
+		BaseRubric: NewBaseRubric(),
+		delimiter:  delimiter,
+		partial:    partial,
+	}
+
+	// Replace the default exact match with an unordered comparison
+	setMatchFunc := func(ctx context.Context, parsed, groundTruth string) (float64, error) {
+		return rubric.score(parsed, groundTruth), nil
+	}
+
+	rubric.rewardFuncs = []types.RewardFunc{setMatchFunc}
+	rubric.rewardWeights = []float64{1.0}
+
+	return rubric
+}
+
+// SetIgnoreDuplicates controls whether repeated items are ignored, comparing the
+// answers as sets rather than multisets
+func (r *SetMatchRubric) SetIgnoreDuplicates(ignore bool) {
+	r.ignoreDuplicates = ignore
+}
+
+// score compares the item counts of both answers. The multiset Jaccard similarity
+// is the sum of the smaller counts over the sum of the larger counts per item.
+func (r *SetMatchRubric) score(parsed, groundTruth string) float64 {
+	got := r.items(parsed)
+	want := r.items(groundTruth)
+
+	intersection, union := 0, 0
+	for item, wantCount := range want {
+		gotCount := got[item]
+		intersection += min(gotCount, wantCount)
+		union += max(gotCount, wantCount)
+	}
+	for item, gotCount := range got {
+		if _, ok := want[item]; !ok {
+			union += gotCount
+		}
+	}
+
+	if intersection == union {
+		return 1.0
+	}
+	if !r.partial {
+		return 0.0
+	}
+	return float64(intersection) / float64(union)
+}
+
+// items splits an answer and counts its normalized items
+func (r *SetMatchRubric) items(answer string) map[string]int {
+	answer = strings.TrimSpace(answer)
+	for _, brackets := range []string{"[]", "{}", "()"} {
+		if len(answer) >= 2 && answer[0] == brackets[0] && answer[len(answer)-1] == brackets[1] {
+			answer = answer[1 : len(answer)-1]
+			break
+		}
+	}
+
+	counts := make(map[string]int)
+	for _, item := range strings.Split(answer, r.delimiter) {
+		item = strings.ToLower(strings.Join(strings.Fields(strings.Trim(item, " \t\n.")), " "))
+		if item == "" {
+			continue
+		}
+		if r.ignoreDuplicates {
+			counts[item] = 1
+		} else {
+			counts[item]++
+		}
+	}
+	return counts
+}
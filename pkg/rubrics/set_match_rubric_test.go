@@ -0,0 +1,44 @@
+package rubrics
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+func TestSetMatchRubric_ComputeReward(t *testing.T) {
+	tests := []struct {
+		name             string
+		delimiter        string
+		partial          bool
+		ignoreDuplicates bool
+		parsed           string
+		groundTruth      string
+		expected         float64
+	}{
+		{name: "reordered", parsed: "5, 3, 2", groundTruth: "2, 3, 5", expected: 1.0},
+		{name: "case, spacing and brackets", parsed: "[ Red,blue ,  GREEN ]", groundTruth: "green, red, blue", expected: 1.0},
+		{name: "custom delimiter", delimiter: ";", parsed: "b; a", groundTruth: "a;b", expected: 1.0},
+		{name: "missing item", parsed: "2, 3", groundTruth: "2, 3, 5", expected: 0.0},
+		{name: "duplicates count in multisets", parsed: "2, 2, 3", groundTruth: "2, 3", expected: 0.0},
+		{name: "duplicates ignored in sets", ignoreDuplicates: true, parsed: "2, 2, 3", groundTruth: "3, 2", expected: 1.0},
+		{name: "partial jaccard", partial: true, parsed: "2, 3, 7", groundTruth: "2, 3, 5", expected: 0.5},
+		{name: "partial multiset jaccard", partial: true, parsed: "2, 2, 3", groundTruth: "2, 2, 2, 3", expected: 0.75},
+		{name: "partial no overlap", partial: true, parsed: "1", groundTruth: "2, 3", expected: 0.0},
+	}
+
+	ctx := context.Background()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rubric := NewSetMatchRubric(tt.delimiter, tt.partial)
+			rubric.SetIgnoreDuplicates(tt.ignoreDuplicates)
+			got, err := rubric.ComputeReward(ctx, tt.parsed, tt.groundTruth)
+			if err != nil {
+				t.Fatalf("ComputeReward() error = %v", err)
+			}
+			if math.Abs(got-tt.expected) > 1e-9 {
+				t.Errorf("ComputeReward(%q, %q) = %v, want %v", tt.parsed, tt.groundTruth, got, tt.expected)
+			}
+		})
+	}
+}
@@ -2,6 +2,8 @@ package rubrics
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"strings"
@@ -58,9 +60,7 @@ func NewSmolaToolRubric(toolList []tools.Tool, parser *parsers.SmolaParser, envP
 	}
 
 	// Add format reward function
-	formatFunc := func(ctx context.Context, response, groundTruth string) (float64, error) {
-		return rubric.parser.FollowsFormat(response), nil
-	}
+	formatFunc := NewFormatReward(parser)
 
 	// Add metrics with weights
 	rubric.AddMetric("correct_answer", correctAnswerFunc, 0.7)
@@ -83,120 +83,83 @@ func (r *SmolaToolRubric) SetIncludeUsage(include bool) {
 	r.includeUsage = include
 }
 
-// createToolUsageFunc creates a reward function for specific tool usage
+// createToolUsageFunc creates a reward function for specific tool usage. It scores
+// the success rate of the tool's executions in the trace attached with WithToolTrace,
+// as SmolaToolEnv does during rollouts. A tool call in the response text says
+// nothing about whether it succeeded, so without a trace the metric is 0.
 func (r *SmolaToolRubric) createToolUsageFunc(toolName string) types.RewardFunc {
 	return func(ctx context.Context, response, groundTruth string) (float64, error) {
-		// Count successful uses of this specific tool
-		toolCalls := r.extractToolCalls(response)
-		
-		successCount := 0
-		totalCount := 0
-		
-		for _, toolJSON := range toolCalls {
-			var toolCall map[string]interface{}
-			if err := json.Unmarshal([]byte(toolJSON), &toolCall); err != nil {
-				continue
-			}
-			
-			if name, ok := toolCall["name"].(string); ok && name == toolName {
-				totalCount++
-				// Check if this tool call appears to be successful
-				// (In practice, we'd check execution results)
-				if toolCall["args"] != nil {
-					successCount++
-				}
-			}
-		}
-		
-		if totalCount > 0 {
-			return float64(successCount) / float64(totalCount), nil
-		}
-		
-		// No usage of this tool
-		return 0.0, nil
+		trace, _ := ToolTrace(ctx)
+		return toolSuccessRate(trace, toolName), nil
 	}
 }
 
-// extractToolCalls extracts tool JSON from Smola-formatted response
-func (r *SmolaToolRubric) extractToolCalls(response string) []string {
-	var toolCalls []string
-	
-	// Parse with Smola parser
-	parsed, err := r.parser.ParseSmola(response, true)
-	if err != nil {
-		return toolCalls
-	}
-	
-	// Look for tool fields
-	for field, content := range parsed.Fields {
-		if field == "tool" && content != "" {
-			toolCalls = append(toolCalls, content)
-		}
-	}
-	
-	// Also try to extract from raw response in case of multiple calls
-	parts := strings.Split(response, "<tool>")
-	for i := 1; i < len(parts); i++ {
-		if endIdx := strings.Index(parts[i], "</tool>"); endIdx > 0 {
-			toolJSON := strings.TrimSpace(parts[i][:endIdx])
-			if toolJSON != "" && !contains(toolCalls, toolJSON) {
-				toolCalls = append(toolCalls, toolJSON)
-			}
-		}
-	}
-	
-	return toolCalls
+// ComputeRewardWithTrace computes reward with execution trace. Each tool usage
+// metric scores the success rate of that tool's executions in the trace.
+func (r *SmolaToolRubric) ComputeRewardWithTrace(ctx context.Context, parsed string, groundTruth string, trace []ToolExecution) (float64, error) {
+	return r.ComputeReward(WithToolTrace(ctx, trace), parsed, groundTruth)
 }
 
-// ComputeRewardWithTrace computes reward with execution trace
-func (r *SmolaToolRubric) ComputeRewardWithTrace(ctx context.Context, parsed string, groundTruth string, trace []ToolExecution) (float64, error) {
-	// Base reward computation
-	baseReward, err := r.ComputeReward(ctx, parsed, groundTruth)
-	if err != nil {
-		return 0.0, err
-	}
-	
-	// If we have execution trace, adjust tool usage scores
-	if len(trace) > 0 && r.includeUsage {
-		// Count successful executions per tool
-		toolSuccess := make(map[string]float64)
-		toolTotal := make(map[string]float64)
-		
-		for _, exec := range trace {
-			toolTotal[exec.ToolName]++
-			if exec.Success {
-				toolSuccess[exec.ToolName]++
-			}
+// toolSuccessRate returns the fraction of successful executions of toolName in the
+// trace, or 0 if the tool was never executed
+func toolSuccessRate(trace []ToolExecution, toolName string) float64 {
+	total, success := 0, 0
+	for _, exec := range trace {
+		if exec.ToolName != toolName {
+			continue
 		}
-		
-		// Update tool usage metrics based on actual execution
-		for toolName, total := range toolTotal {
-			if total > 0 {
-				successRate := toolSuccess[toolName] / total
-				// This would update the specific tool metric
-				// In practice, we'd need a way to update individual metrics
-				_ = successRate
-			}
+		total++
+		if exec.Success {
+			success++
 		}
 	}
-	
-	return baseReward, nil
+	if total == 0 {
+		return 0.0
+	}
+	return float64(success) / float64(total)
 }
 
 // ToolExecution represents a tool execution in the trace
 type ToolExecution struct {
 	ToolName string
 	Args     map[string]interface{}
+	ArgsHash string // Canonical hash of Args, used to detect repeated calls
 	Result   string
 	Success  bool
 }
 
-// contains checks if a string is in a slice
-func contains(slice []string, item string) bool {
-	for _, s := range slice {
-		if s == item {
-			return true
-		}
+// NewToolExecution creates a trace entry, hashing the arguments
+func NewToolExecution(toolName string, args map[string]interface{}, result string, success bool) ToolExecution {
+	return ToolExecution{
+		ToolName: toolName,
+		Args:     args,
+		ArgsHash: HashToolArgs(args),
+		Result:   result,
+		Success:  success,
 	}
-	return false
-}
\ No newline at end of file
+}
+
+// HashToolArgs returns a stable hash of tool arguments. Map keys are serialized
+// in sorted order, so equal arguments always hash the same.
+func HashToolArgs(args map[string]interface{}) string {
+	data, err := json.Marshal(args)
+	if err != nil {
+		data = []byte(fmt.Sprintf("%v", args))
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+// toolTraceKey is the context key for the tool execution trace
+type toolTraceKey struct{}
+
+// WithToolTrace attaches a tool execution trace to the context passed to reward functions
+func WithToolTrace(ctx context.Context, trace []ToolExecution) context.Context {
+	return context.WithValue(ctx, toolTraceKey{}, trace)
+}
+
+// ToolTrace returns the tool execution trace attached by WithToolTrace
+func ToolTrace(ctx context.Context) ([]ToolExecution, bool) {
+	trace, ok := ctx.Value(toolTraceKey{}).([]ToolExecution)
+	return trace, ok
+}
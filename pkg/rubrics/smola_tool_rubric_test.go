@@ -0,0 +1,56 @@
+package rubrics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rizome-dev/go-verifiers/pkg/parsers"
+	"github.com/rizome-dev/go-verifiers/pkg/tools"
+)
+
+func TestSmolaToolRubric_ComputeRewardWithTrace(t *testing.T) {
+	parser, err := parsers.NewSmolaParser([]interface{}{"think", "tool", "answer"})
+	if err != nil {
+		t.Fatalf("NewSmolaParser failed: %v", err)
+	}
+	envParser, err := parsers.NewXMLParser([]interface{}{"result"}, "result")
+	if err != nil {
+		t.Fatalf("NewXMLParser failed: %v", err)
+	}
+
+	rubric, err := NewSmolaToolRubric([]tools.Tool{tools.NewCalculator()}, parser, envParser)
+	if err != nil {
+		t.Fatalf("NewSmolaToolRubric failed: %v", err)
+	}
+
+	response := "<think>\nthe tool said 4\n</think>\n<answer>\n4\n</answer>"
+	args := map[string]interface{}{"expression": "2 + 2"}
+	ctx := context.Background()
+
+	succeeded, err := rubric.ComputeRewardWithTrace(ctx, response, "4", []ToolExecution{
+		NewToolExecution("calculate", args, "4", true),
+	})
+	if err != nil {
+		t.Fatalf("ComputeRewardWithTrace failed: %v", err)
+	}
+
+	failed, err := rubric.ComputeRewardWithTrace(ctx, response, "4", []ToolExecution{
+		NewToolExecution("calculate", args, "Error: bad input", false),
+	})
+	if err != nil {
+		t.Fatalf("ComputeRewardWithTrace failed: %v", err)
+	}
+
+	if failed >= succeeded {
+		t.Errorf("Expected failed execution to score lower: failed=%v, succeeded=%v", failed, succeeded)
+	}
+
+	// A tool call in the text is not evidence of success without a trace
+	breakdown, err := rubric.ComputeRewardBreakdown(ctx, "<tool>{\"name\": \"calculate\", \"args\": {\"expression\": \"2 +* 2\"}}</tool>", "4")
+	if err != nil {
+		t.Fatalf("ComputeRewardBreakdown failed: %v", err)
+	}
+	if breakdown["calculate_usage"] != 0.0 {
+		t.Errorf("Expected no usage credit without a trace, got %v", breakdown["calculate_usage"])
+	}
+}
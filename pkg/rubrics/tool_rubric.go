@@ -15,6 +15,8 @@ type ToolRubric struct {
 	tools     []tools.Tool
 	parser    *parsers.XMLParser
 	envParser *parsers.XMLParser
+
+	hallucinationPenalty float64
 }
 
 // NewToolRubric creates a new tool rubric
@@ -24,6 +26,8 @@ func NewToolRubric(toolList []tools.Tool, parser *parsers.XMLParser, envParser *
 		tools:            toolList,
 		parser:           parser,
 		envParser:        envParser,
+
+		hallucinationPenalty: DefaultHallucinationPenalty,
 	}
 
 	// Add correct answer reward function
@@ -48,11 +52,17 @@ func NewToolRubric(toolList []tools.Tool, parser *parsers.XMLParser, envParser *
 
 	// Add format reward function
 	formatFunc := func(ctx context.Context, response, groundTruth string) (float64, error) {
+		if raw, ok := RawResponse(ctx); ok {
+			response = raw
+		}
 		return rubric.evaluateFormat(response)
 	}
 
 	// Add tool usage reward function
 	toolUsageFunc := func(ctx context.Context, response, groundTruth string) (float64, error) {
+		if raw, ok := RawResponse(ctx); ok {
+			response = raw
+		}
 		return rubric.evaluateToolUsage(response)
 	}
 
@@ -64,6 +74,64 @@ func NewToolRubric(toolList []tools.Tool, parser *parsers.XMLParser, envParser *
 	return rubric, nil
 }
 
+// NewToolRubricWithEfficiency creates a tool rubric with an additional tool_efficiency
+// metric that penalizes repeated and failed tool calls in the execution trace
+func NewToolRubricWithEfficiency(toolList []tools.Tool, parser *parsers.XMLParser, envParser *parsers.XMLParser) (*ToolRubric, error) {
+	rubric, err := NewToolRubric(toolList, parser, envParser)
+	if err != nil {
+		return nil, err
+	}
+
+	efficiencyFunc := func(ctx context.Context, response, groundTruth string) (float64, error) {
+		trace, _ := ToolTrace(ctx)
+		return ToolEfficiency(trace), nil
+	}
+	rubric.AddMetric("tool_efficiency", efficiencyFunc, 0.2)
+
+	return rubric, nil
+}
+
+// ComputeRewardWithTrace computes the reward with access to the tool execution trace
+func (r *ToolRubric) ComputeRewardWithTrace(ctx context.Context, parsed string, groundTruth string, trace []ToolExecution) (float64, error) {
+	return r.ComputeReward(WithToolTrace(ctx, trace), parsed, groundTruth)
+}
+
+// ComputeRewardDetail reports whether the response has an <answer>, whether that
+// answer is correct, and the format score. ToolEnv passes the extracted answer as
+// parsed, so the raw response attached with WithRawResponse is checked for the
+// answer tag as well.
+func (r *ToolRubric) ComputeRewardDetail(ctx context.Context, parsed string, groundTruth string) (RewardDetail, error) {
+	texts := []string{parsed}
+	if raw, ok := RawResponse(ctx); ok {
+		texts = append(texts, raw)
+	}
+
+	return r.rewardDetail(ctx, parsed, groundTruth, hasAnswerField(r.parser, texts...))
+}
+
+// ToolEfficiency scores a trace as unique successful calls divided by total calls.
+// Calls are unique by tool name and argument hash. An empty trace scores 1.0.
+func ToolEfficiency(trace []ToolExecution) float64 {
+	if len(trace) == 0 {
+		return 1.0
+	}
+
+	seen := make(map[string]bool)
+	uniqueSuccessful := 0
+	for _, exec := range trace {
+		if !exec.Success {
+			continue
+		}
+		key := exec.ToolName + ":" + exec.ArgsHash
+		if !seen[key] {
+			seen[key] = true
+			uniqueSuccessful++
+		}
+	}
+
+	return float64(uniqueSuccessful) / float64(len(trace))
+}
+
 // evaluateFormat checks if the response follows the expected XML format
 func (r *ToolRubric) evaluateFormat(response string) (float64, error) {
 	// Split response into messages if it contains multiple
@@ -84,6 +152,12 @@ func (r *ToolRubric) evaluateFormat(response string) (float64, error) {
 		// Check for either tool or answer tags
 		hasToolTag := strings.Contains(msg, "<tool>") && strings.Contains(msg, "</tool>")
 		hasAnswerTag := strings.Contains(msg, "<answer>") && strings.Contains(msg, "</answer>")
+		if !hasAnswerTag && r.parser.AllowsUnclosed() {
+			// A stop sequence such as "</answer>" is cut from the response
+			if parsed, err := r.parser.ParseXML(msg, true); err == nil {
+				_, hasAnswerTag = parsed.Fields["answer"]
+			}
+		}
 		
 		if hasToolTag || hasAnswerTag {
 			score += 0.4
@@ -118,46 +192,83 @@ func (r *ToolRubric) evaluateFormat(response string) (float64, error) {
 	return 0.0, nil
 }
 
-// evaluateToolUsage checks if tools are used correctly
-func (r *ToolRubric) evaluateToolUsage(response string) (float64, error) {
-	// Extract all tool calls from the response
-	toolCalls := r.extractToolCalls(response)
-	
-	if len(toolCalls) == 0 {
-		// No tool usage - might be okay for some problems
-		return 0.5, nil
-	}
-	
-	validCalls := 0
-	for _, toolJSON := range toolCalls {
+// DefaultHallucinationPenalty is how much the tool_usage score drops per share of
+// calls naming a tool that does not exist
+const DefaultHallucinationPenalty = 1.0
+
+// ToolCallCounts classifies the tool calls found in a response
+type ToolCallCounts struct {
+	Valid        int // Well-formed calls to a known tool
+	Hallucinated int // Well-formed calls naming a tool that is not in the tool set
+	Malformed    int // Calls that are not valid JSON or lack a name or args
+}
+
+// Total returns the number of tool calls
+func (c ToolCallCounts) Total() int {
+	return c.Valid + c.Hallucinated + c.Malformed
+}
+
+// SetHallucinationPenalty sets how much the tool_usage score drops per share of
+// hallucinated tool calls. 0 scores them like malformed calls.
+func (r *ToolRubric) SetHallucinationPenalty(penalty float64) {
+	r.hallucinationPenalty = penalty
+}
+
+// CountToolCalls classifies the <tool> calls in response as valid, hallucinated
+// or malformed
+func (r *ToolRubric) CountToolCalls(response string) ToolCallCounts {
+	var counts ToolCallCounts
+	for _, toolJSON := range r.extractToolCalls(response) {
 		// Try to parse the tool call
 		var toolCall map[string]interface{}
 		if err := json.Unmarshal([]byte(toolJSON), &toolCall); err != nil {
+			counts.Malformed++
 			continue
 		}
-		
+
 		// Check if it has required fields
-		if name, ok := toolCall["name"].(string); ok && name != "" {
-			// Check if tool exists
-			toolExists := false
-			for _, tool := range r.tools {
-				if tool.Name() == name {
-					toolExists = true
-					break
-				}
-			}
-			
-			if toolExists && toolCall["args"] != nil {
-				validCalls++
+		name, _ := toolCall["name"].(string)
+		if name == "" || toolCall["args"] == nil {
+			counts.Malformed++
+			continue
+		}
+
+		// Check if tool exists
+		toolExists := false
+		for _, tool := range r.tools {
+			if tool.Name() == name {
+				toolExists = true
+				break
 			}
 		}
+		if toolExists {
+			counts.Valid++
+		} else {
+			counts.Hallucinated++
+		}
 	}
-	
-	// Calculate score based on valid tool usage
-	if validCalls > 0 {
-		return 1.0, nil
+	return counts
+}
+
+// evaluateToolUsage checks if tools are used correctly. Any valid call earns 1.0,
+// reduced by the hallucination penalty times the share of calls to unknown tools;
+// malformed calls alone earn 0 but are not penalized further.
+func (r *ToolRubric) evaluateToolUsage(response string) (float64, error) {
+	counts := r.CountToolCalls(response)
+
+	if counts.Total() == 0 {
+		// No tool usage - might be okay for some problems
+		return 0.5, nil
 	}
-	return 0.0, nil
+	if counts.Valid == 0 {
+		return 0.0, nil
+	}
+
+	score := 1.0 - r.hallucinationPenalty*float64(counts.Hallucinated)/float64(counts.Total())
+	if score < 0 {
+		score = 0
+	}
+	return score, nil
 }
 
 // extractToolCalls extracts all tool JSON calls from the response
@@ -0,0 +1,177 @@
+package rubrics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rizome-dev/go-verifiers/pkg/parsers"
+	"github.com/rizome-dev/go-verifiers/pkg/tools"
+)
+
+func TestToolEfficiency(t *testing.T) {
+	search := map[string]interface{}{"query": "go"}
+	other := map[string]interface{}{"query": "rust"}
+
+	tests := []struct {
+		name     string
+		trace    []ToolExecution
+		expected float64
+	}{
+		{name: "no tools used", trace: nil, expected: 1.0},
+		{
+			name: "all unique and successful",
+			trace: []ToolExecution{
+				NewToolExecution("search", search, "ok", true),
+				NewToolExecution("search", other, "ok", true),
+			},
+			expected: 1.0,
+		},
+		{
+			name: "duplicate call",
+			trace: []ToolExecution{
+				NewToolExecution("search", search, "ok", true),
+				NewToolExecution("search", map[string]interface{}{"query": "go"}, "ok", true),
+			},
+			expected: 0.5,
+		},
+		{
+			name: "failed call",
+			trace: []ToolExecution{
+				NewToolExecution("search", search, "ok", true),
+				NewToolExecution("calculator", nil, "Error: bad input", false),
+				NewToolExecution("search", other, "ok", true),
+				NewToolExecution("search", other, "ok", true),
+			},
+			expected: 0.5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ToolEfficiency(tt.trace); got != tt.expected {
+				t.Errorf("ToolEfficiency() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestToolRubric_ComputeRewardWithTrace(t *testing.T) {
+	parser, err := parsers.NewXMLParser([]interface{}{"think", []string{"tool", "answer"}}, "answer")
+	if err != nil {
+		t.Fatalf("NewXMLParser failed: %v", err)
+	}
+	envParser, err := parsers.NewXMLParser([]interface{}{"result"}, "result")
+	if err != nil {
+		t.Fatalf("NewXMLParser failed: %v", err)
+	}
+
+	rubric, err := NewToolRubricWithEfficiency(nil, parser, envParser)
+	if err != nil {
+		t.Fatalf("NewToolRubricWithEfficiency failed: %v", err)
+	}
+
+	call := NewToolExecution("search", map[string]interface{}{"query": "go"}, "ok", true)
+	ctx := context.Background()
+
+	clean, err := rubric.ComputeRewardWithTrace(ctx, "<answer>42</answer>", "42", []ToolExecution{call})
+	if err != nil {
+		t.Fatalf("ComputeRewardWithTrace failed: %v", err)
+	}
+	redundant, err := rubric.ComputeRewardWithTrace(ctx, "<answer>42</answer>", "42", []ToolExecution{call, call, call})
+	if err != nil {
+		t.Fatalf("ComputeRewardWithTrace failed: %v", err)
+	}
+
+	if redundant >= clean {
+		t.Errorf("Expected redundant calls to lower the reward: clean=%v redundant=%v", clean, redundant)
+	}
+}
+
+func TestToolRubric_ComputeRewardDetail(t *testing.T) {
+	parser, err := parsers.NewXMLParser([]interface{}{"think", []string{"tool", "answer"}}, "answer")
+	if err != nil {
+		t.Fatalf("NewXMLParser failed: %v", err)
+	}
+	envParser, err := parsers.NewXMLParser([]interface{}{"result"}, "result")
+	if err != nil {
+		t.Fatalf("NewXMLParser failed: %v", err)
+	}
+	rubric, err := NewToolRubric(nil, parser, envParser)
+	if err != nil {
+		t.Fatalf("NewToolRubric failed: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		raw         string
+		parsed      string // What ToolEnv passes: the extracted answer, or the whole response
+		wantPresent bool
+		wantCorrect bool
+	}{
+		{"no answer", "<think>\nI am not sure\n</think>", "<think>\nI am not sure\n</think>", false, false},
+		{"wrong answer", "<think>\nguess\n</think>\n<answer>\n5\n</answer>", "5", true, false},
+		{"correct answer", "<think>\nadd\n</think>\n<answer>\n4\n</answer>", "4", true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := WithRawResponse(context.Background(), tt.raw)
+			detail, err := rubric.ComputeRewardDetail(ctx, tt.parsed, "4")
+			if err != nil {
+				t.Fatalf("ComputeRewardDetail failed: %v", err)
+			}
+			if detail.AnswerPresent != tt.wantPresent || detail.AnswerCorrect != tt.wantCorrect {
+				t.Errorf("Got present=%v correct=%v, want present=%v correct=%v", detail.AnswerPresent, detail.AnswerCorrect, tt.wantPresent, tt.wantCorrect)
+			}
+		})
+	}
+}
+
+func TestToolRubric_HallucinatedToolCalls(t *testing.T) {
+	parser, err := parsers.NewXMLParser([]interface{}{"think", []string{"tool", "answer"}}, "answer")
+	if err != nil {
+		t.Fatalf("NewXMLParser failed: %v", err)
+	}
+	rubric, err := NewToolRubric([]tools.Tool{tools.NewCalculator()}, parser, nil)
+	if err != nil {
+		t.Fatalf("NewToolRubric failed: %v", err)
+	}
+
+	valid := `<tool>{"name": "calculate", "args": {"expression": "2 + 2"}}</tool>`
+	hallucinated := `<tool>{"name": "weather", "args": {"city": "Paris"}}</tool>`
+	malformed := `<tool>{"name": "calculate", "args": </tool>`
+
+	tests := []struct {
+		name     string
+		response string
+		counts   ToolCallCounts
+		expected float64
+	}{
+		{"valid", valid, ToolCallCounts{Valid: 1}, 1.0},
+		{"valid and hallucinated", valid + "\n---\n" + hallucinated, ToolCallCounts{Valid: 1, Hallucinated: 1}, 0.5},
+		{"valid and malformed", valid + "\n---\n" + malformed, ToolCallCounts{Valid: 1, Malformed: 1}, 1.0},
+		{"only hallucinated", hallucinated, ToolCallCounts{Hallucinated: 1}, 0.0},
+		{"only malformed", malformed, ToolCallCounts{Malformed: 1}, 0.0},
+		{"no calls", "<answer>4</answer>", ToolCallCounts{}, 0.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if counts := rubric.CountToolCalls(tt.response); counts != tt.counts {
+				t.Errorf("CountToolCalls() = %+v, want %+v", counts, tt.counts)
+			}
+			breakdown, err := rubric.ComputeRewardBreakdown(context.Background(), tt.response, "4")
+			if err != nil {
+				t.Fatalf("ComputeRewardBreakdown failed: %v", err)
+			}
+			if breakdown["tool_usage"] != tt.expected {
+				t.Errorf("tool_usage = %v, want %v", breakdown["tool_usage"], tt.expected)
+			}
+		})
+	}
+
+	rubric.SetHallucinationPenalty(0)
+	if score, _ := rubric.evaluateToolUsage(valid + hallucinated); score != 1.0 {
+		t.Errorf("Expected no penalty when disabled, got %v", score)
+	}
+}
@@ -8,16 +8,35 @@ import (
 	"strings"
 
 	"github.com/Knetic/govaluate"
+	"github.com/rizome-dev/go-verifiers/pkg/mathexpr"
+)
+
+// Magnitudes outside [sciLowerThreshold, sciUpperThreshold) are shown in scientific
+// notation when the calculator is created with scientific formatting
+const (
+	sciLowerThreshold = 1e-4
+	sciUpperThreshold = 1e15
 )
 
 // Calculator implements a mathematical expression evaluator
 type Calculator struct {
 	*BaseTool
+	precision  int  // Significant digits in results; 0 keeps full precision
+	scientific bool // Format very large and very small results in scientific notation
 }
 
 // NewCalculator creates a new calculator tool
 func NewCalculator() *Calculator {
+	return NewCalculatorWithOptions(0, false)
+}
+
+// NewCalculatorWithOptions creates a calculator that rounds results to precision
+// significant digits (0 keeps full precision). With sci, results whose magnitude is
+// at least 1e15 or below 1e-4 are returned as strings in scientific notation.
+func NewCalculatorWithOptions(precision int, sci bool) *Calculator {
 	calc := &Calculator{
+		precision:  precision,
+		scientific: sci,
 		BaseTool: NewBaseTool(
 			"calculate",
 			"Evaluate mathematical expressions. Supports basic arithmetic, trigonometry, logarithms, and more.",
@@ -63,241 +82,94 @@ func (c *Calculator) execute(ctx context.Context, args map[string]interface{}) (
 	}
 	
 	// Preprocess the expression to handle common mathematical functions
-	processed := preprocessExpression(expr)
+	processed := mathexpr.Preprocess(expr)
 	
 	// Create expression evaluator
-	expression, err := govaluate.NewEvaluableExpression(processed)
+	expression, err := govaluate.NewEvaluableExpressionWithFunctions(processed, mathFunctions)
 	if err != nil {
 		// Try simpler evaluation for basic expressions
 		result, evalErr := evaluateSimple(expr)
 		if evalErr != nil {
 			return nil, fmt.Errorf("invalid expression: %v", err)
 		}
-		return result, nil
-	}
-	
-	// Define mathematical functions and constants
-	parameters := map[string]interface{}{
-		"pi":   math.Pi,
-		"e":    math.E,
-		"sqrt": sqrt,
-		"sin":  sin,
-		"cos":  cos,
-		"tan":  tan,
-		"log":  log,
-		"ln":   ln,
-		"exp":  exp,
-		"pow":  pow,
-		"abs":  abs,
-		"ceil": ceil,
-		"floor": floor,
-		"round": round,
+		if err := mathexpr.CheckFinite(result); err != nil {
+			return nil, err
+		}
+		return c.formatNumber(result), nil
 	}
 	
-	// Evaluate the expression
-	result, err := expression.Evaluate(parameters)
+	// Evaluate the expression with the mathematical constants
+	result, err := expression.Evaluate(mathexpr.Constants())
 	if err != nil {
 		return nil, fmt.Errorf("evaluation error: %v", err)
 	}
+	if err := mathexpr.CheckFinite(result); err != nil {
+		return nil, err
+	}
+	result = mathexpr.ExactResult(processed, mathexpr.Constants(), result)
 	
 	// Format the result
 	switch v := result.(type) {
 	case float64:
-		// Format to remove unnecessary decimal places
-		if v == float64(int64(v)) {
-			return int64(v), nil
-		}
-		return v, nil
+		return c.formatNumber(v), nil
 	case int64:
+		if c.precision > 0 || c.scientific {
+			return c.formatNumber(float64(v)), nil
+		}
 		return v, nil
 	default:
 		return fmt.Sprintf("%v", result), nil
 	}
 }
 
-// Mathematical function wrappers for govaluate
-func sqrt(args ...interface{}) (interface{}, error) {
-	if len(args) != 1 {
-		return nil, fmt.Errorf("sqrt requires exactly 1 argument")
+// formatNumber rounds v to the configured precision and removes unnecessary
+// decimal places, returning an int64 for integral values that fit in one
+func (c *Calculator) formatNumber(v float64) interface{} {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return v
 	}
-	val, err := toFloat64(args[0])
-	if err != nil {
-		return nil, err
-	}
-	return math.Sqrt(val), nil
-}
 
-func sin(args ...interface{}) (interface{}, error) {
-	if len(args) != 1 {
-		return nil, fmt.Errorf("sin requires exactly 1 argument")
-	}
-	val, err := toFloat64(args[0])
-	if err != nil {
-		return nil, err
-	}
-	return math.Sin(val), nil
-}
-
-func cos(args ...interface{}) (interface{}, error) {
-	if len(args) != 1 {
-		return nil, fmt.Errorf("cos requires exactly 1 argument")
-	}
-	val, err := toFloat64(args[0])
-	if err != nil {
-		return nil, err
-	}
-	return math.Cos(val), nil
-}
-
-func tan(args ...interface{}) (interface{}, error) {
-	if len(args) != 1 {
-		return nil, fmt.Errorf("tan requires exactly 1 argument")
-	}
-	val, err := toFloat64(args[0])
-	if err != nil {
-		return nil, err
-	}
-	return math.Tan(val), nil
-}
-
-func log(args ...interface{}) (interface{}, error) {
-	if len(args) != 1 {
-		return nil, fmt.Errorf("log requires exactly 1 argument")
-	}
-	val, err := toFloat64(args[0])
-	if err != nil {
-		return nil, err
-	}
-	return math.Log10(val), nil
-}
-
-func ln(args ...interface{}) (interface{}, error) {
-	if len(args) != 1 {
-		return nil, fmt.Errorf("ln requires exactly 1 argument")
-	}
-	val, err := toFloat64(args[0])
-	if err != nil {
-		return nil, err
-	}
-	return math.Log(val), nil
-}
-
-func exp(args ...interface{}) (interface{}, error) {
-	if len(args) != 1 {
-		return nil, fmt.Errorf("exp requires exactly 1 argument")
-	}
-	val, err := toFloat64(args[0])
-	if err != nil {
-		return nil, err
-	}
-	return math.Exp(val), nil
-}
-
-func pow(args ...interface{}) (interface{}, error) {
-	if len(args) != 2 {
-		return nil, fmt.Errorf("pow requires exactly 2 arguments")
-	}
-	base, err := toFloat64(args[0])
-	if err != nil {
-		return nil, err
-	}
-	exponent, err := toFloat64(args[1])
-	if err != nil {
-		return nil, err
-	}
-	return math.Pow(base, exponent), nil
-}
-
-func abs(args ...interface{}) (interface{}, error) {
-	if len(args) != 1 {
-		return nil, fmt.Errorf("abs requires exactly 1 argument")
-	}
-	val, err := toFloat64(args[0])
-	if err != nil {
-		return nil, err
-	}
-	return math.Abs(val), nil
-}
-
-func ceil(args ...interface{}) (interface{}, error) {
-	if len(args) != 1 {
-		return nil, fmt.Errorf("ceil requires exactly 1 argument")
-	}
-	val, err := toFloat64(args[0])
-	if err != nil {
-		return nil, err
+	if c.precision > 0 {
+		if rounded, err := strconv.ParseFloat(strconv.FormatFloat(v, 'g', c.precision, 64), 64); err == nil {
+			v = rounded
+		}
 	}
-	return math.Ceil(val), nil
-}
 
-func floor(args ...interface{}) (interface{}, error) {
-	if len(args) != 1 {
-		return nil, fmt.Errorf("floor requires exactly 1 argument")
-	}
-	val, err := toFloat64(args[0])
-	if err != nil {
-		return nil, err
+	if c.scientific && v != 0 {
+		if mag := math.Abs(v); mag >= sciUpperThreshold || mag < sciLowerThreshold {
+			digits := -1
+			if c.precision > 0 {
+				digits = c.precision - 1
+			}
+			return strconv.FormatFloat(v, 'e', digits, 64)
+		}
 	}
-	return math.Floor(val), nil
-}
 
-func round(args ...interface{}) (interface{}, error) {
-	if len(args) != 1 {
-		return nil, fmt.Errorf("round requires exactly 1 argument")
-	}
-	val, err := toFloat64(args[0])
-	if err != nil {
-		return nil, err
+	if isInt64(v) {
+		return int64(v)
 	}
-	return math.Round(val), nil
+	return v
 }
 
-// toFloat64 converts an interface to float64
-func toFloat64(val interface{}) (float64, error) {
-	switch v := val.(type) {
-	case float64:
-		return v, nil
-	case float32:
-		return float64(v), nil
-	case int:
-		return float64(v), nil
-	case int64:
-		return float64(v), nil
-	case int32:
-		return float64(v), nil
-	case string:
-		return strconv.ParseFloat(v, 64)
-	default:
-		return 0, fmt.Errorf("cannot convert %T to float64", val)
-	}
-}
+// mathFunctions are the functions available in calculator expressions
+var mathFunctions = mathexpr.DefaultFunctions()
 
-// preprocessExpression handles common mathematical notation
-func preprocessExpression(expr string) string {
-	// Replace common mathematical constants
-	expr = strings.ReplaceAll(expr, "π", "pi")
-	
-	// Handle implicit multiplication (e.g., 2pi -> 2*pi)
-	// This is a simple implementation and may need refinement
-	expr = strings.ReplaceAll(expr, "2pi", "2*pi")
-	expr = strings.ReplaceAll(expr, "2e", "2*e")
-	
-	return expr
+// isInt64 reports whether v is integral and within the range of int64, so the
+// conversion int64(v) is exact
+func isInt64(v float64) bool {
+	return v == math.Trunc(v) && v >= math.MinInt64 && v < math.MaxInt64
 }
 
 // evaluateSimple handles basic arithmetic for fallback
-func evaluateSimple(expr string) (interface{}, error) {
+func evaluateSimple(expr string) (float64, error) {
 	// Remove spaces
 	expr = strings.ReplaceAll(expr, " ", "")
 	
 	// Try to parse as a simple number
 	if val, err := strconv.ParseFloat(expr, 64); err == nil {
-		if val == float64(int64(val)) {
-			return int64(val), nil
-		}
 		return val, nil
 	}
 	
 	// For more complex expressions, return an error to use the main evaluator
-	return nil, fmt.Errorf("expression too complex for simple evaluation")
+	return 0, fmt.Errorf("expression too complex for simple evaluation")
 }
\ No newline at end of file
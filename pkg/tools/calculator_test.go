@@ -0,0 +1,123 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestCalculator_IntegerFunctions(t *testing.T) {
+	tests := []struct {
+		expression string
+		expected   interface{}
+		wantErr    bool
+	}{
+		{expression: "factorial(5)", expected: int64(120)},
+		{expression: "gcd(12, 18)", expected: int64(6)},
+		{expression: "lcm(4, 6)", expected: int64(12)},
+		{expression: "17 mod 5", expected: int64(2)},
+		{expression: "mod(7.5, 2)", expected: 1.5},
+		{expression: "pow(2, 10)", expected: int64(1024)},
+		{expression: "pow(3, 39)", expected: int64(4052555153018976267)},
+		{expression: "factorial(5) + 1", expected: int64(121)},
+		{expression: "pow(2, 60) + 1", expected: int64(1152921504606846976)},
+		{expression: "factorial(20) + 1", expected: int64(2432902008176640000)},
+		{expression: "factorial(19) * 2", expected: int64(243290200817664000)},
+		{expression: "sqrt(16)", expected: int64(4)},
+		{expression: "factorial(-1)", wantErr: true},
+		{expression: "factorial(2.5)", wantErr: true},
+	}
+
+	calc := NewCalculator()
+	ctx := context.Background()
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			got, err := calc.Execute(ctx, map[string]interface{}{"expression": tt.expression})
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Execute(%q) error = %v", tt.expression, err)
+			}
+			if got != tt.expected {
+				t.Errorf("Execute(%q) = %v (%T), want %v (%T)", tt.expression, got, got, tt.expected, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCalculator_Precision(t *testing.T) {
+	tests := []struct {
+		name       string
+		calc       *Calculator
+		expression string
+		expected   interface{}
+	}{
+		{name: "integer result", calc: NewCalculator(), expression: "2 + 2", expected: int64(4)},
+		{name: "beyond int64 stays float", calc: NewCalculator(), expression: "1e20", expected: 1e20},
+		{name: "default keeps full precision", calc: NewCalculator(), expression: "0.1 + 0.2", expected: 0.30000000000000004},
+		{name: "rounded to 6 digits", calc: NewCalculatorWithOptions(6, false), expression: "0.1 + 0.2", expected: 0.3},
+		{name: "rounded integer result", calc: NewCalculatorWithOptions(6, false), expression: "10 / 4 * 2", expected: int64(5)},
+		{name: "scientific large", calc: NewCalculatorWithOptions(0, true), expression: "1e20", expected: "1e+20"},
+		{name: "scientific with precision", calc: NewCalculatorWithOptions(3, true), expression: "2 / 30000", expected: "6.67e-05"},
+		{name: "scientific leaves normal values", calc: NewCalculatorWithOptions(0, true), expression: "2 + 2", expected: int64(4)},
+	}
+
+	ctx := context.Background()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.calc.Execute(ctx, map[string]interface{}{"expression": tt.expression})
+			if err != nil {
+				t.Fatalf("Execute(%q) error = %v", tt.expression, err)
+			}
+			if got != tt.expected {
+				t.Errorf("Execute(%q) = %v (%T), want %v (%T)", tt.expression, got, got, tt.expected, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCalculator_ImplicitMultiplication(t *testing.T) {
+	calc := NewCalculator()
+	ctx := context.Background()
+
+	for expression, expected := range map[string]interface{}{
+		"2(3+4)":   int64(14),
+		"2sqrt(9)": int64(6),
+		"1e3":      int64(1000),
+	} {
+		got, err := calc.Execute(ctx, map[string]interface{}{"expression": expression})
+		if err != nil {
+			t.Fatalf("Execute(%q) error = %v", expression, err)
+		}
+		if got != expected {
+			t.Errorf("Execute(%q) = %v, want %v", expression, got, expected)
+		}
+	}
+}
+
+func TestCalculator_MathErrors(t *testing.T) {
+	tests := []struct {
+		expression string
+		wantErr    string
+	}{
+		{expression: "1/0", wantErr: "division by zero"},
+		{expression: "sqrt(-4)", wantErr: "math domain error (sqrt of negative)"},
+		{expression: "log(0)", wantErr: "math domain error (log of zero)"},
+		{expression: "ln(-1)", wantErr: "math domain error (log of negative)"},
+	}
+
+	calc := NewCalculator()
+	ctx := context.Background()
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			got, err := calc.Execute(ctx, map[string]interface{}{"expression": tt.expression})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Execute(%q) = %v, %v; want error containing %q", tt.expression, got, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,210 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultFileReadMaxChars is the default limit on the characters a FileReadTool returns
+const DefaultFileReadMaxChars = 20000
+
+// FileReadTool reads text files from a sandbox directory. Paths are resolved
+// relative to the root, and any path that resolves outside it, through "..",
+// an absolute path or a symlink, is rejected.
+type FileReadTool struct {
+	*BaseTool
+	rootDir  string
+	maxChars int
+}
+
+// NewFileReadTool creates a file-read tool scoped to rootDir
+func NewFileReadTool(rootDir string) *FileReadTool {
+	root, err := filepath.Abs(rootDir)
+	if err == nil {
+		if resolved, err := filepath.EvalSymlinks(root); err == nil {
+			root = resolved
+		}
+	} else {
+		root = filepath.Clean(rootDir)
+	}
+
+	tool := &FileReadTool{
+		BaseTool: NewBaseTool(
+			"read_file",
+			"Read a text file from the working directory",
+			nil, // Set below
+		),
+		rootDir:  root,
+		maxChars: DefaultFileReadMaxChars,
+	}
+
+	// Set the executor
+	tool.executor = tool.execute
+
+	// Define schema
+	tool.schema = ToolSchema{
+		Name:        "read_file",
+		Description: tool.description,
+		Args: map[string]ArgumentSchema{
+			"path": {
+				Type:        "string",
+				Description: "Path of the file, relative to the working directory",
+				Required:    true,
+			},
+			"offset": {
+				Type:        "integer",
+				Description: "Line number to start reading from, starting at 1",
+				Default:     1,
+				Required:    false,
+			},
+			"limit": {
+				Type:        "integer",
+				Description: "Maximum number of lines to read; 0 reads to the end of the file",
+				Default:     0,
+				Required:    false,
+			},
+		},
+		Returns: "The contents of the file, truncated if too long",
+		Examples: []string{
+			`{"name": "read_file", "args": {"path": "notes.txt"}}`,
+			`{"name": "read_file", "args": {"path": "data/report.md", "offset": 100, "limit": 50}}`,
+		},
+	}
+
+	return tool
+}
+
+// SetMaxChars sets the maximum number of characters returned per read; a
+// non-positive value restores DefaultFileReadMaxChars
+func (f *FileReadTool) SetMaxChars(n int) {
+	if n <= 0 {
+		n = DefaultFileReadMaxChars
+	}
+	f.maxChars = n
+}
+
+// execute reads the requested lines of the file
+func (f *FileReadTool) execute(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	pathInterface, ok := args["path"]
+	if !ok {
+		return nil, fmt.Errorf("missing required argument 'path'")
+	}
+
+	path, ok := pathInterface.(string)
+	if !ok {
+		return nil, fmt.Errorf("path must be a string")
+	}
+
+	offset := intArg(args, "offset", 1)
+	if offset < 1 {
+		return nil, fmt.Errorf("offset must be at least 1, got %d", offset)
+	}
+	limit := intArg(args, "limit", 0)
+	if limit < 0 {
+		return nil, fmt.Errorf("limit must not be negative, got %d", limit)
+	}
+
+	fullPath, err := f.resolve(path)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(fullPath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("file not found: %s", path)
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("%s is a directory, not a file", path)
+	}
+
+	data, err := os.ReadFile(fullPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	lines := strings.SplitAfter(string(data), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if offset > len(lines) {
+		if offset == 1 {
+			return "", nil // Empty file
+		}
+		return nil, fmt.Errorf("offset %d is past the end of %s (%d lines)", offset, path, len(lines))
+	}
+
+	end := len(lines)
+	if limit > 0 && offset-1+limit < end {
+		end = offset - 1 + limit
+	}
+
+	return f.truncate(strings.Join(lines[offset-1:end], "")), nil
+}
+
+// resolve maps path to a location inside the root directory, rejecting paths
+// that escape it
+func (f *FileReadTool) resolve(path string) (string, error) {
+	if strings.TrimSpace(path) == "" {
+		return "", fmt.Errorf("path must not be empty")
+	}
+
+	target := filepath.Clean(path)
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(f.rootDir, target)
+	}
+	if !f.contains(target) {
+		return "", fmt.Errorf("access denied: %s is outside the working directory", path)
+	}
+
+	// Follow symlinks, so a link inside the root cannot point outside it
+	resolved, err := filepath.EvalSymlinks(target)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return target, nil // Reported as not found by the caller
+		}
+		return "", fmt.Errorf("failed to resolve %s: %w", path, err)
+	}
+	if !f.contains(resolved) {
+		return "", fmt.Errorf("access denied: %s is outside the working directory", path)
+	}
+	return resolved, nil
+}
+
+// contains reports whether the absolute path lies within the root directory
+func (f *FileReadTool) contains(path string) bool {
+	rel, err := filepath.Rel(f.rootDir, path)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) && !filepath.IsAbs(rel)
+}
+
+// truncate limits contents to the configured number of characters
+func (f *FileReadTool) truncate(contents string) string {
+	runes := []rune(contents)
+	if len(runes) <= f.maxChars {
+		return contents
+	}
+	return string(runes[:f.maxChars]) + "\n... (file truncated; use offset and limit to read the rest)"
+}
+
+// intArg reads an integer argument, accepting the float64 values produced by JSON decoding
+func intArg(args map[string]interface{}, name string, defaultValue int) int {
+	switch v := args[name].(type) {
+	case int:
+		return v
+	case int64:
+		return int(v)
+	case float64:
+		return int(v)
+	}
+	return defaultValue
+}
@@ -0,0 +1,111 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newFileReadFixture(t *testing.T) (*FileReadTool, string) {
+	t.Helper()
+	dir := t.TempDir()
+	root := filepath.Join(dir, "root")
+	if err := os.MkdirAll(filepath.Join(root, "docs"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "docs", "notes.txt"), []byte("one\ntwo\nthree\nfour\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "secret.txt"), []byte("top secret\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return NewFileReadTool(root), dir
+}
+
+func TestFileReadTool_Read(t *testing.T) {
+	tool, _ := newFileReadFixture(t)
+
+	tests := []struct {
+		name     string
+		args     map[string]interface{}
+		expected string
+	}{
+		{"whole file", map[string]interface{}{"path": "docs/notes.txt"}, "one\ntwo\nthree\nfour\n"},
+		{"offset and limit", map[string]interface{}{"path": "docs/notes.txt", "offset": float64(2), "limit": float64(2)}, "two\nthree\n"},
+		{"limit past end", map[string]interface{}{"path": "docs/notes.txt", "offset": 4, "limit": 10}, "four\n"},
+		{"redundant path segments", map[string]interface{}{"path": "./docs/../docs/notes.txt", "limit": 1}, "one\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tool.Execute(context.Background(), tt.args)
+			if err != nil {
+				t.Fatalf("Execute failed: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestFileReadTool_Truncates(t *testing.T) {
+	tool, _ := newFileReadFixture(t)
+	tool.SetMaxChars(5)
+
+	result, err := tool.Execute(context.Background(), map[string]interface{}{"path": "docs/notes.txt"})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if !strings.HasPrefix(result.(string), "one\nt\n... (file truncated") {
+		t.Errorf("Expected truncated contents, got %q", result)
+	}
+}
+
+func TestFileReadTool_BlocksEscapes(t *testing.T) {
+	tool, dir := newFileReadFixture(t)
+
+	link := filepath.Join(tool.rootDir, "link.txt")
+	if err := os.Symlink(filepath.Join(dir, "secret.txt"), link); err != nil {
+		t.Logf("Skipping symlink case: %v", err)
+		link = ""
+	}
+
+	paths := []string{"../secret.txt", "docs/../../secret.txt", filepath.Join(dir, "secret.txt")}
+	if link != "" {
+		paths = append(paths, "link.txt")
+	}
+
+	for _, path := range paths {
+		result, err := tool.Execute(context.Background(), map[string]interface{}{"path": path})
+		if err == nil || !strings.Contains(err.Error(), "access denied") {
+			t.Errorf("Expected %q to be denied, got %q, %v", path, result, err)
+		}
+	}
+}
+
+func TestFileReadTool_Errors(t *testing.T) {
+	tool, _ := newFileReadFixture(t)
+
+	tests := []struct {
+		name     string
+		args     map[string]interface{}
+		expected string
+	}{
+		{"missing file", map[string]interface{}{"path": "docs/missing.txt"}, "file not found: docs/missing.txt"},
+		{"directory", map[string]interface{}{"path": "docs"}, "is a directory"},
+		{"offset past end", map[string]interface{}{"path": "docs/notes.txt", "offset": 9}, "past the end"},
+		{"missing path", map[string]interface{}{}, "missing required argument 'path'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tool.Execute(context.Background(), tt.args)
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("Expected error containing %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"time"
+)
+
+// PythonToolOptions configures a PythonTool. Zero values use the defaults.
+type PythonToolOptions struct {
+	Interpreter string        // Interpreter executable (default "python3")
+	Args        []string      // Arguments placed before the code string (default ["-c"])
+	Timeout     time.Duration // Wall-clock limit per execution (default 10s)
+	MaxOutput   int           // Maximum bytes of output returned (default 4096)
+}
+
+// PythonTool executes Python code in a subprocess.
+//
+// The tool runs arbitrary model-generated code with the permissions of the
+// current process. It provides a timeout and output limit but no sandboxing of
+// its own, so it must only be used inside an isolated environment such as a
+// container or VM without access to credentials or the network.
+type PythonTool struct {
+	*BaseTool
+	interpreter string
+	args        []string
+	timeout     time.Duration
+	maxOutput   int
+}
+
+// NewPythonTool creates a new Python code execution tool
+func NewPythonTool(opts PythonToolOptions) *PythonTool {
+	if opts.Interpreter == "" {
+		opts.Interpreter = "python3"
+	}
+	if opts.Args == nil {
+		opts.Args = []string{"-c"}
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 10 * time.Second
+	}
+	if opts.MaxOutput <= 0 {
+		opts.MaxOutput = 4096
+	}
+
+	tool := &PythonTool{
+		BaseTool: NewBaseTool(
+			"python",
+			"Execute Python code and return its printed output. Use print() to show results.",
+			nil, // Set below
+		),
+		interpreter: opts.Interpreter,
+		args:        opts.Args,
+		timeout:     opts.Timeout,
+		maxOutput:   opts.MaxOutput,
+	}
+
+	// Set the executor
+	tool.executor = tool.execute
+
+	// Define schema
+	tool.schema = ToolSchema{
+		Name:        "python",
+		Description: tool.description,
+		Args: map[string]ArgumentSchema{
+			"code": {
+				Type:        "string",
+				Description: "Python code to execute",
+				Required:    true,
+			},
+		},
+		Returns: "The stdout and stderr of the program",
+		Examples: []string{
+			`{"name": "python", "args": {"code": "print(2 ** 10)"}}`,
+			`{"name": "python", "args": {"code": "import math\nprint(math.factorial(10))"}}`,
+		},
+	}
+
+	return tool
+}
+
+// execute runs the code with the configured interpreter
+func (p *PythonTool) execute(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	codeInterface, ok := args["code"]
+	if !ok {
+		return nil, fmt.Errorf("missing required argument 'code'")
+	}
+
+	code, ok := codeInterface.(string)
+	if !ok {
+		return nil, fmt.Errorf("code must be a string")
+	}
+
+	interpreter, err := exec.LookPath(p.interpreter)
+	if err != nil {
+		return nil, fmt.Errorf("python interpreter %q not found: %w", p.interpreter, err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	cmdArgs := append(append([]string{}, p.args...), code)
+	cmd := exec.CommandContext(ctx, interpreter, cmdArgs...)
+	cmd.WaitDelay = time.Second // Don't wait on pipes held open by orphaned children
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	runErr := cmd.Run()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return nil, fmt.Errorf("execution timed out after %s", p.timeout)
+	}
+
+	output := stdout.String()
+	if stderr.Len() > 0 {
+		if output != "" && output[len(output)-1] != '\n' {
+			output += "\n"
+		}
+		output += stderr.String()
+	}
+	output = p.truncate(output)
+
+	if runErr != nil {
+		return nil, fmt.Errorf("execution failed: %v\n%s", runErr, output)
+	}
+
+	return output, nil
+}
+
+// truncate limits output to the configured size
+func (p *PythonTool) truncate(output string) string {
+	if len(output) <= p.maxOutput {
+		return output
+	}
+	return output[:p.maxOutput] + "\n... (output truncated)"
+}
@@ -0,0 +1,51 @@
+package tools
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+func requirePython(t *testing.T) {
+	t.Helper()
+	if _, err := exec.LookPath("python3"); err != nil {
+		t.Skip("python3 not available")
+	}
+}
+
+func TestPythonTool_Print(t *testing.T) {
+	requirePython(t)
+
+	tool := NewPythonTool(PythonToolOptions{})
+	result, err := tool.Execute(context.Background(), map[string]interface{}{"code": "print(6 * 7)"})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if strings.TrimSpace(result.(string)) != "42" {
+		t.Errorf("Expected output 42, got %q", result)
+	}
+}
+
+func TestPythonTool_Timeout(t *testing.T) {
+	requirePython(t)
+
+	tool := NewPythonTool(PythonToolOptions{Timeout: 200 * time.Millisecond})
+	start := time.Now()
+	_, err := tool.Execute(context.Background(), map[string]interface{}{"code": "import time\ntime.sleep(10)"})
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("Expected timeout error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Timeout took too long: %v", elapsed)
+	}
+}
+
+func TestPythonTool_MissingInterpreter(t *testing.T) {
+	tool := NewPythonTool(PythonToolOptions{Interpreter: "no-such-python-interpreter"})
+	_, err := tool.Execute(context.Background(), map[string]interface{}{"code": "print(1)"})
+	if err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Expected interpreter not found error, got %v", err)
+	}
+}
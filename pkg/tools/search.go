@@ -1,6 +1,7 @@
 package tools
 
 import (
+	"container/list"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -21,12 +22,22 @@ const (
 	SearchEngineBing       SearchEngine = "bing"
 )
 
+// DefaultUserAgent identifies the search tool in requests to search engines
+const DefaultUserAgent = "go-verifiers-search/1.0 (+https://github.com/rizome-dev/go-verifiers)"
+
+// duckDuckGoEndpoint is the DuckDuckGo instant answer API
+const duckDuckGoEndpoint = "https://api.duckduckgo.com/"
+
 // WebSearch implements web search functionality
 type WebSearch struct {
 	*BaseTool
 	httpClient   *http.Client
 	searchEngine SearchEngine
 	apiKey       string // For engines that require API keys
+	userAgent    string
+	endpoint     string // DuckDuckGo API URL, replaced in tests
+	simulate     bool   // Return simulated results instead of errors or empty results
+	search       func(ctx context.Context, query string, maxResults int) ([]SearchResult, error)
 }
 
 // NewWebSearch creates a new web search tool
@@ -41,10 +52,13 @@ func NewWebSearch(engine SearchEngine) *WebSearch {
 			Timeout: 30 * time.Second,
 		},
 		searchEngine: engine,
+		userAgent:    DefaultUserAgent,
+		endpoint:     duckDuckGoEndpoint,
 	}
 
-	// Set the executor
+	// Set the executor and search backend
 	search.executor = search.execute
+	search.search = search.performSearch
 
 	// Define schema
 	search.schema = ToolSchema{
@@ -62,6 +76,12 @@ func NewWebSearch(engine SearchEngine) *WebSearch {
 				Default:     5,
 				Required:    false,
 			},
+			"structured": {
+				Type:        "boolean",
+				Description: "Return the results as a JSON array of {title, url, snippet} objects",
+				Default:     false,
+				Required:    false,
+			},
 		},
 		Returns: "Search results containing titles, URLs, and snippets",
 		Examples: []string{
@@ -78,6 +98,23 @@ func (s *WebSearch) SetAPIKey(key string) {
 	s.apiKey = key
 }
 
+// SetUserAgent sets the User-Agent header sent to search engines. An empty string
+// restores DefaultUserAgent.
+func (s *WebSearch) SetUserAgent(userAgent string) {
+	if userAgent == "" {
+		userAgent = DefaultUserAgent
+	}
+	s.userAgent = userAgent
+}
+
+// EnableSimulation controls whether simulated results stand in for engines without
+// a backend and for DuckDuckGo searches that find nothing. It is off by default;
+// simulated results contain made-up URLs, so this is meant for offline demos and
+// tests only.
+func (s *WebSearch) EnableSimulation(enabled bool) {
+	s.simulate = enabled
+}
+
 // SearchResult represents a single search result
 type SearchResult struct {
 	Title   string `json:"title"`
@@ -85,16 +122,23 @@ type SearchResult struct {
 	Snippet string `json:"snippet"`
 }
 
-// execute performs the search
-func (s *WebSearch) execute(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+// searchArgs are the parsed arguments of a search tool call
+type searchArgs struct {
+	query      string
+	maxResults int
+	structured bool
+}
+
+// parseSearchArgs reads the query, max_results and structured arguments
+func parseSearchArgs(args map[string]interface{}) (searchArgs, error) {
 	queryInterface, ok := args["query"]
 	if !ok {
-		return nil, fmt.Errorf("missing required argument 'query'")
+		return searchArgs{}, fmt.Errorf("missing required argument 'query'")
 	}
 
 	query, ok := queryInterface.(string)
 	if !ok {
-		return nil, fmt.Errorf("query must be a string")
+		return searchArgs{}, fmt.Errorf("query must be a string")
 	}
 
 	maxResults := 5
@@ -109,14 +153,44 @@ func (s *WebSearch) execute(ctx context.Context, args map[string]interface{}) (i
 		}
 	}
 
+	structured, _ := args["structured"].(bool)
+
+	return searchArgs{query: query, maxResults: maxResults, structured: structured}, nil
+}
+
+// execute performs the search
+func (s *WebSearch) execute(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	parsed, err := parseSearchArgs(args)
+	if err != nil {
+		return nil, err
+	}
+
 	// Perform search based on engine
-	results, err := s.performSearch(ctx, query, maxResults)
+	results, err := s.SearchStructured(ctx, parsed.query, parsed.maxResults)
 	if err != nil {
-		return nil, fmt.Errorf("search failed: %w", err)
+		return nil, err
 	}
 
-	// Format results
-	return s.formatResults(results), nil
+	return s.renderResults(results, parsed.structured), nil
+}
+
+// renderResults returns the results as is for structured output, which ExecuteTool
+// encodes as JSON, and as formatted text otherwise
+func (s *WebSearch) renderResults(results []SearchResult, structured bool) interface{} {
+	if structured {
+		return results
+	}
+	return s.formatResults(results)
+}
+
+// SearchStructured performs a search and returns the individual results, for callers
+// that need to inspect titles and URLs rather than the formatted text
+func (s *WebSearch) SearchStructured(ctx context.Context, query string, maxResults int) ([]SearchResult, error) {
+	results, err := s.search(ctx, query, maxResults)
+	if err != nil {
+		return nil, fmt.Errorf("search failed: %w", err)
+	}
+	return results, nil
 }
 
 // performSearch executes the search based on the configured engine
@@ -125,7 +199,10 @@ func (s *WebSearch) performSearch(ctx context.Context, query string, maxResults
 	case SearchEngineDuckDuckGo:
 		return s.searchDuckDuckGo(ctx, query, maxResults)
 	default:
-		// For now, we'll simulate search results
+		// Other engines have no backend yet; only simulate them when asked to
+		if !s.simulate {
+			return nil, fmt.Errorf("search engine %s is not implemented; use %s or enable simulation for offline use", s.searchEngine, SearchEngineDuckDuckGo)
+		}
 		return s.simulateSearch(query, maxResults), nil
 	}
 }
@@ -133,13 +210,14 @@ func (s *WebSearch) performSearch(ctx context.Context, query string, maxResults
 // searchDuckDuckGo performs a search using DuckDuckGo's instant answer API
 func (s *WebSearch) searchDuckDuckGo(ctx context.Context, query string, maxResults int) ([]SearchResult, error) {
 	// DuckDuckGo instant answer API (limited but no API key required)
-	apiURL := fmt.Sprintf("https://api.duckduckgo.com/?q=%s&format=json&no_html=1&skip_disambig=1",
-		url.QueryEscape(query))
+	apiURL := fmt.Sprintf("%s?q=%s&format=json&no_html=1&skip_disambig=1",
+		s.endpoint, url.QueryEscape(query))
 
 	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
 	if err != nil {
 		return nil, err
 	}
+	req.Header.Set("User-Agent", s.userAgent)
 
 	resp, err := s.httpClient.Do(req)
 	if err != nil {
@@ -152,6 +230,11 @@ func (s *WebSearch) searchDuckDuckGo(ctx context.Context, query string, maxResul
 		return nil, err
 	}
 
+	// Error pages are HTML, so report the status instead of failing to decode them
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("duckduckgo returned status %d: %s", resp.StatusCode, truncateBody(body))
+	}
+
 	// Parse DuckDuckGo response
 	var ddgResponse struct {
 		Abstract       string `json:"Abstract"`
@@ -165,7 +248,7 @@ func (s *WebSearch) searchDuckDuckGo(ctx context.Context, query string, maxResul
 	}
 
 	if err := json.Unmarshal(body, &ddgResponse); err != nil {
-		return nil, err
+		return nil, fmt.Errorf("invalid duckduckgo response: %w", err)
 	}
 
 	// Convert to our format
@@ -194,14 +277,24 @@ func (s *WebSearch) searchDuckDuckGo(ctx context.Context, query string, maxResul
 		}
 	}
 
-	// If no results, return simulated results
-	if len(results) == 0 {
+	// If no results, return simulated results when enabled
+	if len(results) == 0 && s.simulate {
 		return s.simulateSearch(query, maxResults), nil
 	}
 
 	return results, nil
 }
 
+// truncateBody shortens a response body for inclusion in an error message
+func truncateBody(body []byte) string {
+	const maxLen = 200
+	text := []rune(strings.TrimSpace(string(body)))
+	if len(text) > maxLen {
+		return string(text[:maxLen]) + "..."
+	}
+	return string(text)
+}
+
 // simulateSearch returns simulated search results for demonstration
 func (s *WebSearch) simulateSearch(query string, maxResults int) []SearchResult {
 	// Simulate search results based on query keywords
@@ -273,60 +366,142 @@ func extractTitle(text string) string {
 	return text
 }
 
-// SearchCache provides caching for search results
+// SearchCache provides caching for search results. Entries expire after the TTL and,
+// when maxEntries is positive, the least recently used entry is evicted once the
+// cache is full. Expired entries are swept lazily, at most once per TTL.
 type SearchCache struct {
 	*WebSearch
-	cache     map[string]cacheEntry
-	cacheMu   sync.RWMutex
-	ttl       time.Duration
+	cache      map[string]*list.Element
+	order      *list.List // Front is most recently used
+	cacheMu    sync.RWMutex
+	ttl        time.Duration
+	maxEntries int
+	lastSweep  time.Time
+	now        func() time.Time
 }
 
 type cacheEntry struct {
+	key       string
 	results   []SearchResult
 	timestamp time.Time
 }
 
-// NewCachedWebSearch creates a web search tool with caching
-func NewCachedWebSearch(engine SearchEngine, ttl time.Duration) *SearchCache {
-	return &SearchCache{
-		WebSearch: NewWebSearch(engine),
-		cache:     make(map[string]cacheEntry),
-		ttl:       ttl,
+// NewCachedWebSearch creates a web search tool with caching. A maxEntries of 0 or
+// less leaves the cache size unbounded.
+func NewCachedWebSearch(engine SearchEngine, ttl time.Duration, maxEntries int) *SearchCache {
+	cache := &SearchCache{
+		WebSearch:  NewWebSearch(engine),
+		cache:      make(map[string]*list.Element),
+		order:      list.New(),
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		now:        time.Now,
 	}
+	cache.lastSweep = cache.now()
+
+	// Route Execute through the cache instead of the embedded WebSearch
+	cache.BaseTool.executor = cache.execute
+
+	return cache
+}
+
+// Len returns the number of cached entries, including expired ones not yet swept
+func (c *SearchCache) Len() int {
+	c.cacheMu.RLock()
+	defer c.cacheMu.RUnlock()
+	return len(c.cache)
 }
 
 // execute performs cached search
 func (c *SearchCache) execute(ctx context.Context, args map[string]interface{}) (interface{}, error) {
-	query, _ := args["query"].(string)
-	maxResults := 5
-	if mr, ok := args["max_results"]; ok {
-		if v, ok := mr.(float64); ok {
-			maxResults = int(v)
-		}
+	parsed, err := parseSearchArgs(args)
+	if err != nil {
+		return nil, err
 	}
 
 	// Check cache
-	cacheKey := fmt.Sprintf("%s:%d", query, maxResults)
-	c.cacheMu.RLock()
-	if entry, ok := c.cache[cacheKey]; ok && time.Since(entry.timestamp) < c.ttl {
-		c.cacheMu.RUnlock()
-		return c.formatResults(entry.results), nil
+	cacheKey := fmt.Sprintf("%s:%d", parsed.query, parsed.maxResults)
+	if results, ok := c.get(cacheKey); ok {
+		return c.renderResults(results, parsed.structured), nil
 	}
-	c.cacheMu.RUnlock()
 
 	// Perform search
-	results, err := c.performSearch(ctx, query, maxResults)
+	results, err := c.SearchStructured(ctx, parsed.query, parsed.maxResults)
 	if err != nil {
 		return nil, err
 	}
 
-	// Update cache
+	c.put(cacheKey, results)
+
+	return c.renderResults(results, parsed.structured), nil
+}
+
+// get returns unexpired cached results and marks them as recently used. It takes
+// the write lock because a hit reorders the LRU list.
+func (c *SearchCache) get(key string) ([]SearchResult, bool) {
+	c.cacheMu.Lock()
+	defer c.cacheMu.Unlock()
+
+	elem, ok := c.cache[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := elem.Value.(*cacheEntry)
+	if c.now().Sub(entry.timestamp) >= c.ttl {
+		c.removeElement(elem)
+		return nil, false
+	}
+
+	c.order.MoveToFront(elem)
+	return entry.results, true
+}
+
+// put stores results, sweeping expired entries and evicting the least recently used
+// entries beyond maxEntries
+func (c *SearchCache) put(key string, results []SearchResult) {
 	c.cacheMu.Lock()
-	c.cache[cacheKey] = cacheEntry{
+	defer c.cacheMu.Unlock()
+
+	now := c.now()
+	if now.Sub(c.lastSweep) >= c.ttl {
+		c.sweepExpired(now)
+	}
+
+	if elem, ok := c.cache[key]; ok {
+		entry := elem.Value.(*cacheEntry)
+		entry.results = results
+		entry.timestamp = now
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.cache[key] = c.order.PushFront(&cacheEntry{
+		key:       key,
 		results:   results,
-		timestamp: time.Now(),
+		timestamp: now,
+	})
+
+	for c.maxEntries > 0 && c.order.Len() > c.maxEntries {
+		c.removeElement(c.order.Back())
 	}
-	c.cacheMu.Unlock()
+}
 
-	return c.formatResults(results), nil
-}
\ No newline at end of file
+// sweepExpired drops every expired entry. The caller must hold the write lock.
+func (c *SearchCache) sweepExpired(now time.Time) {
+	for elem := c.order.Front(); elem != nil; {
+		next := elem.Next()
+		if now.Sub(elem.Value.(*cacheEntry).timestamp) >= c.ttl {
+			c.removeElement(elem)
+		}
+		elem = next
+	}
+	c.lastSweep = now
+}
+
+// removeElement deletes an entry from the list and the index. The caller must hold
+// the write lock.
+func (c *SearchCache) removeElement(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.cache, elem.Value.(*cacheEntry).key)
+}
@@ -0,0 +1,205 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWebSearch_StructuredResults(t *testing.T) {
+	// The Google engine is simulated, so the test needs no network access
+	search := NewWebSearch(SearchEngineGoogle)
+	search.EnableSimulation(true)
+	toolMap := map[string]Tool{search.Name(): search}
+	ctx := context.Background()
+
+	call := &ToolCall{Name: "search", Args: map[string]interface{}{
+		"query":       "golang concurrency",
+		"max_results": float64(3),
+		"structured":  true,
+	}}
+	output := ExecuteTool(ctx, toolMap, call, 0)
+
+	var results []SearchResult
+	if err := json.Unmarshal([]byte(output), &results); err != nil {
+		t.Fatalf("Expected JSON results, got %q: %v", output, err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(results))
+	}
+	for _, url := range []string{"https://golang.org", "https://golang.org/doc/effective_go#concurrency"} {
+		if !strings.Contains(output, url) {
+			t.Errorf("Expected JSON to contain URL %s, got %s", url, output)
+		}
+	}
+
+	// Formatted text remains the default
+	call.Args["structured"] = false
+	if output := ExecuteTool(ctx, toolMap, call, 0); !strings.HasPrefix(output, "1. The Go Programming Language") {
+		t.Errorf("Expected formatted text by default, got %q", output)
+	}
+
+	direct, err := search.SearchStructured(ctx, "golang", 2)
+	if err != nil {
+		t.Fatalf("SearchStructured failed: %v", err)
+	}
+	if len(direct) != 2 || direct[0].URL != "https://golang.org" {
+		t.Errorf("Unexpected structured results: %+v", direct)
+	}
+}
+
+func TestWebSearch_SimulationOffByDefault(t *testing.T) {
+	search := NewWebSearch(SearchEngineGoogle)
+	toolMap := map[string]Tool{search.Name(): search}
+	ctx := context.Background()
+
+	results, err := search.SearchStructured(ctx, "golang", 3)
+	if err == nil || !strings.Contains(err.Error(), "not implemented") {
+		t.Fatalf("Expected an error for an unimplemented engine, got %v with results %+v", err, results)
+	}
+
+	call := &ToolCall{Name: "search", Args: map[string]interface{}{"query": "golang"}}
+	if output := ExecuteTool(ctx, toolMap, call, 0); strings.Contains(output, "example.com") || !strings.Contains(output, "not implemented") {
+		t.Errorf("Expected an error instead of simulated links, got %q", output)
+	}
+}
+
+func TestWebSearch_DuckDuckGoErrorStatus(t *testing.T) {
+	var userAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.Header.Get("User-Agent")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte("<html>Service Unavailable</html>"))
+	}))
+	defer server.Close()
+
+	search := NewWebSearch(SearchEngineDuckDuckGo)
+	search.endpoint = server.URL
+	search.SetUserAgent("verifier-test/1.0")
+
+	results, err := search.SearchStructured(context.Background(), "golang", 3)
+	if err == nil || !strings.Contains(err.Error(), "status 503") {
+		t.Fatalf("Expected a 503 error, got %v with results %+v", err, results)
+	}
+	if userAgent != "verifier-test/1.0" {
+		t.Errorf("Expected the configured User-Agent, got %q", userAgent)
+	}
+}
+
+func TestWebSearch_DuckDuckGoNoResults(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"Abstract": "", "RelatedTopics": []}`))
+	}))
+	defer server.Close()
+
+	search := NewWebSearch(SearchEngineDuckDuckGo)
+	search.endpoint = server.URL
+	ctx := context.Background()
+
+	results, err := search.SearchStructured(ctx, "golang", 3)
+	if err != nil || len(results) != 0 {
+		t.Fatalf("Expected no results without simulation, got %+v, %v", results, err)
+	}
+
+	search.EnableSimulation(true)
+	results, err = search.SearchStructured(ctx, "golang", 3)
+	if err != nil || len(results) == 0 {
+		t.Fatalf("Expected simulated results once enabled, got %+v, %v", results, err)
+	}
+}
+
+func TestSearchCache_UsesCacheWithinTTL(t *testing.T) {
+	cache := NewCachedWebSearch(SearchEngineGoogle, time.Minute, 0)
+
+	searches := 0
+	cache.search = func(ctx context.Context, query string, maxResults int) ([]SearchResult, error) {
+		searches++
+		return []SearchResult{{Title: query, URL: "https://example.com"}}, nil
+	}
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	args := map[string]interface{}{"query": "golang"}
+
+	for i := 0; i < 2; i++ {
+		if _, err := cache.Execute(ctx, args); err != nil {
+			t.Fatalf("Execute failed: %v", err)
+		}
+	}
+	if searches != 1 {
+		t.Errorf("Expected one search for two identical queries within the TTL, got %d", searches)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, err := cache.Execute(ctx, args); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if searches != 2 {
+		t.Errorf("Expected a new search after the TTL expired, got %d searches", searches)
+	}
+}
+
+func TestSearchCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	cache := NewCachedWebSearch(SearchEngineGoogle, time.Minute, 2)
+
+	searched := make(map[string]int)
+	cache.search = func(ctx context.Context, query string, maxResults int) ([]SearchResult, error) {
+		searched[query]++
+		return []SearchResult{{Title: query}}, nil
+	}
+
+	ctx := context.Background()
+	run := func(query string) {
+		if _, err := cache.Execute(ctx, map[string]interface{}{"query": query}); err != nil {
+			t.Fatalf("Execute(%q) failed: %v", query, err)
+		}
+	}
+
+	run("a")
+	run("b")
+	run("a") // a is now more recently used than b
+	run("c") // evicts b
+
+	if cache.Len() != 2 {
+		t.Errorf("Expected 2 cached entries, got %d", cache.Len())
+	}
+
+	run("a")
+	if searched["a"] != 1 {
+		t.Errorf("Expected a to stay cached, searched %d times", searched["a"])
+	}
+	run("b")
+	if searched["b"] != 2 {
+		t.Errorf("Expected b to be evicted and searched again, searched %d times", searched["b"])
+	}
+}
+
+func TestSearchCache_SweepsExpiredEntries(t *testing.T) {
+	cache := NewCachedWebSearch(SearchEngineGoogle, time.Minute, 0)
+	cache.search = func(ctx context.Context, query string, maxResults int) ([]SearchResult, error) {
+		return []SearchResult{{Title: query}}, nil
+	}
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+	cache.lastSweep = now
+
+	ctx := context.Background()
+	for _, query := range []string{"a", "b", "c"} {
+		if _, err := cache.Execute(ctx, map[string]interface{}{"query": query}); err != nil {
+			t.Fatalf("Execute failed: %v", err)
+		}
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, err := cache.Execute(ctx, map[string]interface{}{"query": "d"}); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if cache.Len() != 1 {
+		t.Errorf("Expected expired entries to be swept, %d entries remain", cache.Len())
+	}
+}
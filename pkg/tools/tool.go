@@ -4,8 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"reflect"
+	"sort"
+	"strconv"
 	"strings"
+	"unicode/utf8"
 )
 
 // Tool represents a callable tool interface
@@ -33,6 +37,37 @@ type ArgumentSchema struct {
 	Required    bool        `json:"required"`
 }
 
+// Parameters returns the arguments as a JSON Schema object, the form expected by
+// native tool calling APIs
+func (s ToolSchema) Parameters() map[string]interface{} {
+	properties := make(map[string]interface{}, len(s.Args))
+	required := make([]string, 0)
+	
+	for name, arg := range s.Args {
+		property := map[string]interface{}{
+			"type": arg.Type,
+		}
+		if arg.Description != "" {
+			property["description"] = arg.Description
+		}
+		if arg.Default != nil {
+			property["default"] = arg.Default
+		}
+		properties[name] = property
+		
+		if arg.Required {
+			required = append(required, name)
+		}
+	}
+	sort.Strings(required)
+	
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	}
+}
+
 // ToolCall represents a JSON tool call
 type ToolCall struct {
 	Name string                 `json:"name"`
@@ -41,10 +76,17 @@ type ToolCall struct {
 
 // BaseTool provides common tool functionality
 type BaseTool struct {
-	name        string
-	description string
-	schema      ToolSchema
-	executor    func(context.Context, map[string]interface{}) (interface{}, error)
+	name           string
+	description    string
+	schema         ToolSchema
+	executor       func(context.Context, map[string]interface{}) (interface{}, error)
+	maxResultChars int
+}
+
+// ResultLimiter is optionally implemented by tools that set their own result length
+// limit. A positive MaxResultChars overrides the limit passed to ExecuteTool.
+type ResultLimiter interface {
+	MaxResultChars() int
 }
 
 // NewBaseTool creates a new base tool
@@ -83,6 +125,17 @@ func (t *BaseTool) Schema() ToolSchema {
 	return t.schema
 }
 
+// MaxResultChars returns the tool's result length limit; 0 defers to the caller
+func (t *BaseTool) MaxResultChars() int {
+	return t.maxResultChars
+}
+
+// SetMaxResultChars sets the tool's result length limit, in characters. It overrides
+// the limit of the environment executing the tool; 0 defers to the environment.
+func (t *BaseTool) SetMaxResultChars(n int) {
+	t.maxResultChars = n
+}
+
 // SetSchema updates the tool schema
 func (t *BaseTool) SetSchema(schema ToolSchema) {
 	t.schema = schema
@@ -147,8 +200,33 @@ func ParseToolCall(jsonStr string) (*ToolCall, error) {
 	return &call, nil
 }
 
-// ExecuteTool executes a tool by name with the given arguments
-func ExecuteTool(ctx context.Context, tools map[string]Tool, toolCall *ToolCall, maxChars int) string {
+// executeConfig holds the settings applied by ExecuteOptions
+type executeConfig struct {
+	skipValidation bool
+}
+
+// ExecuteOption configures optional ExecuteTool behavior
+type ExecuteOption func(*executeConfig)
+
+// WithoutValidation skips checking the arguments against the tool schema, for tools
+// that handle missing or loosely typed arguments themselves. Arguments are still
+// coerced to their schema types; if that fails, the tool gets the raw arguments.
+func WithoutValidation() ExecuteOption {
+	return func(c *executeConfig) {
+		c.skipValidation = true
+	}
+}
+
+// ExecuteTool executes a tool by name with the given arguments.
+// Arguments are coerced to their schema types and validated before the tool runs;
+// a validation failure is returned as an "Error: ..." message naming the problem so
+// the model can correct its call.
+func ExecuteTool(ctx context.Context, tools map[string]Tool, toolCall *ToolCall, maxChars int, opts ...ExecuteOption) string {
+	config := executeConfig{}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	
 	tool, exists := tools[toolCall.Name]
 	if !exists {
 		availableTools := make([]string, 0, len(tools))
@@ -159,8 +237,24 @@ func ExecuteTool(ctx context.Context, tools map[string]Tool, toolCall *ToolCall,
 			toolCall.Name, strings.Join(availableTools, ", "))
 	}
 	
+	// Convert arguments to the declared types, e.g. JSON numbers to integers. Without
+	// validation, arguments that do not convert reach the tool as the model sent them.
+	args, err := CoerceArgs(tool.Schema(), toolCall.Args)
+	if err != nil {
+		if !config.skipValidation {
+			return fmt.Sprintf("Error: invalid arguments for tool '%s': %v", toolCall.Name, err)
+		}
+		args = toolCall.Args
+	}
+	
+	if !config.skipValidation {
+		if err := ValidateArgs(tool.Schema(), args); err != nil {
+			return fmt.Sprintf("Error: invalid arguments for tool '%s': %v", toolCall.Name, err)
+		}
+	}
+	
 	// Execute the tool
-	result, err := tool.Execute(ctx, toolCall.Args)
+	result, err := tool.Execute(ctx, args)
 	if err != nil {
 		return fmt.Sprintf("Error: %v", err)
 	}
@@ -181,12 +275,32 @@ func ExecuteTool(ctx context.Context, tools map[string]Tool, toolCall *ToolCall,
 		}
 	}
 	
-	// Truncate if needed
-	if maxChars > 0 && len(resultStr) > maxChars {
-		resultStr = resultStr[:maxChars] + "..."
+	// Truncate if needed, preferring the tool's own limit
+	if limiter, ok := tool.(ResultLimiter); ok && limiter.MaxResultChars() > 0 {
+		maxChars = limiter.MaxResultChars()
 	}
-	
-	return resultStr
+	return TruncateResult(resultStr, maxChars)
+}
+
+// TruncateResult shortens result to at most maxChars characters, cutting on a rune
+// boundary, and appends a note with the number of characters removed.
+// A maxChars of 0 or less means no limit.
+func TruncateResult(result string, maxChars int) string {
+	if maxChars <= 0 || utf8.RuneCountInString(result) <= maxChars {
+		return result
+	}
+
+	// Byte offset of the first rune past the limit
+	cut, runes := 0, 0
+	for cut = range result {
+		if runes == maxChars {
+			break
+		}
+		runes++
+	}
+
+	removed := utf8.RuneCountInString(result[cut:])
+	return fmt.Sprintf("%s\n...[truncated %d chars]", result[:cut], removed)
 }
 
 // ValidateArgs validates tool arguments against the schema
@@ -207,8 +321,11 @@ func ValidateArgs(schema ToolSchema, args map[string]interface{}) error {
 			continue // Allow extra arguments for flexibility
 		}
 		
-		// Basic type checking
+		// Basic type checking; null leaves the argument to the tool's default
 		valueType := reflect.TypeOf(argValue)
+		if valueType == nil {
+			continue
+		}
 		switch argSchema.Type {
 		case "string":
 			if valueType.Kind() != reflect.String {
@@ -238,4 +355,78 @@ func ValidateArgs(schema ToolSchema, args map[string]interface{}) error {
 	}
 	
 	return nil
-}
\ No newline at end of file
+}
+
+// CoerceArgs returns a copy of args with each value converted to the type its
+// schema declares: numeric strings and integral floats become int for integer
+// arguments, numeric strings and integers become float64 for number arguments, and
+// "true"/"false" strings become bool for boolean arguments. Arguments missing from
+// the schema, and values already of the right type, are passed through unchanged.
+func CoerceArgs(schema ToolSchema, args map[string]interface{}) (map[string]interface{}, error) {
+	coerced := make(map[string]interface{}, len(args))
+	for argName, argValue := range args {
+		argSchema, exists := schema.Args[argName]
+		if !exists || argValue == nil {
+			coerced[argName] = argValue
+			continue
+		}
+
+		value, err := coerceValue(argSchema.Type, argValue)
+		if err != nil {
+			return nil, fmt.Errorf("argument %s: %w", argName, err)
+		}
+		coerced[argName] = value
+	}
+	return coerced, nil
+}
+
+// maxExactFloat is the largest integer magnitude float64 represents exactly
+const maxExactFloat = 1 << 53
+
+// coerceValue converts a single value to the named schema type
+func coerceValue(argType string, value interface{}) (interface{}, error) {
+	switch argType {
+	case "int", "integer":
+		switch v := value.(type) {
+		case int:
+			return v, nil
+		case int8, int16, int32, int64, float32, float64:
+			f := reflect.ValueOf(v).Convert(reflect.TypeOf(float64(0))).Float()
+			if f != math.Trunc(f) || math.Abs(f) > maxExactFloat {
+				return nil, fmt.Errorf("%v is not an integer", v)
+			}
+			return int(f), nil
+		case string:
+			s := strings.TrimSpace(v)
+			if n, err := strconv.Atoi(s); err == nil {
+				return n, nil
+			}
+			if f, err := strconv.ParseFloat(s, 64); err == nil && f == math.Trunc(f) && math.Abs(f) <= maxExactFloat {
+				return int(f), nil
+			}
+			return nil, fmt.Errorf("cannot convert %q to an integer", v)
+		}
+	case "float", "number":
+		switch v := value.(type) {
+		case float64:
+			return v, nil
+		case int, int8, int16, int32, int64, float32:
+			return reflect.ValueOf(v).Convert(reflect.TypeOf(float64(0))).Float(), nil
+		case string:
+			f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+			if err != nil {
+				return nil, fmt.Errorf("cannot convert %q to a number", v)
+			}
+			return f, nil
+		}
+	case "bool", "boolean":
+		if s, ok := value.(string); ok {
+			b, err := strconv.ParseBool(strings.TrimSpace(s))
+			if err != nil {
+				return nil, fmt.Errorf("cannot convert %q to a boolean", s)
+			}
+			return b, nil
+		}
+	}
+	return value, nil
+}
@@ -0,0 +1,187 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestCoerceArgs(t *testing.T) {
+	schema := ToolSchema{
+		Args: map[string]ArgumentSchema{
+			"count":   {Type: "integer"},
+			"ratio":   {Type: "number"},
+			"verbose": {Type: "boolean"},
+			"query":   {Type: "string"},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		arg     string
+		value   interface{}
+		want    interface{}
+		wantErr bool
+	}{
+		{name: "string to int", arg: "count", value: "5", want: 5},
+		{name: "float to int", arg: "count", value: 5.0, want: 5},
+		{name: "int unchanged", arg: "count", value: 5, want: 5},
+		{name: "fractional float rejected", arg: "count", value: 5.5, wantErr: true},
+		{name: "non-numeric string rejected", arg: "count", value: "five", wantErr: true},
+		{name: "int to number", arg: "ratio", value: 2, want: 2.0},
+		{name: "string to number", arg: "ratio", value: "0.25", want: 0.25},
+		{name: "string to bool", arg: "verbose", value: "true", want: true},
+		{name: "string unchanged", arg: "query", value: "5", want: "5"},
+		{name: "unknown arg unchanged", arg: "extra", value: "5", want: "5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := map[string]interface{}{tt.arg: tt.value}
+			got, err := CoerceArgs(schema, args)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CoerceArgs() error = %v", err)
+			}
+			if got[tt.arg] != tt.want {
+				t.Errorf("CoerceArgs()[%s] = %#v, want %#v", tt.arg, got[tt.arg], tt.want)
+			}
+			if args[tt.arg] != tt.value {
+				t.Errorf("Expected input args to be left unchanged, got %#v", args[tt.arg])
+			}
+		})
+	}
+}
+
+func TestExecuteTool_CoercesArgs(t *testing.T) {
+	repeat := NewBaseTool("repeat", "Repeats a word", func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		times, ok := args["times"].(int)
+		if !ok {
+			return nil, fmt.Errorf("times must be an int, got %T", args["times"])
+		}
+		return fmt.Sprintf("%s x%d", args["word"], times), nil
+	})
+	repeat.SetSchema(ToolSchema{
+		Name: "repeat",
+		Args: map[string]ArgumentSchema{
+			"word":  {Type: "string", Required: true},
+			"times": {Type: "integer", Required: true},
+		},
+	})
+
+	call, err := ParseToolCall(`{"name": "repeat", "args": {"word": "go", "times": 3}}`)
+	if err != nil {
+		t.Fatalf("ParseToolCall failed: %v", err)
+	}
+
+	toolMap := map[string]Tool{"repeat": repeat}
+	if got := ExecuteTool(context.Background(), toolMap, call, 0); got != "go x3" {
+		t.Errorf("ExecuteTool() = %q, want %q", got, "go x3")
+	}
+}
+
+func TestExecuteTool_ValidatesArgs(t *testing.T) {
+	calls := 0
+	echo := NewBaseTool("echo", "Echoes a message", func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		calls++
+		return fmt.Sprintf("%v", args["message"]), nil
+	})
+	echo.SetSchema(ToolSchema{
+		Name: "echo",
+		Args: map[string]ArgumentSchema{
+			"message": {Type: "string", Required: true},
+		},
+	})
+	toolMap := map[string]Tool{"echo": echo}
+	call := &ToolCall{Name: "echo", Args: map[string]interface{}{}}
+	ctx := context.Background()
+
+	got := ExecuteTool(ctx, toolMap, call, 0)
+	if !strings.HasPrefix(got, "Error:") || !strings.Contains(got, "missing required argument: message") {
+		t.Errorf("Expected missing argument error naming 'message', got %q", got)
+	}
+	if calls != 0 {
+		t.Errorf("Expected tool not to run with invalid args, ran %d times", calls)
+	}
+
+	// Lenient tools can opt out
+	if got := ExecuteTool(ctx, toolMap, call, 0, WithoutValidation()); got != "<nil>" {
+		t.Errorf("Expected tool to run without validation, got %q", got)
+	}
+	if calls != 1 {
+		t.Errorf("Expected tool to run once, ran %d times", calls)
+	}
+}
+
+func TestExecuteTool_WithoutValidationPassesUncoercedArgs(t *testing.T) {
+	count := NewBaseTool("count", "Repeats a word", func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		return fmt.Sprintf("%v", args["times"]), nil
+	})
+	count.SetSchema(ToolSchema{
+		Name: "count",
+		Args: map[string]ArgumentSchema{
+			"times": {Type: "integer", Required: true},
+		},
+	})
+	toolMap := map[string]Tool{"count": count}
+	call := &ToolCall{Name: "count", Args: map[string]interface{}{"times": "a few"}}
+	ctx := context.Background()
+
+	if got := ExecuteTool(ctx, toolMap, call, 0); !strings.HasPrefix(got, "Error: invalid arguments") {
+		t.Errorf("Expected a coercion error, got %q", got)
+	}
+	if got := ExecuteTool(ctx, toolMap, call, 0, WithoutValidation()); got != "a few" {
+		t.Errorf("Expected the raw argument without validation, got %q", got)
+	}
+}
+
+func TestTruncateResult(t *testing.T) {
+	tests := []struct {
+		name     string
+		result   string
+		maxChars int
+		want     string
+	}{
+		{name: "under limit", result: "héllo", maxChars: 5, want: "héllo"},
+		{name: "no limit", result: "héllo", maxChars: 0, want: "héllo"},
+		{name: "multibyte at boundary", result: "日本語のテキスト", maxChars: 3, want: "日本語\n...[truncated 5 chars]"},
+		{name: "ascii", result: "abcdefgh", maxChars: 5, want: "abcde\n...[truncated 3 chars]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := TruncateResult(tt.result, tt.maxChars)
+			if !utf8.ValidString(got) {
+				t.Errorf("TruncateResult() returned invalid UTF-8: %q", got)
+			}
+			if got != tt.want {
+				t.Errorf("TruncateResult() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecuteTool_ToolResultLimit(t *testing.T) {
+	long := NewBaseTool("long", "Returns a long result", func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		return strings.Repeat("é", 100), nil
+	})
+	toolMap := map[string]Tool{"long": long}
+	call := &ToolCall{Name: "long", Args: map[string]interface{}{}}
+	ctx := context.Background()
+
+	if got := ExecuteTool(ctx, toolMap, call, 10); got != strings.Repeat("é", 10)+"\n...[truncated 90 chars]" {
+		t.Errorf("Expected the caller's limit, got %q", got)
+	}
+
+	long.SetMaxResultChars(50)
+	if got := ExecuteTool(ctx, toolMap, call, 10); got != strings.Repeat("é", 50)+"\n...[truncated 50 chars]" {
+		t.Errorf("Expected the tool's own limit, got %q", got)
+	}
+}
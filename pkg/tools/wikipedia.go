@@ -0,0 +1,275 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// wikipediaBaseURL is the English Wikipedia, whose REST API serves page summaries
+// and search
+const wikipediaBaseURL = "https://en.wikipedia.org"
+
+// wikipediaSearchLimit is the number of candidates listed for ambiguous titles
+const wikipediaSearchLimit = 5
+
+// WikipediaTool looks up facts in Wikipedia. It fetches the summary of the page
+// with the requested title and falls back to a search when no page has that
+// exact title. Ambiguous titles return a list of candidate articles.
+type WikipediaTool struct {
+	*BaseTool
+	httpClient *http.Client
+	userAgent  string
+	baseURL    string // Wikipedia site URL, replaced in tests
+}
+
+// NewWikipediaTool creates a Wikipedia lookup tool
+func NewWikipediaTool() *WikipediaTool {
+	tool := &WikipediaTool{
+		BaseTool: NewBaseTool(
+			"wikipedia",
+			"Look up the summary of a Wikipedia article",
+			nil, // Set below
+		),
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		userAgent: DefaultUserAgent,
+		baseURL:   wikipediaBaseURL,
+	}
+
+	// Set the executor
+	tool.executor = tool.execute
+
+	// Define schema
+	tool.schema = ToolSchema{
+		Name:        "wikipedia",
+		Description: tool.description,
+		Args: map[string]ArgumentSchema{
+			"query": {
+				Type:        "string",
+				Description: "Article title or search terms",
+				Required:    true,
+			},
+			"sentences": {
+				Type:        "integer",
+				Description: "Maximum number of sentences of the summary to return; 0 returns all of it",
+				Default:     0,
+				Required:    false,
+			},
+		},
+		Returns: "The article title, summary and URL",
+		Examples: []string{
+			`{"name": "wikipedia", "args": {"query": "Alan Turing"}}`,
+			`{"name": "wikipedia", "args": {"query": "Go (programming language)", "sentences": 2}}`,
+		},
+	}
+
+	return tool
+}
+
+// SetUserAgent sets the User-Agent header sent to Wikipedia, which asks clients to
+// identify themselves. An empty string restores DefaultUserAgent.
+func (w *WikipediaTool) SetUserAgent(userAgent string) {
+	if userAgent == "" {
+		userAgent = DefaultUserAgent
+	}
+	w.userAgent = userAgent
+}
+
+// wikipediaSummary is the part of a REST page summary the tool uses
+type wikipediaSummary struct {
+	Type        string `json:"type"`
+	Title       string `json:"title"`
+	Extract     string `json:"extract"`
+	ContentURLs struct {
+		Desktop struct {
+			Page string `json:"page"`
+		} `json:"desktop"`
+	} `json:"content_urls"`
+}
+
+// wikipediaPage is a search result of the REST search API
+type wikipediaPage struct {
+	Key   string `json:"key"`
+	Title string `json:"title"`
+}
+
+// execute looks up the requested article
+func (w *WikipediaTool) execute(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	queryInterface, ok := args["query"]
+	if !ok {
+		return nil, fmt.Errorf("missing required argument 'query'")
+	}
+
+	query, ok := queryInterface.(string)
+	if !ok {
+		return nil, fmt.Errorf("query must be a string")
+	}
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, fmt.Errorf("query must not be empty")
+	}
+	sentences := intArg(args, "sentences", 0)
+
+	summary, err := w.fetchSummary(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// No page has this exact title; use the best search match instead
+	if summary == nil {
+		pages, err := w.searchPages(ctx, query, 1)
+		if err != nil {
+			return nil, err
+		}
+		if len(pages) == 0 {
+			return nil, fmt.Errorf("no Wikipedia article found for %q; try a different spelling or more general terms", query)
+		}
+		summary, err = w.fetchSummary(ctx, pages[0].Key)
+		if err != nil {
+			return nil, err
+		}
+		if summary == nil {
+			return nil, fmt.Errorf("no Wikipedia article found for %q; try a different spelling or more general terms", query)
+		}
+	}
+
+	if summary.Type == "disambiguation" {
+		return w.disambiguate(ctx, query, summary)
+	}
+
+	return formatWikipediaSummary(summary, sentences), nil
+}
+
+// disambiguate lists candidate articles for an ambiguous title
+func (w *WikipediaTool) disambiguate(ctx context.Context, query string, summary *wikipediaSummary) (interface{}, error) {
+	pages, err := w.searchPages(ctx, query, wikipediaSearchLimit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	titles := make([]string, 0, len(pages))
+	for _, page := range pages {
+		if page.Title != summary.Title && len(titles) < wikipediaSearchLimit {
+			titles = append(titles, page.Title)
+		}
+	}
+	if len(titles) == 0 {
+		return fmt.Sprintf("%q is ambiguous. Search again with a more specific title.", summary.Title), nil
+	}
+	return fmt.Sprintf("%q is ambiguous and may refer to: %s. Search again with one of these titles.",
+		summary.Title, strings.Join(titles, "; ")), nil
+}
+
+// fetchSummary returns the summary of the page titled title, or nil if there is
+// no such page
+func (w *WikipediaTool) fetchSummary(ctx context.Context, title string) (*wikipediaSummary, error) {
+	apiURL := fmt.Sprintf("%s/api/rest_v1/page/summary/%s",
+		w.baseURL, url.PathEscape(strings.ReplaceAll(title, " ", "_")))
+
+	body, status, err := w.get(ctx, apiURL)
+	if err != nil {
+		return nil, err
+	}
+	if status == http.StatusNotFound {
+		return nil, nil
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("wikipedia returned status %d: %s", status, truncateBody(body))
+	}
+
+	var summary wikipediaSummary
+	if err := json.Unmarshal(body, &summary); err != nil {
+		return nil, fmt.Errorf("invalid wikipedia summary: %w", err)
+	}
+	return &summary, nil
+}
+
+// searchPages returns up to limit pages matching query
+func (w *WikipediaTool) searchPages(ctx context.Context, query string, limit int) ([]wikipediaPage, error) {
+	apiURL := fmt.Sprintf("%s/w/rest.php/v1/search/page?q=%s&limit=%d",
+		w.baseURL, url.QueryEscape(query), limit)
+
+	body, status, err := w.get(ctx, apiURL)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("wikipedia search returned status %d: %s", status, truncateBody(body))
+	}
+
+	var result struct {
+		Pages []wikipediaPage `json:"pages"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("invalid wikipedia search response: %w", err)
+	}
+	return result.Pages, nil
+}
+
+// get performs a GET request and returns the body and status code
+func (w *WikipediaTool) get(ctx context.Context, apiURL string) ([]byte, int, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("User-Agent", w.userAgent)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("wikipedia request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, err
+	}
+	return body, resp.StatusCode, nil
+}
+
+// formatWikipediaSummary renders a summary, keeping at most sentences sentences
+// of the extract when sentences is positive
+func formatWikipediaSummary(summary *wikipediaSummary, sentences int) string {
+	extract := strings.TrimSpace(summary.Extract)
+	if sentences > 0 {
+		extract = firstSentences(extract, sentences)
+	}
+
+	var output strings.Builder
+	output.WriteString(summary.Title)
+	output.WriteString("\n\n")
+	output.WriteString(extract)
+	if page := summary.ContentURLs.Desktop.Page; page != "" {
+		output.WriteString("\n\nSource: ")
+		output.WriteString(page)
+	}
+	return output.String()
+}
+
+// firstSentences returns the first n sentences of text. A sentence ends at '.',
+// '!' or '?' followed by whitespace or the end of the text.
+func firstSentences(text string, n int) string {
+	runes := []rune(text)
+	count := 0
+	for i, r := range runes {
+		if r != '.' && r != '!' && r != '?' {
+			continue
+		}
+		if i+1 < len(runes) && runes[i+1] != ' ' && runes[i+1] != '\n' {
+			continue
+		}
+		count++
+		if count == n {
+			return string(runes[:i+1])
+		}
+	}
+	return text
+}
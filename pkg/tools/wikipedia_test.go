@@ -0,0 +1,107 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newWikipediaFixture serves canned summaries and search results for a few titles
+func newWikipediaFixture(t *testing.T) *WikipediaTool {
+	t.Helper()
+	summaries := map[string]string{
+		"Go_(programming_language)": `{"type": "standard", "title": "Go (programming language)",
+			"extract": "Go is a statically typed, compiled language. It was designed at Google. It is often referred to as Golang.",
+			"content_urls": {"desktop": {"page": "https://en.wikipedia.org/wiki/Go_(programming_language)"}}}`,
+		"Mercury": `{"type": "disambiguation", "title": "Mercury", "extract": "Mercury may refer to:"}`,
+	}
+	searches := map[string]string{
+		"golang":  `{"pages": [{"key": "Go_(programming_language)", "title": "Go (programming language)"}]}`,
+		"Mercury": `{"pages": [{"key": "Mercury", "title": "Mercury"}, {"key": "Mercury_(planet)", "title": "Mercury (planet)"}, {"key": "Mercury_(element)", "title": "Mercury (element)"}]}`,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/rest_v1/page/summary/", func(w http.ResponseWriter, r *http.Request) {
+		summary, ok := summaries[strings.TrimPrefix(r.URL.Path, "/api/rest_v1/page/summary/")]
+		if !ok {
+			http.Error(w, `{"type": "https://mediawiki.org/wiki/HyperSwitch/errors/not_found"}`, http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(summary))
+	})
+	mux.HandleFunc("/w/rest.php/v1/search/page", func(w http.ResponseWriter, r *http.Request) {
+		result, ok := searches[r.URL.Query().Get("q")]
+		if !ok {
+			result = `{"pages": []}`
+		}
+		w.Write([]byte(result))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	tool := NewWikipediaTool()
+	tool.baseURL = server.URL
+	return tool
+}
+
+func TestWikipediaTool_Summary(t *testing.T) {
+	tool := newWikipediaFixture(t)
+
+	tests := []struct {
+		name     string
+		args     map[string]interface{}
+		expected []string
+	}{
+		{
+			name: "exact title",
+			args: map[string]interface{}{"query": "Go (programming language)"},
+			expected: []string{
+				"Go (programming language)\n\nGo is a statically typed",
+				"It is often referred to as Golang.",
+				"Source: https://en.wikipedia.org/wiki/Go_(programming_language)",
+			},
+		},
+		{
+			name:     "search fallback",
+			args:     map[string]interface{}{"query": "golang"},
+			expected: []string{"Go (programming language)"},
+		},
+		{
+			name:     "sentences",
+			args:     map[string]interface{}{"query": "Go (programming language)", "sentences": float64(2)},
+			expected: []string{"It was designed at Google.\n\nSource:"},
+		},
+		{
+			name:     "disambiguation",
+			args:     map[string]interface{}{"query": "Mercury"},
+			expected: []string{`"Mercury" is ambiguous`, "Mercury (planet); Mercury (element)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tool.Execute(context.Background(), tt.args)
+			if err != nil {
+				t.Fatalf("Execute failed: %v", err)
+			}
+			for _, want := range tt.expected {
+				if !strings.Contains(result.(string), want) {
+					t.Errorf("Expected %q in result, got %q", want, result)
+				}
+			}
+		})
+	}
+}
+
+func TestWikipediaTool_NotFound(t *testing.T) {
+	tool := newWikipediaFixture(t)
+	toolMap := map[string]Tool{tool.Name(): tool}
+
+	call := &ToolCall{Name: "wikipedia", Args: map[string]interface{}{"query": "Nonexistent article"}}
+	output := ExecuteTool(context.Background(), toolMap, call, 0)
+	if !strings.Contains(output, `no Wikipedia article found for "Nonexistent article"`) {
+		t.Errorf("Expected a not found message, got %q", output)
+	}
+}
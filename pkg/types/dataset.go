@@ -1,9 +1,13 @@
 package types
 
 import (
+	"bufio"
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"math/rand"
+	"os"
 	"sync"
 )
 
@@ -27,12 +31,13 @@ func (d *SimpleDataset) Len() int {
 	return len(d.data)
 }
 
-// Get returns the item at the specified index
+// Get returns the item at the specified index, or nil if it is out of range or a
+// nil placeholder for an item that could not be read
 func (d *SimpleDataset) Get(idx int) map[string]interface{} {
 	d.mu.RLock()
 	defer d.mu.RUnlock()
 	
-	if idx < 0 || idx >= len(d.data) {
+	if idx < 0 || idx >= len(d.data) || d.data[idx] == nil {
 		return nil
 	}
 	
@@ -49,19 +54,21 @@ func (d *SimpleDataset) Shuffle(seed int64) Dataset {
 	d.mu.RLock()
 	defer d.mu.RUnlock()
 	
-	// Create a copy of the data
+	// Copy the data in the order of the seeded permutation
 	newData := make([]map[string]interface{}, len(d.data))
-	copy(newData, d.data)
-	
-	// Shuffle the copy
-	r := rand.New(rand.NewSource(seed))
-	r.Shuffle(len(newData), func(i, j int) {
-		newData[i], newData[j] = newData[j], newData[i]
-	})
+	for i, idx := range shuffledIndices(len(d.data), seed) {
+		newData[i] = d.data[idx]
+	}
 	
 	return NewSimpleDataset(newData)
 }
 
+// ShuffleIndices returns the permutation Shuffle applies for seed without copying
+// any data, so a sample can be taken with Select(indices[:n])
+func (d *SimpleDataset) ShuffleIndices(seed int64) []int {
+	return shuffledIndices(d.Len(), seed)
+}
+
 // Select returns a new dataset with only the specified indices
 func (d *SimpleDataset) Select(indices []int) Dataset {
 	d.mu.RLock()
@@ -70,6 +77,10 @@ func (d *SimpleDataset) Select(indices []int) Dataset {
 	newData := make([]map[string]interface{}, 0, len(indices))
 	for _, idx := range indices {
 		if idx >= 0 && idx < len(d.data) {
+			if d.data[idx] == nil {
+				newData = append(newData, nil)
+				continue
+			}
 			// Deep copy the item
 			item := make(map[string]interface{})
 			for k, v := range d.data[idx] {
@@ -82,13 +93,17 @@ func (d *SimpleDataset) Select(indices []int) Dataset {
 	return NewSimpleDataset(newData)
 }
 
-// Map applies a function to each item and returns a new dataset
+// Map applies a function to each item and returns a new dataset. Nil placeholders
+// are kept as they are.
 func (d *SimpleDataset) Map(fn func(map[string]interface{}) map[string]interface{}) Dataset {
 	d.mu.RLock()
 	defer d.mu.RUnlock()
 	
 	newData := make([]map[string]interface{}, len(d.data))
 	for i, item := range d.data {
+		if item == nil {
+			continue
+		}
 		// Create a copy of the item
 		itemCopy := make(map[string]interface{})
 		for k, v := range item {
@@ -101,6 +116,92 @@ func (d *SimpleDataset) Map(fn func(map[string]interface{}) map[string]interface
 	return NewSimpleDataset(newData)
 }
 
+// MapErr applies a function that can fail to each item and returns a new dataset.
+// It stops at the first error, which is wrapped with the offending item's index.
+// A nil placeholder for an unreadable item is an error.
+func (d *SimpleDataset) MapErr(fn func(map[string]interface{}) (map[string]interface{}, error)) (Dataset, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	
+	newData := make([]map[string]interface{}, len(d.data))
+	for i, item := range d.data {
+		if item == nil {
+			return nil, fmt.Errorf("item %d: missing", i)
+		}
+		// Create a copy of the item
+		itemCopy := make(map[string]interface{})
+		for k, v := range item {
+			itemCopy[k] = v
+		}
+		mapped, err := fn(itemCopy)
+		if err != nil {
+			return nil, fmt.Errorf("item %d: %w", i, err)
+		}
+		newData[i] = mapped
+	}
+	
+	return NewSimpleDataset(newData), nil
+}
+
+// MessagesFromItem reads a list of messages stored under key in a dataset item.
+// It accepts []Message as well as the []interface{} / []map[string]interface{}
+// shapes produced by JSON decoding. A missing key returns nil without error.
+func MessagesFromItem(item map[string]interface{}, key string) ([]Message, error) {
+	raw, ok := item[key]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+
+	switch v := raw.(type) {
+	case []Message:
+		messages := make([]Message, len(v))
+		copy(messages, v)
+		return messages, nil
+	case []map[string]interface{}:
+		messages := make([]Message, 0, len(v))
+		for i, m := range v {
+			msg, err := messageFromMap(m)
+			if err != nil {
+				return nil, fmt.Errorf("%s[%d]: %w", key, i, err)
+			}
+			messages = append(messages, msg)
+		}
+		return messages, nil
+	case []interface{}:
+		messages := make([]Message, 0, len(v))
+		for i, entry := range v {
+			switch m := entry.(type) {
+			case Message:
+				messages = append(messages, m)
+			case map[string]interface{}:
+				msg, err := messageFromMap(m)
+				if err != nil {
+					return nil, fmt.Errorf("%s[%d]: %w", key, i, err)
+				}
+				messages = append(messages, msg)
+			default:
+				return nil, fmt.Errorf("%s[%d]: expected message object, got %T", key, i, entry)
+			}
+		}
+		return messages, nil
+	default:
+		return nil, fmt.Errorf("%s: expected a list of messages, got %T", key, raw)
+	}
+}
+
+// messageFromMap converts a decoded JSON object into a Message
+func messageFromMap(m map[string]interface{}) (Message, error) {
+	role, ok := m["role"].(string)
+	if !ok || role == "" {
+		return Message{}, fmt.Errorf("message must have a string 'role'")
+	}
+	content, ok := m["content"].(string)
+	if !ok {
+		return Message{}, fmt.Errorf("message must have a string 'content'")
+	}
+	return Message{Role: role, Content: content}, nil
+}
+
 // DatasetBuilder helps construct datasets
 type DatasetBuilder struct {
 	data []map[string]interface{}
@@ -167,6 +268,80 @@ func (DatasetUtils) LoadFromQuestionAnswer(pairs []struct{ Question, Answer stri
 	return builder.Build()
 }
 
+// LoadFromJSONL reads a JSONL file with one JSON object per line
+func (u DatasetUtils) LoadFromJSONL(path string) (Dataset, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open dataset: %w", err)
+	}
+	defer file.Close()
+
+	return u.LoadFromJSONLReader(file)
+}
+
+// LoadFromJSONLReader reads JSONL from any reader, such as a gzip or network stream.
+// Blank lines are skipped; malformed lines return an error with the line number.
+func (DatasetUtils) LoadFromJSONLReader(r io.Reader) (Dataset, error) {
+	builder := NewDatasetBuilder()
+	reader := bufio.NewReader(r)
+
+	for lineNum := 1; ; lineNum++ {
+		line, err := reader.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return nil, fmt.Errorf("line %d: failed to read: %w", lineNum, err)
+		}
+
+		if trimmed := bytes.TrimSpace(line); len(trimmed) > 0 {
+			var item map[string]interface{}
+			if jsonErr := json.Unmarshal(trimmed, &item); jsonErr != nil {
+				return nil, fmt.Errorf("line %d: invalid JSON: %w", lineNum, jsonErr)
+			}
+			builder.Add(item)
+		}
+
+		if err == io.EOF {
+			break
+		}
+	}
+
+	return builder.Build(), nil
+}
+
+// WriteJSONL writes each item of d to w as a JSON line, in dataset order, so it
+// can be reloaded with LoadFromJSONLReader. Object keys are written in sorted
+// order, which keeps the output stable across runs.
+func (DatasetUtils) WriteJSONL(d Dataset, w io.Writer) error {
+	writer := bufio.NewWriter(w)
+	encoder := json.NewEncoder(writer)
+	encoder.SetEscapeHTML(false)
+
+	for i := 0; i < d.Len(); i++ {
+		// Maps are encoded with sorted keys
+		if err := encoder.Encode(d.Get(i)); err != nil {
+			return fmt.Errorf("item %d: %w", i, err)
+		}
+	}
+
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("failed to write dataset: %w", err)
+	}
+	return nil
+}
+
+// WriteJSONLFile writes d to a JSONL file at path, replacing any existing file
+func (u DatasetUtils) WriteJSONLFile(d Dataset, path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create dataset file: %w", err)
+	}
+
+	if err := u.WriteJSONL(d, file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
 // Filter filters a dataset based on a predicate
 func (DatasetUtils) Filter(dataset Dataset, predicate func(map[string]interface{}) bool) Dataset {
 	indices := make([]int, 0)
@@ -178,6 +353,20 @@ func (DatasetUtils) Filter(dataset Dataset, predicate func(map[string]interface{
 	return dataset.Select(indices)
 }
 
+// TrainTestSplit shuffles the dataset indices with seed and partitions them into
+// disjoint train and test sets. The same seed always produces the same split.
+// testFraction must be in (0, 1).
+func (DatasetUtils) TrainTestSplit(d Dataset, testFraction float64, seed int64) (Dataset, Dataset, error) {
+	if testFraction <= 0 || testFraction >= 1 {
+		return nil, nil, fmt.Errorf("test fraction must be in (0, 1), got %v", testFraction)
+	}
+
+	indices := shuffledIndices(d.Len(), seed)
+
+	testSize := int(float64(len(indices))*testFraction + 0.5)
+	return d.Select(indices[testSize:]), d.Select(indices[:testSize]), nil
+}
+
 // Concatenate combines multiple datasets
 func (DatasetUtils) Concatenate(datasets ...Dataset) Dataset {
 	builder := NewDatasetBuilder()
@@ -187,4 +376,14 @@ func (DatasetUtils) Concatenate(datasets ...Dataset) Dataset {
 		}
 	}
 	return builder.Build()
-}
\ No newline at end of file
+}
+
+// shuffledIndices returns a permutation of 0..n-1 determined by seed
+func shuffledIndices(n int, seed int64) []int {
+	indices := makeIndices(n)
+	r := rand.New(rand.NewSource(seed))
+	r.Shuffle(len(indices), func(i, j int) {
+		indices[i], indices[j] = indices[j], indices[i]
+	})
+	return indices
+}
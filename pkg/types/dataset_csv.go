@@ -0,0 +1,136 @@
+package types
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+)
+
+// CSVHeaderMode controls how the first row of a CSV file is interpreted
+type CSVHeaderMode string
+
+const (
+	// CSVHeaderPresent treats the first row as column names (default)
+	CSVHeaderPresent CSVHeaderMode = "present"
+	// CSVHeaderAbsent treats every row as data; columns are named "col0", "col1", ...
+	CSVHeaderAbsent CSVHeaderMode = "absent"
+	// CSVHeaderDetect treats the first row as a header only if it contains both named columns
+	CSVHeaderDetect CSVHeaderMode = "detect"
+)
+
+// CSVOptions configures CSV loading. Zero values use the defaults.
+type CSVOptions struct {
+	Delimiter rune          // Field delimiter (default ',')
+	Header    CSVHeaderMode // Header handling (default CSVHeaderPresent)
+}
+
+// LoadFromCSV reads a comma-separated file with a header row, mapping questionCol and
+// answerCol to the "question" and "answer" keys. Other columns are kept as extra fields.
+func (u DatasetUtils) LoadFromCSV(path string, questionCol, answerCol string) (Dataset, error) {
+	return u.LoadFromCSVWithOptions(path, questionCol, answerCol, CSVOptions{})
+}
+
+// LoadFromCSVWithOptions reads a CSV file with a custom delimiter or header handling
+func (u DatasetUtils) LoadFromCSVWithOptions(path string, questionCol, answerCol string, opts CSVOptions) (Dataset, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open dataset: %w", err)
+	}
+	defer file.Close()
+
+	return u.LoadFromCSVReader(file, questionCol, answerCol, opts)
+}
+
+// LoadFromCSVReader reads CSV data from any reader
+func (DatasetUtils) LoadFromCSVReader(r io.Reader, questionCol, answerCol string, opts CSVOptions) (Dataset, error) {
+	reader := csv.NewReader(r)
+	if opts.Delimiter != 0 {
+		reader.Comma = opts.Delimiter
+	}
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CSV: %w", err)
+	}
+	if len(records) == 0 {
+		return NewSimpleDataset(nil), nil
+	}
+
+	columns, rows := csvColumns(records, questionCol, answerCol, opts.Header)
+
+	questionIdx, answerIdx := -1, -1
+	for i, name := range columns {
+		switch name {
+		case questionCol:
+			questionIdx = i
+		case answerCol:
+			answerIdx = i
+		}
+	}
+	if questionIdx < 0 {
+		return nil, fmt.Errorf("question column %q not found in columns %v", questionCol, columns)
+	}
+	if answerIdx < 0 {
+		return nil, fmt.Errorf("answer column %q not found in columns %v", answerCol, columns)
+	}
+
+	builder := NewDatasetBuilder()
+	for _, row := range rows {
+		item := make(map[string]interface{}, len(row))
+		for i, value := range row {
+			switch i {
+			case questionIdx:
+				item["question"] = value
+			case answerIdx:
+				item["answer"] = value
+			default:
+				item[columns[i]] = value
+			}
+		}
+		builder.Add(item)
+	}
+
+	return builder.Build(), nil
+}
+
+// csvColumns returns the column names and the data rows according to the header mode
+func csvColumns(records [][]string, questionCol, answerCol string, mode CSVHeaderMode) ([]string, [][]string) {
+	first := records[0]
+
+	hasHeader := true
+	switch mode {
+	case CSVHeaderAbsent:
+		hasHeader = false
+	case CSVHeaderDetect:
+		hasHeader = containsAll(first, questionCol, answerCol)
+	}
+
+	if hasHeader {
+		return first, records[1:]
+	}
+
+	columns := make([]string, len(first))
+	for i := range columns {
+		columns[i] = "col" + strconv.Itoa(i)
+	}
+	return columns, records
+}
+
+// containsAll reports whether values contains every target
+func containsAll(values []string, targets ...string) bool {
+	for _, target := range targets {
+		found := false
+		for _, v := range values {
+			if v == target {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,244 @@
+package types
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDatasetUtils_LoadFromJSONL(t *testing.T) {
+	dir := t.TempDir()
+	utils := DatasetUtils{}
+
+	validPath := filepath.Join(dir, "valid.jsonl")
+	valid := "{\"question\": \"What is 2 + 2?\", \"answer\": \"4\"}\n\n{\"question\": \"What is 3 + 3?\", \"answer\": \"6\"}"
+	if err := os.WriteFile(validPath, []byte(valid), 0o644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	dataset, err := utils.LoadFromJSONL(validPath)
+	if err != nil {
+		t.Fatalf("LoadFromJSONL failed: %v", err)
+	}
+	if dataset.Len() != 2 {
+		t.Fatalf("Expected 2 items, got %d", dataset.Len())
+	}
+	if dataset.Get(1)["answer"] != "6" {
+		t.Errorf("Unexpected second item: %v", dataset.Get(1))
+	}
+
+	badPath := filepath.Join(dir, "bad.jsonl")
+	bad := "{\"answer\": \"1\"}\n{\"answer\": \"2\"}\n{not json}\n"
+	if err := os.WriteFile(badPath, []byte(bad), 0o644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	_, err = utils.LoadFromJSONL(badPath)
+	if err == nil || !strings.Contains(err.Error(), "line 3") {
+		t.Errorf("Expected error mentioning line 3, got %v", err)
+	}
+
+	if _, err := utils.LoadFromJSONL(filepath.Join(dir, "missing.jsonl")); err == nil {
+		t.Errorf("Expected error for missing file")
+	}
+}
+
+func TestDatasetUtils_LoadFromJSONLReader(t *testing.T) {
+	dataset, err := DatasetUtils{}.LoadFromJSONLReader(strings.NewReader("{\"a\": 1}\n{\"a\": 2}\n"))
+	if err != nil {
+		t.Fatalf("LoadFromJSONLReader failed: %v", err)
+	}
+	if dataset.Len() != 2 {
+		t.Errorf("Expected 2 items, got %d", dataset.Len())
+	}
+}
+
+func TestDatasetUtils_WriteJSONLRoundTrip(t *testing.T) {
+	utils := DatasetUtils{}
+	original := NewDatasetBuilder().
+		Add(map[string]interface{}{"question": "What is 2 + 2?", "answer": "4", "meta": map[string]interface{}{"level": 1.0, "tags": []interface{}{"easy", "<math>"}}}).
+		Add(map[string]interface{}{"question": "Capital of France?", "answer": "Paris", "score": 0.5}).
+		Build().
+		Map(func(item map[string]interface{}) map[string]interface{} {
+			item["task"] = "qa"
+			return item
+		})
+
+	path := filepath.Join(t.TempDir(), "dataset.jsonl")
+	if err := utils.WriteJSONLFile(original, path); err != nil {
+		t.Fatalf("WriteJSONLFile failed: %v", err)
+	}
+
+	reloaded, err := utils.LoadFromJSONL(path)
+	if err != nil {
+		t.Fatalf("LoadFromJSONL failed: %v", err)
+	}
+	if reloaded.Len() != original.Len() {
+		t.Fatalf("Expected %d items, got %d", original.Len(), reloaded.Len())
+	}
+	for i := 0; i < original.Len(); i++ {
+		if !reflect.DeepEqual(reloaded.Get(i), original.Get(i)) {
+			t.Errorf("Item %d: got %v, want %v", i, reloaded.Get(i), original.Get(i))
+		}
+	}
+
+	// Keys are sorted, so the output is byte-for-byte reproducible
+	var buf strings.Builder
+	if err := utils.WriteJSONL(reloaded, &buf); err != nil {
+		t.Fatalf("WriteJSONL failed: %v", err)
+	}
+	first := strings.SplitN(buf.String(), "\n", 2)[0]
+	want := `{"answer":"4","meta":{"level":1,"tags":["easy","<math>"]},"question":"What is 2 + 2?","task":"qa"}`
+	if first != want {
+		t.Errorf("First line = %s, want %s", first, want)
+	}
+}
+
+func TestDatasetUtils_LoadFromCSVReader(t *testing.T) {
+	utils := DatasetUtils{}
+	data := "id,problem,solution,difficulty\n1,\"What is 2 + 2, exactly?\",4,easy\n2,What is 3 * 3?,9,medium\n"
+
+	dataset, err := utils.LoadFromCSVReader(strings.NewReader(data), "problem", "solution", CSVOptions{})
+	if err != nil {
+		t.Fatalf("LoadFromCSVReader failed: %v", err)
+	}
+	if dataset.Len() != 2 {
+		t.Fatalf("Expected 2 items, got %d", dataset.Len())
+	}
+
+	item := dataset.Get(0)
+	if item["question"] != "What is 2 + 2, exactly?" || item["answer"] != "4" {
+		t.Errorf("Unexpected question/answer mapping: %v", item)
+	}
+	if item["id"] != "1" || item["difficulty"] != "easy" {
+		t.Errorf("Expected extra columns to be preserved: %v", item)
+	}
+	if _, ok := item["problem"]; ok {
+		t.Errorf("Mapped column should not be duplicated: %v", item)
+	}
+
+	// Missing column
+	_, err = utils.LoadFromCSVReader(strings.NewReader(data), "question", "solution", CSVOptions{})
+	if err == nil || !strings.Contains(err.Error(), "question") {
+		t.Errorf("Expected missing column error, got %v", err)
+	}
+
+	// Custom delimiter with header detection on headerless data
+	tsv := "What is 1 + 1?\t2\nWhat is 5 - 3?\t2\n"
+	dataset, err = utils.LoadFromCSVReader(strings.NewReader(tsv), "col0", "col1", CSVOptions{Delimiter: '\t', Header: CSVHeaderDetect})
+	if err != nil {
+		t.Fatalf("LoadFromCSVReader failed: %v", err)
+	}
+	if dataset.Len() != 2 || dataset.Get(0)["question"] != "What is 1 + 1?" {
+		t.Errorf("Expected headerless rows to be read as data, got %d items", dataset.Len())
+	}
+}
+
+func TestDatasetUtils_TrainTestSplit(t *testing.T) {
+	builder := NewDatasetBuilder()
+	for i := 0; i < 100; i++ {
+		builder.Add(map[string]interface{}{"id": i})
+	}
+	dataset := builder.Build()
+	utils := DatasetUtils{}
+
+	train, test, err := utils.TrainTestSplit(dataset, 0.2, 42)
+	if err != nil {
+		t.Fatalf("TrainTestSplit failed: %v", err)
+	}
+	if train.Len()+test.Len() != dataset.Len() || test.Len() != 20 {
+		t.Fatalf("Unexpected sizes: train=%d test=%d", train.Len(), test.Len())
+	}
+
+	seen := make(map[interface{}]bool)
+	for _, split := range []Dataset{train, test} {
+		for i := 0; i < split.Len(); i++ {
+			id := split.Get(i)["id"]
+			if seen[id] {
+				t.Fatalf("Item %v appears in both splits", id)
+			}
+			seen[id] = true
+		}
+	}
+
+	_, testAgain, err := utils.TrainTestSplit(dataset, 0.2, 42)
+	if err != nil {
+		t.Fatalf("TrainTestSplit failed: %v", err)
+	}
+	for i := 0; i < test.Len(); i++ {
+		if test.Get(i)["id"] != testAgain.Get(i)["id"] {
+			t.Fatalf("Same seed produced different splits at index %d", i)
+		}
+	}
+
+	for _, fraction := range []float64{0, 1, -0.5} {
+		if _, _, err := utils.TrainTestSplit(dataset, fraction, 42); err == nil {
+			t.Errorf("Expected error for test fraction %v", fraction)
+		}
+	}
+}
+
+func TestSimpleDataset_MapErr(t *testing.T) {
+	dataset := NewSimpleDataset([]map[string]interface{}{
+		{"answer": "\\boxed{1}"},
+		{"answer": "\\boxed{2}"},
+		{"answer": "3"},
+		{"answer": "\\boxed{4}"},
+	})
+	extractBoxed := func(item map[string]interface{}) (map[string]interface{}, error) {
+		answer, _ := item["answer"].(string)
+		if !strings.HasPrefix(answer, "\\boxed{") {
+			return nil, fmt.Errorf("no boxed answer in %q", answer)
+		}
+		item["answer"] = strings.TrimSuffix(strings.TrimPrefix(answer, "\\boxed{"), "}")
+		return item, nil
+	}
+
+	_, err := dataset.MapErr(extractBoxed)
+	if err == nil || !strings.Contains(err.Error(), "item 2") {
+		t.Fatalf("Expected error reporting item 2, got %v", err)
+	}
+
+	mapped, err := dataset.Select([]int{0, 1, 3}).MapErr(extractBoxed)
+	if err != nil {
+		t.Fatalf("MapErr failed: %v", err)
+	}
+	if mapped.Len() != 3 || mapped.Get(2)["answer"] != "4" {
+		t.Errorf("Unexpected mapped dataset: %v", mapped.Get(2))
+	}
+	if dataset.Get(0)["answer"] != "\\boxed{1}" {
+		t.Errorf("MapErr modified the source dataset: %v", dataset.Get(0))
+	}
+}
+
+func TestSimpleDataset_ShuffleIndices(t *testing.T) {
+	data := make([]map[string]interface{}, 50)
+	for i := range data {
+		data[i] = map[string]interface{}{"id": i}
+	}
+	dataset := NewSimpleDataset(data)
+
+	indices := dataset.ShuffleIndices(7)
+	again := dataset.ShuffleIndices(7)
+	seen := make(map[int]bool)
+	for i, idx := range indices {
+		if idx != again[i] {
+			t.Fatalf("Same seed produced different permutations at position %d", i)
+		}
+		seen[idx] = true
+	}
+	if len(indices) != dataset.Len() || len(seen) != dataset.Len() {
+		t.Fatalf("Expected a permutation of %d indices, got %v", dataset.Len(), indices)
+	}
+
+	// The permutation matches the order Shuffle produces for the same seed
+	shuffled := dataset.Shuffle(7)
+	for i, idx := range indices {
+		if shuffled.Get(i)["id"] != idx {
+			t.Fatalf("Shuffle and ShuffleIndices disagree at position %d", i)
+		}
+	}
+}
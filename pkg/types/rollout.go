@@ -0,0 +1,48 @@
+package types
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// MarshalJSONL encodes the rollout as a single JSON line terminated by a newline,
+// so rollouts can be appended to a JSONL file and reloaded with ParseRolloutJSONL
+func (r Rollout) MarshalJSONL() ([]byte, error) {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal rollout: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// ParseRolloutJSONL reads rollouts written by MarshalJSONL, one per line.
+// Blank lines are skipped; malformed lines return an error with the line number.
+// State values are decoded as generic JSON values.
+func ParseRolloutJSONL(r io.Reader) ([]Rollout, error) {
+	rollouts := make([]Rollout, 0)
+	reader := bufio.NewReader(r)
+
+	for lineNum := 1; ; lineNum++ {
+		line, err := reader.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return nil, fmt.Errorf("line %d: failed to read: %w", lineNum, err)
+		}
+
+		if trimmed := bytes.TrimSpace(line); len(trimmed) > 0 {
+			var rollout Rollout
+			if jsonErr := json.Unmarshal(trimmed, &rollout); jsonErr != nil {
+				return nil, fmt.Errorf("line %d: invalid rollout: %w", lineNum, jsonErr)
+			}
+			rollouts = append(rollouts, rollout)
+		}
+
+		if err == io.EOF {
+			break
+		}
+	}
+
+	return rollouts, nil
+}
@@ -0,0 +1,85 @@
+package types
+
+import (
+	"reflect"
+	"sync"
+)
+
+// State is the mutable state of a multi-turn rollout, passed to IsCompleted and
+// EnvResponse on every turn. Its methods are safe for concurrent use, so an
+// environment may update it from several goroutines, e.g. while running tool
+// calls in parallel. The zero value is an empty state ready to use.
+type State struct {
+	mu     sync.RWMutex
+	values map[string]interface{}
+}
+
+// NewState creates a state holding a copy of values, which may be nil
+func NewState(values map[string]interface{}) *State {
+	s := &State{values: make(map[string]interface{}, len(values))}
+	for k, v := range values {
+		s.values[k] = v
+	}
+	return s
+}
+
+// Get returns the value stored under key
+func (s *State) Get(key string) (interface{}, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	value, ok := s.values[key]
+	return value, ok
+}
+
+// Set stores value under key
+func (s *State) Set(key string, value interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.values == nil {
+		s.values = make(map[string]interface{})
+	}
+	s.values[key] = value
+}
+
+// Update atomically replaces the value under key with fn applied to the current
+// value, which is nil when key is unset. Use it for read-modify-write changes
+// such as counters; fn must not call back into the state.
+func (s *State) Update(key string, fn func(current interface{}) interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.values == nil {
+		s.values = make(map[string]interface{})
+	}
+	s.values[key] = fn(s.values[key])
+}
+
+// Append atomically appends value to the slice stored under key. The slice keeps
+// its element type, so appending ToolExecution values builds a []ToolExecution.
+// When key is unset, or holds something that value cannot be appended to, a new
+// slice of value's type replaces it.
+func (s *State) Append(key string, value interface{}) {
+	s.Update(key, func(current interface{}) interface{} {
+		item := reflect.ValueOf(value)
+		if !item.IsValid() {
+			item = reflect.Zero(reflect.TypeOf((*interface{})(nil)).Elem())
+		}
+
+		slice := reflect.ValueOf(current)
+		if slice.Kind() != reflect.Slice || !item.Type().AssignableTo(slice.Type().Elem()) {
+			slice = reflect.MakeSlice(reflect.SliceOf(item.Type()), 0, 1)
+		}
+		return reflect.Append(slice, item).Interface()
+	})
+}
+
+// Map returns a shallow copy of the state as a plain map, e.g. for
+// Rollout.State or code written against map-based state
+func (s *State) Map() map[string]interface{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	values := make(map[string]interface{}, len(s.values))
+	for k, v := range s.values {
+		values[k] = v
+	}
+	return values
+}
@@ -0,0 +1,51 @@
+package types
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestState(t *testing.T) {
+	values := map[string]interface{}{"answer": "4"}
+	state := NewState(values)
+	values["answer"] = "changed"
+
+	if answer, ok := state.Get("answer"); !ok || answer != "4" {
+		t.Errorf("Get(answer) = %v, %v, want 4, true", answer, ok)
+	}
+	if _, ok := state.Get("missing"); ok {
+		t.Error("Expected missing key to be reported as unset")
+	}
+
+	state.Append("steps", 1)
+	state.Append("steps", 2)
+	state.Set("label", "x")
+	state.Append("label", "y")
+	state.Update("count", func(current interface{}) interface{} {
+		count, _ := current.(int)
+		return count + 1
+	})
+
+	expected := map[string]interface{}{
+		"answer": "4",
+		"steps":  []int{1, 2},
+		"label":  []string{"y"},
+		"count":  1,
+	}
+	snapshot := state.Map()
+	if !reflect.DeepEqual(snapshot, expected) {
+		t.Errorf("Map() = %v, want %v", snapshot, expected)
+	}
+
+	// The snapshot is a copy
+	snapshot["answer"] = "changed"
+	if answer, _ := state.Get("answer"); answer != "4" {
+		t.Errorf("Expected Map() to return a copy, state now has answer %v", answer)
+	}
+
+	var zero State
+	zero.Append("items", "a")
+	if items, _ := zero.Get("items"); !reflect.DeepEqual(items, []string{"a"}) {
+		t.Errorf("Expected zero State to be usable, got %v", items)
+	}
+}
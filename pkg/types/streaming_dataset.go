@@ -0,0 +1,327 @@
+package types
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// StreamingDataset reads JSONL items lazily from disk.
+//
+// Opening the dataset pre-scans the file once to record where each non-blank line
+// starts, so Len is exact and Get reads and decodes a single line on demand. Only
+// the line offsets are kept in memory, never the items themselves. Map is applied
+// lazily on Get. Shuffle and Select return in-memory SimpleDatasets: Select buffers
+// only the selected items, while Shuffle buffers the entire file and should be
+// avoided for very large datasets.
+//
+// Get is safe for concurrent use. Because the Dataset interface cannot report
+// errors from Get, a line that fails to read or decode yields nil and the first
+// such error is available from Err.
+type StreamingDataset struct {
+	file       *os.File
+	offsets    []int64
+	lengths    []int
+	transforms []func(map[string]interface{}) map[string]interface{}
+	state      *streamState
+}
+
+// streamState is shared between a dataset and the lazily mapped views derived from it
+type streamState struct {
+	mu  sync.Mutex
+	err error
+}
+
+// NewStreamingDataset opens a JSONL file for lazy access
+func NewStreamingDataset(path string) (*StreamingDataset, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open dataset: %w", err)
+	}
+
+	dataset := &StreamingDataset{
+		file:  file,
+		state: &streamState{},
+	}
+
+	if err := dataset.scan(); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return dataset, nil
+}
+
+// scan records the offset and length of every non-blank line
+func (d *StreamingDataset) scan() error {
+	reader := bufio.NewReaderSize(d.file, 64*1024)
+
+	var offset, lineStart int64
+	lineLen := 0
+	blank := true
+
+	for {
+		chunk, err := reader.ReadSlice('\n')
+		if len(bytes.TrimSpace(chunk)) > 0 {
+			blank = false
+		}
+		lineLen += len(chunk)
+		offset += int64(len(chunk))
+
+		// Lines longer than the buffer arrive in several chunks
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+
+		if !blank {
+			d.offsets = append(d.offsets, lineStart)
+			d.lengths = append(d.lengths, lineLen)
+		}
+		lineStart = offset
+		lineLen = 0
+		blank = true
+
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to scan dataset: %w", err)
+		}
+	}
+}
+
+// Len returns the number of items in the dataset
+func (d *StreamingDataset) Len() int {
+	return len(d.offsets)
+}
+
+// Get reads and decodes the item at the specified index
+func (d *StreamingDataset) Get(idx int) map[string]interface{} {
+	if idx < 0 || idx >= len(d.offsets) {
+		return nil
+	}
+
+	item, err := d.read(idx)
+	if err != nil {
+		d.setErr(err)
+		return nil
+	}
+
+	for _, fn := range d.transforms {
+		item = fn(item)
+	}
+	return item
+}
+
+// read decodes the raw item at idx without applying transforms
+func (d *StreamingDataset) read(idx int) (map[string]interface{}, error) {
+	buf := make([]byte, d.lengths[idx])
+	if _, err := d.file.ReadAt(buf, d.offsets[idx]); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("item %d: failed to read: %w", idx, err)
+	}
+
+	var item map[string]interface{}
+	if err := json.Unmarshal(buf, &item); err != nil {
+		return nil, fmt.Errorf("item %d: invalid JSON: %w", idx, err)
+	}
+	return item, nil
+}
+
+// Shuffle loads every item into memory and returns a shuffled SimpleDataset
+func (d *StreamingDataset) Shuffle(seed int64) Dataset {
+	return d.Select(makeIndices(d.Len())).Shuffle(seed)
+}
+
+// ShuffleIndices returns a seeded permutation of the item indices without reading
+// any items, so a sample can be loaded with Select(indices[:n])
+func (d *StreamingDataset) ShuffleIndices(seed int64) []int {
+	return shuffledIndices(d.Len(), seed)
+}
+
+// Select loads the specified items into memory and returns them as a SimpleDataset.
+// Out-of-range indices are skipped, as in SimpleDataset. An item that fails to read
+// or decode is kept as a nil placeholder, so positions stay aligned with indices;
+// Get returns nil for it and the error is available from Err.
+func (d *StreamingDataset) Select(indices []int) Dataset {
+	data := make([]map[string]interface{}, 0, len(indices))
+	for _, idx := range indices {
+		if idx < 0 || idx >= d.Len() {
+			continue
+		}
+		data = append(data, d.Get(idx))
+	}
+	return NewSimpleDataset(data)
+}
+
+// Map returns a view of the dataset that applies fn lazily on each Get.
+// The view shares the underlying file with d.
+func (d *StreamingDataset) Map(fn func(map[string]interface{}) map[string]interface{}) Dataset {
+	transforms := make([]func(map[string]interface{}) map[string]interface{}, 0, len(d.transforms)+1)
+	transforms = append(transforms, d.transforms...)
+	transforms = append(transforms, fn)
+
+	return &StreamingDataset{
+		file:       d.file,
+		offsets:    d.offsets,
+		lengths:    d.lengths,
+		transforms: transforms,
+		state:      d.state,
+	}
+}
+
+// MapErr loads every item into memory, applies fn and returns the results as a
+// SimpleDataset. It stops at the first read or transform error, which is wrapped
+// with the offending item's index.
+func (d *StreamingDataset) MapErr(fn func(map[string]interface{}) (map[string]interface{}, error)) (Dataset, error) {
+	data := make([]map[string]interface{}, 0, d.Len())
+	for idx := 0; idx < d.Len(); idx++ {
+		item, err := d.read(idx)
+		if err != nil {
+			return nil, err
+		}
+		for _, transform := range d.transforms {
+			item = transform(item)
+		}
+
+		mapped, err := fn(item)
+		if err != nil {
+			return nil, fmt.Errorf("item %d: %w", idx, err)
+		}
+		data = append(data, mapped)
+	}
+	return NewSimpleDataset(data), nil
+}
+
+// Err returns the first error encountered while reading items
+func (d *StreamingDataset) Err() error {
+	d.state.mu.Lock()
+	defer d.state.mu.Unlock()
+	return d.state.err
+}
+
+// Close closes the underlying file, invalidating the dataset and any mapped views
+func (d *StreamingDataset) Close() error {
+	return d.file.Close()
+}
+
+// setErr records the first read error
+func (d *StreamingDataset) setErr(err error) {
+	d.state.mu.Lock()
+	defer d.state.mu.Unlock()
+	if d.state.err == nil {
+		d.state.err = err
+	}
+}
+
+// makeIndices returns the indices 0..n-1
+func makeIndices(n int) []int {
+	indices := make([]int, n)
+	for i := range indices {
+		indices[i] = i
+	}
+	return indices
+}
+
+// ErrUnsupportedOperation is returned by streaming datasets for operations that
+// need random access to every item
+var ErrUnsupportedOperation = errors.New("operation not supported on a streaming dataset")
+
+// JSONLStreamDataset reads JSONL items one at a time from a file.
+//
+// It implements IterableDataset and never holds more than one item in memory.
+// Shuffle and Select require random access and always return
+// ErrUnsupportedOperation; use Materialize to load a bounded prefix into a
+// SimpleDataset, or StreamingDataset for random access without buffering.
+type JSONLStreamDataset struct {
+	file    *os.File
+	reader  *bufio.Reader
+	lineNum int
+	err     error
+}
+
+// NewJSONLStreamDataset opens a JSONL file for sequential reading
+func NewJSONLStreamDataset(path string) (*JSONLStreamDataset, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open dataset: %w", err)
+	}
+
+	return &JSONLStreamDataset{
+		file:   file,
+		reader: bufio.NewReader(file),
+	}, nil
+}
+
+// Next returns the next item, skipping blank lines. It returns false at the end
+// of the file or on the first read or decode error, which is available from Err.
+func (d *JSONLStreamDataset) Next() (map[string]interface{}, bool) {
+	for d.err == nil {
+		line, err := d.reader.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			d.err = fmt.Errorf("line %d: failed to read: %w", d.lineNum+1, err)
+			return nil, false
+		}
+		if len(line) > 0 {
+			d.lineNum++
+		}
+
+		if trimmed := bytes.TrimSpace(line); len(trimmed) > 0 {
+			var item map[string]interface{}
+			if jsonErr := json.Unmarshal(trimmed, &item); jsonErr != nil {
+				d.err = fmt.Errorf("line %d: invalid JSON: %w", d.lineNum, jsonErr)
+				return nil, false
+			}
+			return item, true
+		}
+
+		if err == io.EOF {
+			return nil, false
+		}
+	}
+	return nil, false
+}
+
+// Err returns the error that stopped iteration, if any
+func (d *JSONLStreamDataset) Err() error {
+	return d.err
+}
+
+// Shuffle is not supported on a streaming dataset
+func (d *JSONLStreamDataset) Shuffle(seed int64) (Dataset, error) {
+	return nil, fmt.Errorf("shuffle: %w", ErrUnsupportedOperation)
+}
+
+// Select is not supported on a streaming dataset
+func (d *JSONLStreamDataset) Select(indices []int) (Dataset, error) {
+	return nil, fmt.Errorf("select: %w", ErrUnsupportedOperation)
+}
+
+// Close closes the underlying file
+func (d *JSONLStreamDataset) Close() error {
+	return d.file.Close()
+}
+
+// Materialize reads up to limit items from iter into a SimpleDataset, so streaming
+// sources can be used where random access is required. A limit of zero or less
+// reads every remaining item. If iter reports an error through an Err method, as
+// JSONLStreamDataset does, a stop caused by that error is returned instead of
+// the incomplete dataset.
+func Materialize(iter IterableDataset, limit int) (Dataset, error) {
+	data := make([]map[string]interface{}, 0)
+	for limit <= 0 || len(data) < limit {
+		item, ok := iter.Next()
+		if !ok {
+			if errIter, ok := iter.(interface{ Err() error }); ok && errIter.Err() != nil {
+				return nil, fmt.Errorf("failed to materialize item %d: %w", len(data), errIter.Err())
+			}
+			break
+		}
+		data = append(data, item)
+	}
+	return NewSimpleDataset(data), nil
+}
@@ -0,0 +1,182 @@
+package types
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestStreamingDataset_LargeFile(t *testing.T) {
+	const numItems = 50000
+
+	path := filepath.Join(t.TempDir(), "large.jsonl")
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+	writer := bufio.NewWriter(file)
+	for i := 0; i < numItems; i++ {
+		fmt.Fprintf(writer, "{\"question\": \"What is %d + %d?\", \"answer\": \"%d\"}\n", i, i, 2*i)
+		if i%1000 == 0 {
+			writer.WriteString("\n") // Blank lines are skipped
+		}
+	}
+	// A line longer than the read buffer
+	fmt.Fprintf(writer, "{\"question\": \"%s\", \"answer\": \"long\"}", strings.Repeat("x", 100000))
+	writer.Flush()
+	file.Close()
+
+	dataset, err := NewStreamingDataset(path)
+	if err != nil {
+		t.Fatalf("NewStreamingDataset failed: %v", err)
+	}
+	defer dataset.Close()
+
+	if dataset.Len() != numItems+1 {
+		t.Fatalf("Expected %d items, got %d", numItems+1, dataset.Len())
+	}
+
+	if item := dataset.Get(12345); item["answer"] != "24690" {
+		t.Errorf("Get(12345) = %v", item)
+	}
+	if item := dataset.Get(numItems); item["answer"] != "long" {
+		t.Errorf("Expected long line to be read, got answer %v", item["answer"])
+	}
+	if item := dataset.Get(numItems + 1); item != nil {
+		t.Errorf("Expected nil for out-of-range index")
+	}
+
+	// Sequential iteration without materializing the dataset
+	count := 0
+	for i := 0; i < dataset.Len(); i++ {
+		if dataset.Get(i) != nil {
+			count++
+		}
+	}
+	if count != dataset.Len() || dataset.Err() != nil {
+		t.Errorf("Iterated %d items, err = %v", count, dataset.Err())
+	}
+
+	// Map is applied lazily
+	mapped := dataset.Map(func(item map[string]interface{}) map[string]interface{} {
+		item["mapped"] = true
+		return item
+	})
+	if item := mapped.Get(1); item["mapped"] != true || item["answer"] != "2" {
+		t.Errorf("Mapped Get(1) = %v", item)
+	}
+
+	// Select buffers only the chosen items
+	selected := dataset.Select([]int{0, 2, 4})
+	if selected.Len() != 3 || selected.Get(2)["answer"] != "8" {
+		t.Errorf("Select returned unexpected items")
+	}
+}
+
+func TestStreamingDataset_MalformedLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bad.jsonl")
+	content := "{\"answer\": \"1\"}\nnot json\n{\"answer\": \"3\"}\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	dataset, err := NewStreamingDataset(path)
+	if err != nil {
+		t.Fatalf("NewStreamingDataset failed: %v", err)
+	}
+	defer dataset.Close()
+
+	if dataset.Get(1) != nil {
+		t.Errorf("Expected nil for malformed line")
+	}
+	if dataset.Err() == nil {
+		t.Errorf("Expected Err to report malformed line")
+	}
+	if dataset.Get(2)["answer"] != "3" {
+		t.Errorf("Expected later lines to remain readable")
+	}
+
+	// Select keeps positions aligned with the requested indices
+	selected := dataset.Select([]int{0, 1, 2})
+	if selected.Len() != 3 {
+		t.Fatalf("Expected Select to keep all 3 positions, got %d", selected.Len())
+	}
+	if selected.Get(0)["answer"] != "1" || selected.Get(1) != nil || selected.Get(2)["answer"] != "3" {
+		t.Errorf("Expected a nil placeholder at the malformed position, got %v, %v, %v", selected.Get(0), selected.Get(1), selected.Get(2))
+	}
+	if mapped := selected.Map(func(item map[string]interface{}) map[string]interface{} { return item }); mapped.Get(1) != nil {
+		t.Errorf("Expected Map to keep the placeholder, got %v", mapped.Get(1))
+	}
+	if _, err := selected.MapErr(func(item map[string]interface{}) (map[string]interface{}, error) { return item, nil }); err == nil {
+		t.Errorf("Expected MapErr to fail on the placeholder")
+	}
+}
+
+func TestJSONLStreamDataset(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stream.jsonl")
+	content := "{\"answer\": \"1\"}\n\n{\"answer\": \"2\"}\n{\"answer\": \"3\"}\n{\"answer\": \"4\"}"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	stream, err := NewJSONLStreamDataset(path)
+	if err != nil {
+		t.Fatalf("NewJSONLStreamDataset failed: %v", err)
+	}
+	defer stream.Close()
+
+	if _, err := stream.Shuffle(1); !errors.Is(err, ErrUnsupportedOperation) {
+		t.Errorf("Expected ErrUnsupportedOperation from Shuffle, got %v", err)
+	}
+	if _, err := stream.Select([]int{0}); !errors.Is(err, ErrUnsupportedOperation) {
+		t.Errorf("Expected ErrUnsupportedOperation from Select, got %v", err)
+	}
+
+	prefix, err := Materialize(stream, 2)
+	if err != nil {
+		t.Fatalf("Materialize(2) failed: %v", err)
+	}
+	if prefix.Len() != 2 || prefix.Get(1)["answer"] != "2" {
+		t.Fatalf("Materialize(2) returned unexpected items")
+	}
+
+	// The cursor continues after the materialized prefix
+	rest, err := Materialize(stream, 0)
+	if err != nil {
+		t.Fatalf("Materialize(0) failed: %v", err)
+	}
+	if rest.Len() != 2 || rest.Get(1)["answer"] != "4" {
+		t.Errorf("Expected remaining 2 items, got %d", rest.Len())
+	}
+	if _, ok := stream.Next(); ok || stream.Err() != nil {
+		t.Errorf("Expected clean end of stream, err = %v", stream.Err())
+	}
+}
+
+func TestJSONLStreamDataset_MalformedLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bad.jsonl")
+	if err := os.WriteFile(path, []byte("{\"answer\": \"1\"}\n\nnot json\n"), 0o644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	stream, err := NewJSONLStreamDataset(path)
+	if err != nil {
+		t.Fatalf("NewJSONLStreamDataset failed: %v", err)
+	}
+	defer stream.Close()
+
+	dataset, err := Materialize(stream, 0)
+	if dataset != nil {
+		t.Errorf("Expected no dataset from a stream that stopped on an error, got %d items", dataset.Len())
+	}
+	if err == nil || !strings.Contains(err.Error(), "line 3") {
+		t.Errorf("Expected Materialize to report the error for line 3, got %v", err)
+	}
+	if stream.Err() == nil || !strings.Contains(stream.Err().Error(), "line 3") {
+		t.Errorf("Expected error for line 3, got %v", stream.Err())
+	}
+}
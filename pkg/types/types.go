@@ -2,13 +2,18 @@ package types
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"sync"
 	"time"
 )
 
 // Message represents a chat message
 type Message struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
+	Role       string     `json:"role"`
+	Content    string     `json:"content"`
+	ToolCalls  []ToolCall `json:"tool_calls,omitempty"`   // Native tool calls requested by an assistant message
+	ToolCallID string     `json:"tool_call_id,omitempty"` // Call answered by a "tool" message
 }
 
 // SamplingArgs contains parameters for model sampling
@@ -21,6 +26,36 @@ type SamplingArgs struct {
 	PresencePenalty   float64                `json:"presence_penalty,omitempty"`
 	Stop              []string               `json:"stop,omitempty"`
 	ExtraBody         map[string]interface{} `json:"extra_body,omitempty"`
+	Tools             []ToolDefinition       `json:"tools,omitempty"`       // Tools offered for native tool calling
+	ToolChoice        interface{}            `json:"tool_choice,omitempty"` // "auto", "none", "required" or a specific function
+
+	// Seed requests deterministic sampling. Whether reruns are reproducible depends
+	// on the server; some backends ignore it or only make a best effort.
+	Seed *int `json:"seed,omitempty"`
+}
+
+// Clone returns a copy of a that shares no slices, maps or pointers with it, so
+// changes to either leave the other untouched. ExtraBody values are copied
+// shallowly.
+func (a SamplingArgs) Clone() *SamplingArgs {
+	if a.Stop != nil {
+		a.Stop = append([]string{}, a.Stop...)
+	}
+	if a.ExtraBody != nil {
+		extra := make(map[string]interface{}, len(a.ExtraBody))
+		for k, v := range a.ExtraBody {
+			extra[k] = v
+		}
+		a.ExtraBody = extra
+	}
+	if a.Tools != nil {
+		a.Tools = append([]ToolDefinition{}, a.Tools...)
+	}
+	if a.Seed != nil {
+		seed := *a.Seed
+		a.Seed = &seed
+	}
+	return &a
 }
 
 // Dataset represents a collection of data items
@@ -30,6 +65,13 @@ type Dataset interface {
 	Shuffle(seed int64) Dataset
 	Select(indices []int) Dataset
 	Map(fn func(map[string]interface{}) map[string]interface{}) Dataset
+	MapErr(fn func(map[string]interface{}) (map[string]interface{}, error)) (Dataset, error)
+}
+
+// IterableDataset is a forward-only cursor over dataset items, for corpora too
+// large to hold in memory. Next returns false when the items are exhausted.
+type IterableDataset interface {
+	Next() (map[string]interface{}, bool)
 }
 
 // RewardFunc represents a function that calculates rewards
@@ -37,16 +79,137 @@ type RewardFunc func(context.Context, string, string) (float64, error)
 
 // Rollout represents the result of an environment rollout
 type Rollout struct {
-	Messages []Message `json:"messages"`
-	Response string    `json:"response"`
-	Score    float64   `json:"score"`
+	Messages   []Message              `json:"messages"`
+	Response   string                 `json:"response"`
+	Score      float64                `json:"score"`
+	Metrics    map[string]float64     `json:"metrics,omitempty"`    // Per-metric raw scores, when the rubric reports them
+	State      map[string]interface{} `json:"state,omitempty"`      // Final multi-turn state, e.g. tool and code executions
+	Terminated TerminationReason      `json:"terminated,omitempty"` // Why a multi-turn rollout stopped
+	Error      *ModelError            `json:"error,omitempty"`      // Set when the model returned an "[ERROR] ..." response
+	Model      string                 `json:"model,omitempty"`      // Model that produced the rollout, for cost accounting
+	Usage      *Usage                 `json:"usage,omitempty"`      // Token counts of the rollout's model calls, when the client reports them with AddUsage
+
+	// SamplingArgs are the sampling args the rollout was run with. Multi-turn
+	// environments may adjust them per turn; this records the rollout's base args.
+	SamplingArgs *SamplingArgs `json:"sampling_args,omitempty"`
+
+	// SystemPrompt is the environment's system prompt when Messages does not carry
+	// it, as in single-turn completion mode, where prompts are sent as is and the
+	// system prompt is never shown to the model.
+	SystemPrompt string `json:"system_prompt,omitempty"`
+}
+
+// Usage holds the token counts of one or more model calls
+type Usage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+// usageKey is the context key for the usage tracker of WithUsageTracking
+type usageKey struct{}
+
+// usageTracker sums the usage reported with AddUsage. Usage is also added to the
+// parent tracker, so a rollout that runs another rollout counts both.
+type usageTracker struct {
+	mu       sync.Mutex
+	usage    Usage
+	reported bool
+	parent   *usageTracker
+}
+
+// WithUsageTracking returns a context in which AddUsage records token usage, and
+// a function returning the usage recorded so far, or nil if none was. Environments
+// use it to set Rollout.Usage from the usage their client reports.
+func WithUsageTracking(ctx context.Context) (context.Context, func() *Usage) {
+	parent, _ := ctx.Value(usageKey{}).(*usageTracker)
+	tracker := &usageTracker{parent: parent}
+	snapshot := func() *Usage {
+		tracker.mu.Lock()
+		defer tracker.mu.Unlock()
+		if !tracker.reported {
+			return nil
+		}
+		usage := tracker.usage
+		return &usage
+	}
+	return context.WithValue(ctx, usageKey{}, tracker), snapshot
+}
+
+// AddUsage records the usage of one model call in the trackers of ctx. Clients
+// that receive token counts call it once per request; without a tracker it does
+// nothing.
+func AddUsage(ctx context.Context, usage Usage) {
+	tracker, _ := ctx.Value(usageKey{}).(*usageTracker)
+	for ; tracker != nil; tracker = tracker.parent {
+		tracker.mu.Lock()
+		tracker.usage.PromptTokens += usage.PromptTokens
+		tracker.usage.CompletionTokens += usage.CompletionTokens
+		tracker.usage.TotalTokens += usage.TotalTokens
+		tracker.reported = true
+		tracker.mu.Unlock()
+	}
+}
+
+// ModelError describes an "[ERROR] ..." response, which clients return in place of
+// a completion when the request fails, e.g. because the context is too long
+type ModelError struct {
+	Reason  TerminationReason `json:"reason"`
+	Message string            `json:"message"`
+}
+
+// Error implements the error interface
+func (e *ModelError) Error() string {
+	return fmt.Sprintf("model error (%s): %s", e.Reason, e.Message)
 }
 
+// ParseModelError returns the ModelError for an "[ERROR] ..." response, and false
+// for any other response
+func ParseModelError(response string) (*ModelError, bool) {
+	if !strings.HasPrefix(response, "[ERROR]") {
+		return nil, false
+	}
+
+	reason := TerminatedError
+	if strings.Contains(response, "max_tokens") {
+		reason = TerminatedMaxTokens
+	}
+	return &ModelError{
+		Reason:  reason,
+		Message: strings.TrimSpace(strings.TrimPrefix(response, "[ERROR]")),
+	}, true
+}
+
+// TerminationReason records which exit condition ended a multi-turn rollout
+type TerminationReason string
+
+const (
+	// TerminatedCompleted means the environment reported the task as complete
+	TerminatedCompleted TerminationReason = "completed"
+	// TerminatedMaxTurns means the turn limit was reached first
+	TerminatedMaxTurns TerminationReason = "max_turns"
+	// TerminatedError means the model response was an error
+	TerminatedError TerminationReason = "error"
+	// TerminatedMaxTokens means the model response was cut off by the token limit
+	TerminatedMaxTokens TerminationReason = "max_tokens"
+)
+
+// FewShotMode controls how per-item few-shot examples combine with the environment default
+type FewShotMode string
+
+const (
+	// FewShotReplace uses the item's few-shot examples instead of the environment default
+	FewShotReplace FewShotMode = "replace"
+	// FewShotAppend places the item's few-shot examples after the environment default
+	FewShotAppend FewShotMode = "append"
+)
+
 // Config holds environment configuration
 type Config struct {
 	Model             string                 `json:"model"`
 	SystemPrompt      string                 `json:"system_prompt,omitempty"`
 	FewShot           []Message              `json:"few_shot,omitempty"`
+	FewShotMode       FewShotMode            `json:"few_shot_mode,omitempty"`
 	SamplingArgs      SamplingArgs           `json:"sampling_args"`
 	MaxConcurrent     int                    `json:"max_concurrent"`
 	MessageType       string                 `json:"message_type"`
@@ -58,4 +221,38 @@ type Config struct {
 type Client interface {
 	CreateChatCompletion(ctx context.Context, model string, messages []Message, args SamplingArgs) (string, error)
 	CreateCompletion(ctx context.Context, model string, prompt string, args SamplingArgs) (string, error)
+}
+
+// ToolCallingClient is implemented by clients that support native tool calling.
+// CreateChatCompletionWithTools returns the whole assistant message, including
+// any tool calls, instead of just its text.
+type ToolCallingClient interface {
+	Client
+	CreateChatCompletionWithTools(ctx context.Context, model string, messages []Message, args SamplingArgs) (Message, error)
+}
+
+// ToolDefinition describes a tool offered to the model, in the OpenAI "tools" format
+type ToolDefinition struct {
+	Type     string             `json:"type"` // Always "function"
+	Function FunctionDefinition `json:"function"`
+}
+
+// FunctionDefinition describes a callable function
+type FunctionDefinition struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	Parameters  map[string]interface{} `json:"parameters,omitempty"` // JSON Schema of the arguments
+}
+
+// ToolCall is a native tool call requested by the model
+type ToolCall struct {
+	ID       string       `json:"id"`
+	Type     string       `json:"type"` // Always "function"
+	Function FunctionCall `json:"function"`
+}
+
+// FunctionCall names the function to call and its JSON-encoded arguments
+type FunctionCall struct {
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
 }
\ No newline at end of file
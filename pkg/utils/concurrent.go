@@ -11,15 +11,24 @@ import (
 type BatchProcessor[T any, R any] struct {
 	maxConcurrent int
 	timeout       time.Duration
+	limiter       *RateLimiter // Optional; nil means no rate limit
 }
 
-// NewBatchProcessor creates a new batch processor
+// DefaultItemTimeout is the per-item timeout of a BatchProcessor created with a zero timeout
+const DefaultItemTimeout = 30 * time.Second
+
+// NoTimeout disables the per-item timeout of a BatchProcessor; items then run until
+// they finish or the batch context is cancelled
+const NoTimeout time.Duration = -1
+
+// NewBatchProcessor creates a new batch processor. A zero timeout uses
+// DefaultItemTimeout; NoTimeout, or any negative timeout, disables it.
 func NewBatchProcessor[T any, R any](maxConcurrent int, timeout time.Duration) *BatchProcessor[T, R] {
 	if maxConcurrent <= 0 {
 		maxConcurrent = 10
 	}
-	if timeout <= 0 {
-		timeout = 30 * time.Second
+	if timeout == 0 {
+		timeout = DefaultItemTimeout
 	}
 	
 	return &BatchProcessor[T, R]{
@@ -28,6 +37,16 @@ func NewBatchProcessor[T any, R any](maxConcurrent int, timeout time.Duration) *
 	}
 }
 
+// NewBatchProcessorWithRate creates a batch processor that also limits how many items
+// start per second. Each item waits for a token before running; rps <= 0 disables the limit.
+func NewBatchProcessorWithRate[T any, R any](maxConcurrent int, rps float64, timeout time.Duration) *BatchProcessor[T, R] {
+	processor := NewBatchProcessor[T, R](maxConcurrent, timeout)
+	if rps > 0 {
+		processor.limiter = NewRateLimiter(rps)
+	}
+	return processor
+}
+
 // ProcessResult contains the result of processing a single item
 type ProcessResult[R any] struct {
 	Index  int
@@ -35,53 +54,18 @@ type ProcessResult[R any] struct {
 	Error  error
 }
 
+// ProgressInfo describes batch progress, including throughput and an ETA
+type ProgressInfo struct {
+	Completed int
+	Total     int
+	Elapsed   time.Duration
+	Rate      float64       // Completed items per second
+	Remaining time.Duration // Estimated time until all items complete
+}
+
 // Process executes the processor function on all items concurrently
 func (b *BatchProcessor[T, R]) Process(ctx context.Context, items []T, processor func(context.Context, T) (R, error)) []ProcessResult[R] {
-	results := make([]ProcessResult[R], len(items))
-	
-	// Create semaphore for concurrency control
-	sem := make(chan struct{}, b.maxConcurrent)
-	
-	// WaitGroup to track completion
-	var wg sync.WaitGroup
-	
-	// Process each item
-	for i, item := range items {
-		wg.Add(1)
-		
-		go func(index int, item T) {
-			defer wg.Done()
-			
-			// Acquire semaphore
-			select {
-			case sem <- struct{}{}:
-				defer func() { <-sem }()
-			case <-ctx.Done():
-				results[index] = ProcessResult[R]{
-					Index: index,
-					Error: ctx.Err(),
-				}
-				return
-			}
-			
-			// Create timeout context for this item
-			itemCtx, cancel := context.WithTimeout(ctx, b.timeout)
-			defer cancel()
-			
-			// Process the item
-			result, err := processor(itemCtx, item)
-			results[index] = ProcessResult[R]{
-				Index:  index,
-				Result: result,
-				Error:  err,
-			}
-		}(i, item)
-	}
-	
-	// Wait for all items to complete
-	wg.Wait()
-	
-	return results
+	return b.run(ctx, items, processor, nil)
 }
 
 // ProcessWithProgress processes items and reports progress
@@ -91,66 +75,186 @@ func (b *BatchProcessor[T, R]) ProcessWithProgress(
 	processor func(context.Context, T) (R, error),
 	progress func(completed, total int),
 ) []ProcessResult[R] {
-	
-	results := make([]ProcessResult[R], len(items))
-	completed := 0
-	var mu sync.Mutex
-	
-	// Create semaphore for concurrency control
-	sem := make(chan struct{}, b.maxConcurrent)
-	
-	// WaitGroup to track completion
+	var onProgress func(ProgressInfo)
+	if progress != nil {
+		onProgress = func(info ProgressInfo) {
+			progress(info.Completed, info.Total)
+		}
+	}
+	return b.run(ctx, items, processor, onProgress)
+}
+
+// ProcessWithProgressInfo processes items and reports progress with timing information
+func (b *BatchProcessor[T, R]) ProcessWithProgressInfo(
+	ctx context.Context,
+	items []T,
+	processor func(context.Context, T) (R, error),
+	progress func(ProgressInfo),
+) []ProcessResult[R] {
+	return b.run(ctx, items, processor, progress)
+}
+
+// ProcessStream processes items with at most maxConcurrent in flight and emits each
+// result as soon as it completes, so callers can aggregate without waiting for the
+// whole batch. Results arrive in completion order; use Index to reorder them. The
+// channel is closed once every item has been emitted, and callers must drain it.
+func (b *BatchProcessor[T, R]) ProcessStream(ctx context.Context, items []T, processor func(context.Context, T) (R, error)) <-chan ProcessResult[R] {
+	out := make(chan ProcessResult[R], b.maxConcurrent)
+
+	workers := b.maxConcurrent
+	if workers > len(items) {
+		workers = len(items)
+	}
+
+	// Feed item indices to the workers
+	indices := make(chan int, len(items))
+	for i := range items {
+		indices <- i
+	}
+	close(indices)
+
+	// A fixed pool of workers, so the number of goroutines does not grow with the number of items
 	var wg sync.WaitGroup
-	
-	// Process each item
-	for i, item := range items {
+	for w := 0; w < workers; w++ {
 		wg.Add(1)
-		
-		go func(index int, item T) {
+
+		go func() {
 			defer wg.Done()
-			
-			// Acquire semaphore
-			select {
-			case sem <- struct{}{}:
-				defer func() { <-sem }()
-			case <-ctx.Done():
-				results[index] = ProcessResult[R]{
-					Index: index,
-					Error: ctx.Err(),
-				}
-				mu.Lock()
-				completed++
-				progress(completed, len(items))
-				mu.Unlock()
-				return
-			}
-			
-			// Create timeout context for this item
-			itemCtx, cancel := context.WithTimeout(ctx, b.timeout)
-			defer cancel()
-			
-			// Process the item
-			result, err := processor(itemCtx, item)
-			results[index] = ProcessResult[R]{
-				Index:  index,
-				Result: result,
-				Error:  err,
+
+			for index := range indices {
+				out <- b.processOne(ctx, index, items[index], processor)
 			}
-			
-			// Update progress
-			mu.Lock()
-			completed++
-			progress(completed, len(items))
-			mu.Unlock()
-		}(i, item)
+		}()
 	}
-	
-	// Wait for all items to complete
-	wg.Wait()
-	
+
+	// Close the stream once all items have completed
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+
+	return out
+}
+
+// run collects the streamed results in item order, reporting progress as they arrive
+func (b *BatchProcessor[T, R]) run(
+	ctx context.Context,
+	items []T,
+	processor func(context.Context, T) (R, error),
+	progress func(ProgressInfo),
+) []ProcessResult[R] {
+	results := make([]ProcessResult[R], len(items))
+	completed := 0
+	start := time.Now()
+
+	for result := range b.ProcessStream(ctx, items, processor) {
+		results[result.Index] = result
+		completed++
+
+		// Update progress
+		if progress != nil {
+			progress(newProgressInfo(completed, len(items), time.Since(start)))
+		}
+	}
+
 	return results
 }
 
+// newProgressInfo derives throughput and remaining time from the completed count
+func newProgressInfo(completed, total int, elapsed time.Duration) ProgressInfo {
+	info := ProgressInfo{
+		Completed: completed,
+		Total:     total,
+		Elapsed:   elapsed,
+	}
+
+	if elapsed > 0 {
+		info.Rate = float64(completed) / elapsed.Seconds()
+	}
+	if info.Rate > 0 {
+		info.Remaining = time.Duration(float64(total-completed) / info.Rate * float64(time.Second))
+	}
+
+	return info
+}
+
+// processOne runs the processor on a single item with the per-item timeout
+func (b *BatchProcessor[T, R]) processOne(ctx context.Context, index int, item T, processor func(context.Context, T) (R, error)) ProcessResult[R] {
+	// Skip remaining items once the batch is cancelled
+	if err := ctx.Err(); err != nil {
+		return ProcessResult[R]{
+			Index: index,
+			Error: err,
+		}
+	}
+
+	// Wait for a rate limit token; the wait does not count against the item timeout
+	if b.limiter != nil {
+		if err := b.limiter.Wait(ctx); err != nil {
+			return ProcessResult[R]{
+				Index: index,
+				Error: err,
+			}
+		}
+	}
+
+	// Create timeout context for this item
+	itemCtx, cancel := context.WithCancel(ctx)
+	if b.timeout > 0 {
+		itemCtx, cancel = context.WithTimeout(ctx, b.timeout)
+	}
+	defer cancel()
+
+	// Process the item
+	result, err := processor(itemCtx, item)
+	return ProcessResult[R]{
+		Index:  index,
+		Result: result,
+		Error:  err,
+	}
+}
+
+// RateLimiter is a token bucket with a burst of one: tokens are handed out at
+// evenly spaced times, interval apart. It is safe for concurrent use.
+type RateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+// NewRateLimiter creates a limiter allowing rps tokens per second; rps must be positive
+func NewRateLimiter(rps float64) *RateLimiter {
+	return &RateLimiter{
+		interval: time.Duration(float64(time.Second) / rps),
+	}
+}
+
+// Wait blocks until a token is available or the context is done
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	wait := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	l.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 // Retry implements exponential backoff retry logic
 func Retry[T any](ctx context.Context, maxRetries int, initialDelay time.Duration, fn func(context.Context) (T, error)) (T, error) {
 	var result T
@@ -0,0 +1,165 @@
+package utils
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// BenchmarkBatchProcessor_Process measures scheduling overhead for cheap items.
+// Baseline with one goroutine per item: 404896 ns/op, 112000 B/op, 1539 allocs/op.
+// With a fixed worker pool: 253023 ns/op, 86048 B/op, 1060 allocs/op.
+func BenchmarkBatchProcessor_Process(b *testing.B) {
+	items := make([]int, 256)
+	for i := range items {
+		items[i] = i
+	}
+
+	processor := NewBatchProcessor[int, int](32, time.Minute)
+	square := func(ctx context.Context, n int) (int, error) {
+		return n * n, nil
+	}
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		processor.Process(ctx, items, square)
+	}
+}
+
+func TestBatchProcessor_ProcessStream(t *testing.T) {
+	const (
+		numItems = 200
+		limit    = 8
+	)
+
+	items := make([]int, numItems)
+	for i := range items {
+		items[i] = i
+	}
+
+	var inFlight, peak atomic.Int32
+	processor := NewBatchProcessor[int, int](limit, time.Minute)
+	double := func(ctx context.Context, n int) (int, error) {
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			old := peak.Load()
+			if current <= old || peak.CompareAndSwap(old, current) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		return 2 * n, nil
+	}
+
+	seen := make(map[int]bool)
+	for result := range processor.ProcessStream(context.Background(), items, double) {
+		if seen[result.Index] {
+			t.Fatalf("Index %d emitted twice", result.Index)
+		}
+		seen[result.Index] = true
+		if result.Error != nil || result.Result != 2*items[result.Index] {
+			t.Errorf("Unexpected result for index %d: %+v", result.Index, result)
+		}
+	}
+
+	if len(seen) != numItems {
+		t.Errorf("Expected %d results, got %d", numItems, len(seen))
+	}
+	if p := peak.Load(); p > limit {
+		t.Errorf("Concurrency peaked at %d, limit is %d", p, limit)
+	}
+}
+
+func TestBatchProcessor_RateLimit(t *testing.T) {
+	const (
+		numItems = 10
+		rps      = 50.0
+	)
+
+	items := make([]int, numItems)
+	processor := NewBatchProcessorWithRate[int, int](numItems, rps, time.Minute)
+	identity := func(ctx context.Context, n int) (int, error) {
+		return n, nil
+	}
+
+	start := time.Now()
+	results := processor.Process(context.Background(), items, identity)
+	elapsed := time.Since(start)
+
+	for _, result := range results {
+		if result.Error != nil {
+			t.Fatalf("Unexpected error: %v", result.Error)
+		}
+	}
+
+	// The first token is available immediately, the rest are spaced 1/rps apart
+	minElapsed := time.Duration(float64(numItems-1) / rps * float64(time.Second))
+	if elapsed < minElapsed {
+		t.Errorf("Processed %d items in %v, expected at least %v at %v rps", numItems, elapsed, minElapsed, rps)
+	}
+}
+
+func TestBatchProcessor_RateLimitCancellation(t *testing.T) {
+	items := make([]int, 5)
+	processor := NewBatchProcessorWithRate[int, int](5, 0.5, time.Minute)
+	identity := func(ctx context.Context, n int) (int, error) {
+		return n, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	results := processor.Process(ctx, items, identity)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("Waiting for tokens ignored cancellation, took %v", elapsed)
+	}
+
+	failed := 0
+	for _, result := range results {
+		if result.Error != nil {
+			failed++
+		}
+	}
+	if failed != len(items)-1 {
+		t.Errorf("Expected %d items to fail waiting for a token, got %d", len(items)-1, failed)
+	}
+}
+
+func TestBatchProcessor_ProcessWithProgressInfo(t *testing.T) {
+	items := make([]int, 20)
+	processor := NewBatchProcessor[int, int](2, time.Minute)
+	sleepy := func(ctx context.Context, n int) (int, error) {
+		time.Sleep(2 * time.Millisecond)
+		return n, nil
+	}
+
+	var updates []ProgressInfo
+	processor.ProcessWithProgressInfo(context.Background(), items, sleepy, func(info ProgressInfo) {
+		updates = append(updates, info)
+	})
+
+	if len(updates) != len(items) {
+		t.Fatalf("Expected %d updates, got %d", len(items), len(updates))
+	}
+
+	last := updates[len(updates)-1]
+	if last.Completed != last.Total || last.Remaining != 0 {
+		t.Errorf("Final update should be complete with no time remaining: %+v", last)
+	}
+
+	// Remaining shrinks over the run, allowing for jitter in the rate estimate
+	first := updates[0]
+	if first.Remaining <= last.Remaining || first.Rate <= 0 {
+		t.Errorf("Expected Remaining to decrease: first=%+v last=%+v", first, last)
+	}
+	for i := 1; i < len(updates); i++ {
+		if updates[i].Elapsed < updates[i-1].Elapsed {
+			t.Errorf("Elapsed went backwards at update %d", i)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package utils
+
+import "github.com/rizome-dev/go-verifiers/pkg/types"
+
+// TokenPrice is the price of a model's tokens, per 1,000 tokens
+type TokenPrice struct {
+	PromptPer1K     float64
+	CompletionPer1K float64
+}
+
+// CostModel maps model names to their token prices
+type CostModel map[string]TokenPrice
+
+// Estimate returns the cost of usage on model. Models without a price cost 0.
+func (c CostModel) Estimate(usage types.Usage, model string) float64 {
+	price, ok := c[model]
+	if !ok {
+		return 0
+	}
+	return float64(usage.PromptTokens)/1000*price.PromptPer1K +
+		float64(usage.CompletionTokens)/1000*price.CompletionPer1K
+}
+
+// BatchCost returns the total estimated cost of rollouts and its breakdown by
+// model. Rollouts without usage, e.g. from clients that do not report it with
+// types.AddUsage, are skipped.
+func BatchCost(rollouts []types.Rollout, costs CostModel) (total float64, perModel map[string]float64) {
+	perModel = make(map[string]float64)
+	for _, rollout := range rollouts {
+		if rollout.Usage == nil {
+			continue
+		}
+		cost := costs.Estimate(*rollout.Usage, rollout.Model)
+		perModel[rollout.Model] += cost
+		total += cost
+	}
+	return total, perModel
+}
@@ -0,0 +1,34 @@
+package utils
+
+import (
+	"math"
+	"testing"
+
+	"github.com/rizome-dev/go-verifiers/pkg/types"
+)
+
+func TestBatchCost(t *testing.T) {
+	costs := CostModel{
+		"small": {PromptPer1K: 0.5, CompletionPer1K: 1.5},
+		"large": {PromptPer1K: 10, CompletionPer1K: 30},
+	}
+	rollouts := []types.Rollout{
+		{Model: "small", Usage: &types.Usage{PromptTokens: 2000, CompletionTokens: 1000}}, // 1.0 + 1.5
+		{Model: "small", Usage: &types.Usage{PromptTokens: 1000, CompletionTokens: 0}},    // 0.5
+		{Model: "large", Usage: &types.Usage{PromptTokens: 500, CompletionTokens: 100}},   // 5.0 + 3.0
+		{Model: "large"}, // No usage reported
+		{Model: "unpriced", Usage: &types.Usage{PromptTokens: 1000}}, // No price
+	}
+
+	total, perModel := BatchCost(rollouts, costs)
+
+	if math.Abs(total-11.0) > 1e-9 {
+		t.Errorf("Expected total 11.0, got %v", total)
+	}
+	expected := map[string]float64{"small": 3.0, "large": 8.0, "unpriced": 0}
+	for model, cost := range expected {
+		if math.Abs(perModel[model]-cost) > 1e-9 {
+			t.Errorf("Expected %s to cost %v, got %v", model, cost, perModel[model])
+		}
+	}
+}
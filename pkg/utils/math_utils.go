@@ -6,33 +6,48 @@ import (
 	"strings"
 )
 
-// ExtractBoxedAnswer extracts content from \boxed{...} format
+// ExtractBoxedAnswer extracts content from \boxed{...} format. With several
+// boxed expressions the last one wins; without a well-formed one the text is
+// returned unchanged.
 func ExtractBoxedAnswer(text string) string {
-	// Find \boxed{
-	boxedStart := strings.Index(text, "\\boxed{")
-	if boxedStart == -1 {
-		return text
-	}
+	answer, _ := ExtractBoxedAnswerOK(text)
+	return answer
+}
 
-	// Find matching brace
-	contentStart := boxedStart + 7 // len("\\boxed{")
-	count := 1
-	i := contentStart
-	
-	for i < len(text) && count > 0 {
-		if text[i] == '{' {
-			count++
-		} else if text[i] == '}' {
-			count--
+// ExtractBoxedAnswerOK extracts the content of the last \boxed{...} expression
+// and reports whether one was found. If the text has no boxed expression, or one
+// of them is missing its closing brace, it returns the text unchanged and false;
+// a false result for text containing \boxed{ therefore means malformed input.
+func ExtractBoxedAnswerOK(text string) (string, bool) {
+	const marker = "\\boxed{"
+
+	answer, found := text, false
+	rest := text
+	for {
+		boxedStart := strings.Index(rest, marker)
+		if boxedStart == -1 {
+			return answer, found
 		}
-		i++
-	}
-	
-	if count == 0 {
-		return text[contentStart : i-1]
+
+		// Find matching brace
+		contentStart := boxedStart + len(marker)
+		count := 1
+		i := contentStart
+		for i < len(rest) && count > 0 {
+			if rest[i] == '{' {
+				count++
+			} else if rest[i] == '}' {
+				count--
+			}
+			i++
+		}
+		if count != 0 {
+			return text, false
+		}
+
+		answer, found = rest[contentStart:i-1], true
+		rest = rest[i:]
 	}
-	
-	return text
 }
 
 // ExtractHashAnswer extracts answer after #### marker
@@ -75,10 +90,29 @@ func NormalizeNumber(text string) string {
 	return text
 }
 
+// trailingUnitPattern matches a number followed by a single alphabetic unit word
+var trailingUnitPattern = regexp.MustCompile(`^(.*\d)\s*[\p{L}]+\.?$`)
+
+// currencyReplacer removes common currency symbols
+var currencyReplacer = strings.NewReplacer("$", "", "€", "", "£", "", "¥", "", "₹", "")
+
+// StripUnits removes currency symbols and a trailing alphabetic unit word from a
+// numeric answer, so "42 meters" becomes "42" and "$3.50" becomes "3.50".
+// Text that does not end in a number followed by a unit is only stripped of currency symbols.
+func StripUnits(text string) string {
+	text = strings.TrimSpace(currencyReplacer.Replace(text))
+	if m := trailingUnitPattern.FindStringSubmatch(text); m != nil {
+		return strings.TrimSpace(m[1])
+	}
+	return text
+}
+
+// firstNumberPattern matches an optionally negative integer or decimal
+var firstNumberPattern = regexp.MustCompile(`-?\d+\.?\d*`)
+
 // ExtractFirstNumber extracts the first number from text
 func ExtractFirstNumber(text string) string {
-	re := regexp.MustCompile(`-?\d+\.?\d*`)
-	matches := re.FindStringSubmatch(text)
+	matches := firstNumberPattern.FindStringSubmatch(text)
 	if len(matches) > 0 {
 		return matches[0]
 	}
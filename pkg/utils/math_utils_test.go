@@ -0,0 +1,32 @@
+package utils
+
+import "testing"
+
+func TestExtractBoxedAnswerOK(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+		found    bool
+	}{
+		{"balanced", "The answer is \\boxed{42}.", "42", true},
+		{"nested braces", "So \\boxed{\\frac{1}{2}} it is", "\\frac{1}{2}", true},
+		{"no boxed expression", "The answer is 42", "The answer is 42", false},
+		{"unbalanced", "The answer is \\boxed{42", "The answer is \\boxed{42", false},
+		{"multiple returns last", "First \\boxed{41}, corrected to \\boxed{42}", "42", true},
+		{"malformed after valid", "\\boxed{41} then \\boxed{42", "\\boxed{41} then \\boxed{42", false},
+		{"empty", "\\boxed{}", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			answer, found := ExtractBoxedAnswerOK(tt.text)
+			if answer != tt.expected || found != tt.found {
+				t.Errorf("ExtractBoxedAnswerOK(%q) = %q, %v, want %q, %v", tt.text, answer, found, tt.expected, tt.found)
+			}
+			if got := ExtractBoxedAnswer(tt.text); got != tt.expected {
+				t.Errorf("ExtractBoxedAnswer(%q) = %q, want %q", tt.text, got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"math"
+	"sort"
+)
+
+// Stats summarizes a set of scores
+type Stats struct {
+	Count  int
+	Mean   float64
+	Std    float64 // Population standard deviation
+	Min    float64
+	Max    float64
+	Median float64
+}
+
+// ScoreStats computes summary statistics of scores. An empty slice returns the
+// zero Stats.
+func ScoreStats(scores []float64) Stats {
+	if len(scores) == 0 {
+		return Stats{}
+	}
+
+	sorted := append([]float64(nil), scores...)
+	sort.Float64s(sorted)
+
+	sum := 0.0
+	for _, score := range sorted {
+		sum += score
+	}
+	mean := sum / float64(len(sorted))
+
+	variance := 0.0
+	for _, score := range sorted {
+		variance += (score - mean) * (score - mean)
+	}
+	variance /= float64(len(sorted))
+
+	median := sorted[len(sorted)/2]
+	if len(sorted)%2 == 0 {
+		median = (sorted[len(sorted)/2-1] + median) / 2
+	}
+
+	return Stats{
+		Count:  len(sorted),
+		Mean:   mean,
+		Std:    math.Sqrt(variance),
+		Min:    sorted[0],
+		Max:    sorted[len(sorted)-1],
+		Median: median,
+	}
+}
+
+// PassAtK returns the fraction of problems solved by at least one of k samples.
+// Each inner slice holds the sampled scores for one problem, and a sample counts
+// as correct when its score is at least 1.0. With more than k samples per problem
+// the unbiased estimator 1 - C(n-c, k)/C(n, k) is used, where c of the n samples
+// are correct. Problems with at most k samples pass if any sample is correct.
+func PassAtK(scorePerSample [][]float64, k int) float64 {
+	if len(scorePerSample) == 0 || k <= 0 {
+		return 0.0
+	}
+
+	total := 0.0
+	for _, samples := range scorePerSample {
+		correct := 0
+		for _, score := range samples {
+			if score >= 1.0 {
+				correct++
+			}
+		}
+		total += passProbability(len(samples), correct, k)
+	}
+	return total / float64(len(scorePerSample))
+}
+
+// passProbability is the chance that k samples drawn without replacement from n,
+// of which c are correct, include at least one correct sample
+func passProbability(n, c, k int) float64 {
+	if c == 0 {
+		return 0.0
+	}
+	if n-c < k {
+		return 1.0
+	}
+
+	// C(n-c, k)/C(n, k) as a product, avoiding large binomials
+	allWrong := 1.0
+	for i := n - c + 1; i <= n; i++ {
+		allWrong *= 1.0 - float64(k)/float64(i)
+	}
+	return 1.0 - allWrong
+}
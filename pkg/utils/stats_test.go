@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"math"
+	"testing"
+)
+
+func TestScoreStats(t *testing.T) {
+	stats := ScoreStats([]float64{4, 1, 3, 2, 5, 9})
+
+	expected := Stats{Count: 6, Mean: 4, Std: math.Sqrt(40.0 / 6), Min: 1, Max: 9, Median: 3.5}
+	if math.Abs(stats.Std-expected.Std) > 1e-12 {
+		t.Errorf("Std = %v, want %v", stats.Std, expected.Std)
+	}
+	stats.Std = expected.Std
+	if stats != expected {
+		t.Errorf("ScoreStats() = %+v, want %+v", stats, expected)
+	}
+
+	if got := ScoreStats([]float64{0.5, 1, 0}); got.Median != 0.5 {
+		t.Errorf("Median of odd-length slice = %v, want 0.5", got.Median)
+	}
+	if got := ScoreStats(nil); got != (Stats{}) {
+		t.Errorf("ScoreStats(nil) = %+v, want zero Stats", got)
+	}
+}
+
+func TestPassAtK(t *testing.T) {
+	tests := []struct {
+		name     string
+		scores   [][]float64
+		k        int
+		expected float64
+	}{
+		{
+			name:     "one correct sample passes the problem",
+			scores:   [][]float64{{0, 1, 0}, {0, 0, 0}, {1, 1, 0.5}, {0.5, 0, 0}},
+			k:        3,
+			expected: 0.5,
+		},
+		{
+			name:     "pass@1 is the fraction of correct samples",
+			scores:   [][]float64{{1, 0, 0, 0}, {1, 1, 0, 0}},
+			k:        1,
+			expected: (0.25 + 0.5) / 2,
+		},
+		{
+			name:     "unbiased estimate with more samples than k",
+			scores:   [][]float64{{1, 0, 0, 0}},
+			k:        2,
+			expected: 0.5, // 1 - C(3,2)/C(4,2)
+		},
+		{name: "no problems", k: 1, expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PassAtK(tt.scores, tt.k); math.Abs(got-tt.expected) > 1e-12 {
+				t.Errorf("PassAtK() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,250 @@
+// Package verifiers assembles environments from configuration, wiring the
+// environment, parser and rubric named in Config.Extra.
+package verifiers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rizome-dev/go-verifiers/pkg/envs"
+	"github.com/rizome-dev/go-verifiers/pkg/parsers"
+	"github.com/rizome-dev/go-verifiers/pkg/rubrics"
+	"github.com/rizome-dev/go-verifiers/pkg/tools"
+	"github.com/rizome-dev/go-verifiers/pkg/types"
+)
+
+// DefaultMaxTurns is the turn limit of multi-turn environments when
+// Config.Extra["max_turns"] is not set
+const DefaultMaxTurns = 10
+
+// NewFromConfig builds an environment from config. The following keys of
+// config.Extra select and wire the pieces; all are optional:
+//
+//   - "env_type": "single_turn" (default), "tool", "codemath" or "doublecheck"
+//   - "parser": "base", "last_line", "think" or "xml" (reasoning/answer fields);
+//     defaults to "base" for single_turn and to the environment's own parser otherwise
+//   - "rubric": "exact", "math", "contains", "range" or "set" (comma-separated
+//     lists in any order); defaults to "exact" for single_turn and to the
+//     environment's own rubric otherwise
+//   - "max_turns": turn limit of the tool and codemath environments
+//   - "tools": tool names for the tool environment, "calculate" (default),
+//     "search", "wikipedia" or "read_file"
+//   - "rounds" and "verify_prompt": verification settings of doublecheck
+//   - "root_dir": sandbox directory of the read_file tool (default ".")
+//
+// Numbers may be given as int or float64, so configs decoded from JSON work as is.
+func NewFromConfig(config types.Config) (envs.Environment, error) {
+	envType, err := extraString(config.Extra, "env_type", "single_turn")
+	if err != nil {
+		return nil, err
+	}
+	maxTurns, err := extraInt(config.Extra, "max_turns", DefaultMaxTurns)
+	if err != nil {
+		return nil, err
+	}
+
+	var env configurableEnv
+	switch envType {
+	case "single_turn":
+		singleTurn := envs.NewSingleTurnEnv(config)
+		singleTurn.SetParser(parsers.NewBaseParser())
+		singleTurn.SetRubric(rubrics.NewBaseRubric())
+		env = singleTurn
+	case "tool":
+		toolList, err := buildTools(config.Extra)
+		if err != nil {
+			return nil, err
+		}
+		env, err = envs.NewToolEnv(config, toolList, maxTurns)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create tool environment: %w", err)
+		}
+	case "codemath":
+		env, err = envs.NewCodeMathEnv(config, maxTurns)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create codemath environment: %w", err)
+		}
+	case "doublecheck":
+		rounds, err := extraInt(config.Extra, "rounds", 1)
+		if err != nil {
+			return nil, err
+		}
+		verifyPrompt, err := extraString(config.Extra, "verify_prompt", "")
+		if err != nil {
+			return nil, err
+		}
+		env, err = envs.NewDoubleCheckEnvWithConfig(config, rounds, verifyPrompt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create doublecheck environment: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unknown env_type %q; expected single_turn, tool, codemath or doublecheck", envType)
+	}
+
+	// Parser and rubric overrides
+	if name, err := extraString(config.Extra, "parser", ""); err != nil {
+		return nil, err
+	} else if name != "" {
+		parser, err := buildParser(name)
+		if err != nil {
+			return nil, err
+		}
+		env.SetParser(parser)
+	}
+	if name, err := extraString(config.Extra, "rubric", ""); err != nil {
+		return nil, err
+	} else if name != "" {
+		rubric, err := buildRubric(name)
+		if err != nil {
+			return nil, err
+		}
+		env.SetRubric(rubric)
+	}
+
+	return env, nil
+}
+
+// configurableEnv is an environment whose parser and rubric can be replaced,
+// which every environment built on envs.BaseEnvironment is
+type configurableEnv interface {
+	envs.Environment
+	SetParser(parser parsers.Parser)
+	SetRubric(rubric rubrics.Rubric)
+}
+
+// buildParser returns the parser registered under name
+func buildParser(name string) (parsers.Parser, error) {
+	switch name {
+	case "base":
+		return parsers.NewBaseParser(), nil
+	case "last_line":
+		return parsers.NewLastLineParser(), nil
+	case "think":
+		return parsers.NewThinkParser(), nil
+	case "xml":
+		parser, err := parsers.NewXMLParser([]interface{}{"reasoning", "answer"}, "answer")
+		if err != nil {
+			return nil, fmt.Errorf("failed to create xml parser: %w", err)
+		}
+		return parser, nil
+	default:
+		return nil, fmt.Errorf("unknown parser %q; expected base, last_line, think or xml", name)
+	}
+}
+
+// buildRubric returns the rubric registered under name
+func buildRubric(name string) (rubrics.Rubric, error) {
+	switch name {
+	case "exact":
+		return rubrics.NewBaseRubric(), nil
+	case "math":
+		rubric, err := rubrics.NewMathRubric()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create math rubric: %w", err)
+		}
+		return rubric, nil
+	case "contains":
+		return rubrics.NewContainsAnswerRubric(nil), nil
+	case "range":
+		return rubrics.NewRangeRubric(), nil
+	case "set":
+		return rubrics.NewSetMatchRubric(",", false), nil
+	default:
+		return nil, fmt.Errorf("unknown rubric %q; expected exact, math, contains, range or set", name)
+	}
+}
+
+// toolBuilders creates the tools that can be named in Config.Extra["tools"]
+var toolBuilders = map[string]func(extra map[string]interface{}) (tools.Tool, error){
+	"calculate": func(extra map[string]interface{}) (tools.Tool, error) {
+		return tools.NewCalculator(), nil
+	},
+	"search": func(extra map[string]interface{}) (tools.Tool, error) {
+		return tools.NewWebSearch(tools.SearchEngineDuckDuckGo), nil
+	},
+	"wikipedia": func(extra map[string]interface{}) (tools.Tool, error) {
+		return tools.NewWikipediaTool(), nil
+	},
+	"read_file": func(extra map[string]interface{}) (tools.Tool, error) {
+		rootDir, err := extraString(extra, "root_dir", ".")
+		if err != nil {
+			return nil, err
+		}
+		return tools.NewFileReadTool(rootDir), nil
+	},
+}
+
+// buildTools returns the tools named in extra["tools"], or the calculator
+func buildTools(extra map[string]interface{}) ([]tools.Tool, error) {
+	names := []string{"calculate"}
+	switch v := extra["tools"].(type) {
+	case nil:
+	case []string:
+		names = v
+	case []interface{}:
+		names = make([]string, 0, len(v))
+		for _, item := range v {
+			name, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("tools must be a list of names, got %T in the list", item)
+			}
+			names = append(names, name)
+		}
+	default:
+		return nil, fmt.Errorf("tools must be a list of names, got %T", v)
+	}
+
+	toolList := make([]tools.Tool, 0, len(names))
+	for _, name := range names {
+		builder, ok := toolBuilders[name]
+		if !ok {
+			known := make([]string, 0, len(toolBuilders))
+			for toolName := range toolBuilders {
+				known = append(known, toolName)
+			}
+			sort.Strings(known)
+			return nil, fmt.Errorf("unknown tool %q; expected one of %s", name, strings.Join(known, ", "))
+		}
+		tool, err := builder(extra)
+		if err != nil {
+			return nil, err
+		}
+		toolList = append(toolList, tool)
+	}
+	return toolList, nil
+}
+
+// extraString reads a string from extra, returning defaultValue when it is unset
+func extraString(extra map[string]interface{}, key string, defaultValue string) (string, error) {
+	value, ok := extra[key]
+	if !ok || value == nil {
+		return defaultValue, nil
+	}
+	s, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("%s must be a string, got %T", key, value)
+	}
+	return s, nil
+}
+
+// extraInt reads an integer from extra, returning defaultValue when it is unset
+func extraInt(extra map[string]interface{}, key string, defaultValue int) (int, error) {
+	value, ok := extra[key]
+	if !ok || value == nil {
+		return defaultValue, nil
+	}
+	switch v := value.(type) {
+	case int:
+		return v, nil
+	case int64:
+		return int(v), nil
+	case float64:
+		if v != float64(int(v)) {
+			return 0, fmt.Errorf("%s must be a whole number, got %v", key, v)
+		}
+		return int(v), nil
+	default:
+		return 0, fmt.Errorf("%s must be a number, got %T", key, value)
+	}
+}
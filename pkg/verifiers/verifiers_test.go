@@ -0,0 +1,144 @@
+package verifiers
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rizome-dev/go-verifiers/pkg/envs"
+	"github.com/rizome-dev/go-verifiers/pkg/types"
+)
+
+// scriptedClient returns its responses in order, repeating the last one
+type scriptedClient struct {
+	responses []string
+	calls     int
+}
+
+func (c *scriptedClient) next() string {
+	idx := c.calls
+	if idx >= len(c.responses) {
+		idx = len(c.responses) - 1
+	}
+	c.calls++
+	return c.responses[idx]
+}
+
+func (c *scriptedClient) CreateChatCompletion(ctx context.Context, model string, messages []types.Message, args types.SamplingArgs) (string, error) {
+	return c.next(), nil
+}
+
+func (c *scriptedClient) CreateCompletion(ctx context.Context, model string, prompt string, args types.SamplingArgs) (string, error) {
+	return c.next(), nil
+}
+
+func TestNewFromConfig(t *testing.T) {
+	tests := []struct {
+		name      string
+		extra     map[string]interface{}
+		wantType  interface{}
+		responses []string
+	}{
+		{
+			name:      "default single turn",
+			extra:     nil,
+			wantType:  &envs.SingleTurnEnv{},
+			responses: []string{"4"},
+		},
+		{
+			name:      "tool",
+			extra:     map[string]interface{}{"env_type": "tool", "max_turns": float64(3), "tools": []interface{}{"calculate"}},
+			wantType:  &envs.ToolEnv{},
+			responses: []string{"<think>\nAdd\n</think>\n<tool>\n{\"name\": \"calculate\", \"args\": {\"expression\": \"2 + 2\"}}\n</tool>", "<think>\nDone\n</think>\n<answer>\n4\n</answer>"},
+		},
+		{
+			name:      "codemath",
+			extra:     map[string]interface{}{"env_type": "codemath"},
+			wantType:  &envs.CodeMathEnv{},
+			responses: []string{"<reasoning>\nAdd\n</reasoning>\n<answer>\n4\n</answer>"},
+		},
+		{
+			name:      "doublecheck",
+			extra:     map[string]interface{}{"env_type": "doublecheck", "rounds": 2},
+			wantType:  &envs.DoubleCheckEnv{},
+			responses: []string{"<think>\nAdd\n</think>\n<answer>\n4\n</answer>"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := types.Config{MessageType: "chat", Extra: tt.extra}
+			env, err := NewFromConfig(config)
+			if err != nil {
+				t.Fatalf("NewFromConfig failed: %v", err)
+			}
+			if reflect.TypeOf(env) != reflect.TypeOf(tt.wantType) {
+				t.Fatalf("Expected %T, got %T", tt.wantType, env)
+			}
+
+			client := &scriptedClient{responses: tt.responses}
+			rollout, err := env.Rollout(context.Background(), client, "test-model", []types.Message{{Role: "user", Content: "What is 2 + 2?"}}, "4", types.SamplingArgs{})
+			if err != nil {
+				t.Fatalf("Rollout failed: %v", err)
+			}
+			if rollout.Score <= 0 {
+				t.Errorf("Expected a positive score for a correct answer, got %v", rollout.Score)
+			}
+		})
+	}
+}
+
+func TestNewFromConfig_ParserAndRubric(t *testing.T) {
+	prompt := []types.Message{{Role: "user", Content: "What is 2 + 2?"}}
+	response := "Two plus two.\nThe answer is 4"
+
+	tests := []struct {
+		name  string
+		extra map[string]interface{}
+		want  float64
+	}{
+		{"defaults", nil, 0.0}, // The whole response is not an exact match
+		{"last line and contains", map[string]interface{}{"parser": "last_line", "rubric": "contains"}, 1.0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env, err := NewFromConfig(types.Config{MessageType: "chat", Extra: tt.extra})
+			if err != nil {
+				t.Fatalf("NewFromConfig failed: %v", err)
+			}
+			rollout, err := env.Rollout(context.Background(), &scriptedClient{responses: []string{response}}, "test-model", prompt, "4", types.SamplingArgs{})
+			if err != nil {
+				t.Fatalf("Rollout failed: %v", err)
+			}
+			if rollout.Score != tt.want {
+				t.Errorf("Expected score %v, got %v", tt.want, rollout.Score)
+			}
+		})
+	}
+}
+
+func TestNewFromConfig_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		extra   map[string]interface{}
+		wantErr string
+	}{
+		{"unknown env type", map[string]interface{}{"env_type": "arena"}, `unknown env_type "arena"`},
+		{"unknown parser", map[string]interface{}{"parser": "yaml"}, `unknown parser "yaml"`},
+		{"unknown rubric", map[string]interface{}{"rubric": "vibes"}, `unknown rubric "vibes"`},
+		{"unknown tool", map[string]interface{}{"env_type": "tool", "tools": []string{"shell"}}, `unknown tool "shell"`},
+		{"bad max turns", map[string]interface{}{"max_turns": "ten"}, "max_turns must be a number"},
+		{"bad rounds", map[string]interface{}{"env_type": "doublecheck", "rounds": 0}, "rounds must be at least 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewFromConfig(types.Config{Extra: tt.extra})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}